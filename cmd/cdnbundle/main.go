@@ -0,0 +1,35 @@
+// Command cdnbundle downloads the Data Dragon and CommunityDragon files the bot reads into a bundle
+// for CDN_BUNDLE, so the CDN sync runs without network access.
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+)
+
+func main() {
+	out := flag.String("out", "cdn-bundle.tar.gz", "bundle to write: a .tar.gz, .tgz or .tar file, otherwise a directory")
+	version := flag.String("version", "", "Data Dragon version to bundle (default: latest)")
+	images := flag.Bool("images", true, "include the champion, summoner spell and rune icons used by emoji sync")
+	dataDragonURL := flag.String("ddragon-url", cdn.DefaultDataDragonURL, "Data Dragon to download from")
+	communityDragonURL := flag.String("cdragon-url", cdn.DefaultCommunityDragonURL, "CommunityDragon to download from")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := cdn.NewClient(cdn.WithDataDragonURL(*dataDragonURL), cdn.WithCommunityDragonURL(*communityDragonURL))
+	bundled, err := client.BuildBundle(ctx, *out, *version, *images)
+	if err != nil {
+		logger.Error("cdnbundle error", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("CDN bundle written", "path", *out, "version", bundled, "images", *images)
+}
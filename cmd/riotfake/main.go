@@ -0,0 +1,63 @@
+// Command riotfake serves a local stand-in for the Riot API. Point the bot at it with
+// RIOT_API_BASE_URL=http://127.0.0.1:8089/{routing} and any well-formed RIOT_API_KEY.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot/riotfake"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8089", "address to listen on")
+	fixturesDir := flag.String("fixtures", "", "fixture directory (see riotfake.LoadFixtures)")
+	apiKey := flag.String("api-key", "", "only accept this API key (default: any)")
+	appLimit := flag.String("app-limit", riotfake.DefaultAppRateLimit, "application rate limit, e.g. 20:1,100:120")
+	methodLimit := flag.String("method-limit", riotfake.DefaultMethodRateLimit, "method rate limit, e.g. 2000:60")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	if err := run(*addr, *fixturesDir, *apiKey, *appLimit, *methodLimit, logger); err != nil {
+		logger.Error("riotfake error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(addr, fixturesDir, apiKey, appLimit, methodLimit string, logger *slog.Logger) error {
+	var fixtures riotfake.Fixtures
+	if fixturesDir != "" {
+		loaded, err := riotfake.LoadFixtures(fixturesDir)
+		if err != nil {
+			return err
+		}
+		fixtures = loaded
+	}
+	handler, err := riotfake.NewServer(fixtures, riotfake.WithAPIKey(apiKey), riotfake.WithRateLimits(appLimit, methodLimit))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Riot API stand-in listening", "addr", addr, "fixtures", fixturesDir, "accounts", len(fixtures.Accounts), "matches", len(fixtures.Matches))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
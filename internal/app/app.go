@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -27,6 +28,9 @@ const (
 	cdnSyncInterval       = 24 * time.Hour
 	riotValidationTimeout = 10 * time.Second
 	riotValidationRegion  = "br1"
+	// sharedLimitWarnInterval throttles warnings about the shared rate limit backend, which would
+	// otherwise be logged on every Riot request while the database is down.
+	sharedLimitWarnInterval = time.Minute
 )
 
 func Run(ctx context.Context, cfg config.Config) error {
@@ -49,26 +53,54 @@ func Run(ctx context.Context, cfg config.Config) error {
 		if err := db.CreateTrackTable(ctx); err != nil {
 			return fmt.Errorf("init track schema: %w", err)
 		}
+		if err := db.CreateChampionMasteryTable(ctx); err != nil {
+			return fmt.Errorf("init champion mastery schema: %w", err)
+		}
+		if err := db.CreateAccountLinkTable(ctx); err != nil {
+			return fmt.Errorf("init account link schema: %w", err)
+		}
 	}
 
 	// Setup Logger, validate Riot API and create Discord Bot
 	logger := setupLogger(cfg, db)
-	if loaded, err := riot.ConfigureRateLimitsFromFile(cfg.RateLimitCfg); err != nil {
+	limiter := riot.NewRateLimiter()
+	if loaded, err := limiter.ConfigureFromFile(cfg.RateLimitCfg); err != nil {
 		return fmt.Errorf("configure riot rate limits: %w", err)
 	} else if loaded {
 		logger.Info("Riot rate limits configured", "path", cfg.RateLimitCfg)
 	}
-	if err := validateRiotAPIKeyOnStartup(ctx, cfg.RiotAPIKey, logger); err != nil {
+	if cfg.SharedRateLimit && db != nil {
+		if err := db.CreateRiotRateLimitTable(ctx); err != nil {
+			return fmt.Errorf("init riot rate limit schema: %w", err)
+		}
+		limiter.SetBackend(&loggedLimiterBackend{backend: db, logger: logger})
+		logger.Info("Riot rate limits shared through postgres")
+	}
+	riotClient := riot.NewClient(
+		riot.WithAPIKey(cfg.RiotAPIKey),
+		riot.WithBaseURLTemplate(cfg.RiotBaseURL),
+		riot.WithRateLimiter(limiter),
+		riot.WithLogger(logger),
+	)
+	if cfg.RiotBaseURL != "" {
+		logger.Info("Riot API requests redirected", "baseURL", cfg.RiotBaseURL)
+	}
+	if err := validateRiotAPIKeyOnStartup(ctx, riotClient, cfg.RiotAPIKey, logger); err != nil {
 		return err
 	}
-	commands.ConfigureRuntime(commands.Runtime{RiotAPIKey: cfg.RiotAPIKey, Database: db})
+	commands.ConfigureRuntime(commands.Runtime{Riot: riotClient, Database: db})
 	bot, err := discord.NewBot(cfg.DiscordToken, cfg.GuildID, cfg.IsDev, discord.WithRegistry(buildRegistry()), discord.WithLogger(logger))
 	if err != nil {
 		return fmt.Errorf("create bot: %w", err)
 	}
 
+	cdnClient, err := newCDNClient(cfg, logger)
+	if err != nil {
+		return err
+	}
+
 	// Background Services
-	if err := cdn.StartAutoUpdate(ctx, cdnSyncInterval, logger); err != nil {
+	if err := cdn.StartAutoUpdate(ctx, cdnClient, cdnSyncInterval, logger); err != nil {
 		logger.Warn("Failed to init version refresher", "err", err)
 	}
 
@@ -78,20 +110,20 @@ func Run(ctx context.Context, cfg config.Config) error {
 		asyncCtx, cancel := context.WithCancel(ctx)
 		cancelAsync = cancel
 		goSafe(logger, "run_async_tasks", func() {
-			runAsyncTasks(asyncCtx, db, bot.Session(), cfg, logger)
+			runAsyncTasks(asyncCtx, db, bot.Session(), riotClient, cdnClient, logger)
 		})
 	}
 	defer cancelAsync()
 	return bot.Run()
 }
 
-func validateRiotAPIKeyOnStartup(ctx context.Context, apiKey string, logger *slog.Logger) error {
+func validateRiotAPIKeyOnStartup(ctx context.Context, client riot.API, apiKey string, logger *slog.Logger) error {
 	if strings.TrimSpace(apiKey) == "" {
 		return fmt.Errorf("validate riot api key: key is empty")
 	}
 	checkCtx, cancel := context.WithTimeout(ctx, riotValidationTimeout)
 	defer cancel()
-	if _, err := riot.FetchChampionRotation(checkCtx, riotValidationRegion, apiKey); err != nil {
+	if _, err := client.FetchChampionRotation(checkCtx, riotValidationRegion); err != nil {
 		if logger != nil {
 			logger.Error("Riot API key validation failed", "region", riotValidationRegion, "error", err)
 		}
@@ -149,7 +181,46 @@ func setupLogger(cfg config.Config, database *postgres.Database) *slog.Logger {
 	return logger
 }
 
-func runAsyncTasks(ctx context.Context, db *postgres.Database, session *discordgo.Session, cfg config.Config, logger *slog.Logger) {
+// newCDNClient reads game data from the configured bundle or mirrors, or from Data Dragon and
+// CommunityDragon when none is set.
+func newCDNClient(cfg config.Config, logger *slog.Logger) (*cdn.Client, error) {
+	if cfg.CDNBundle != "" {
+		bundle, err := cdn.OpenBundle(cfg.CDNBundle)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("CDN data loaded from bundle", "path", cfg.CDNBundle)
+		return cdn.NewClient(cdn.WithBundle(bundle)), nil
+	}
+	if cfg.DataDragonURL != "" || cfg.CommunityDragonURL != "" {
+		logger.Info("CDN requests redirected", "dataDragonURL", cfg.DataDragonURL, "communityDragonURL", cfg.CommunityDragonURL)
+	}
+	return cdn.NewClient(cdn.WithDataDragonURL(cfg.DataDragonURL), cdn.WithCommunityDragonURL(cfg.CommunityDragonURL)), nil
+}
+
+// loggedLimiterBackend reports failures of the shared rate limit backend, which the riot package skips
+// silently to keep requests flowing, at most once per sharedLimitWarnInterval.
+type loggedLimiterBackend struct {
+	backend  riot.LimiterBackend
+	logger   *slog.Logger
+	mu       sync.Mutex
+	lastWarn time.Time
+}
+
+func (b *loggedLimiterBackend) ReserveRiotRequest(ctx context.Context, buckets []riot.RateLimitBucket) (time.Duration, error) {
+	wait, err := b.backend.ReserveRiotRequest(ctx, buckets)
+	if err != nil && ctx.Err() == nil {
+		b.mu.Lock()
+		if now := time.Now(); now.Sub(b.lastWarn) >= sharedLimitWarnInterval {
+			b.lastWarn = now
+			b.logger.Warn("Shared Riot rate limit unavailable; using local limits only", "err", err)
+		}
+		b.mu.Unlock()
+	}
+	return wait, err
+}
+
+func runAsyncTasks(ctx context.Context, db *postgres.Database, session *discordgo.Session, riotClient riot.API, cdnClient *cdn.Client, logger *slog.Logger) {
 	if err := db.CreateFreeWeekTable(ctx); err != nil {
 		logger.Error("Schema error (freeweek)", "err", err)
 		return
@@ -158,15 +229,15 @@ func runAsyncTasks(ctx context.Context, db *postgres.Database, session *discordg
 		logger.Error("Schema error (riot_cdn)", "err", err)
 		return
 	}
-	if strings.TrimSpace(cfg.RiotAPIKey) != "" && session != nil {
-		notifier := tracknotify.NewService(db, session, cfg.RiotAPIKey, logger)
+	if riotClient != nil && session != nil {
+		notifier := tracknotify.NewService(db, session, riotClient, logger)
 		goSafe(logger, "track_notify_loop", func() {
 			notifier.Run(ctx)
 		})
 	}
 
 	// Initial sync at startup
-	syncCDN(ctx, db, session, logger)
+	syncCDN(ctx, cdnClient, db, session, logger)
 
 	// Keep data/emojis updated without restart.
 	ticker := time.NewTicker(cdnSyncInterval)
@@ -176,14 +247,14 @@ func runAsyncTasks(ctx context.Context, db *postgres.Database, session *discordg
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			syncCDN(ctx, db, session, logger)
+			syncCDN(ctx, cdnClient, db, session, logger)
 		}
 	}
 }
 
 // Run CDN & Emoji Sync
-func syncCDN(ctx context.Context, db *postgres.Database, session *discordgo.Session, logger *slog.Logger) {
-	versions, err := cdn.NewClient().FetchVersions(ctx)
+func syncCDN(ctx context.Context, cdnClient *cdn.Client, db *postgres.Database, session *discordgo.Session, logger *slog.Logger) {
+	versions, err := cdnClient.FetchVersions(ctx)
 	if err != nil || len(versions) == 0 {
 		logger.Error("Failed to fetch Riot versions", "err", err)
 		return
@@ -222,7 +293,7 @@ func syncCDN(ctx context.Context, db *postgres.Database, session *discordgo.Sess
 	}
 	// Start Emoji Sync. Rank sync always runs; non-rank sync only when needed.
 	if session != nil {
-		errCh, err := cdn.StartApplicationEmojiSync(ctx, session, ver, syncNonRank, db, logger)
+		errCh, err := cdn.StartApplicationEmojiSync(ctx, session, cdnClient, ver, syncNonRank, db, logger)
 		if err != nil {
 			logger.Error("Failed to start emoji sync", "err", err)
 		} else if errCh != nil {
@@ -244,7 +315,7 @@ func syncCDN(ctx context.Context, db *postgres.Database, session *discordgo.Sess
 	logger.Info("Starting CDN metadata sync...", "version", ver)
 	ctx, cancel := context.WithTimeout(ctx, syncTimeout)
 	defer cancel()
-	if err := cdn.SyncBasicData(ctx, db, ver); err != nil {
+	if err := cdn.SyncBasicData(ctx, cdnClient, db, ver); err != nil {
 		logger.Error("CDN metadata sync failed", "err", err)
 		return
 	}
@@ -260,6 +331,13 @@ func buildRegistry() *discord.Registry {
 	r.Add(commands.SearchCommand)
 	r.Add(commands.TrackCommand)
 	r.Add(commands.LeadboardCommand)
+	r.Add(commands.HistoryCommand)
+	r.Add(commands.MasteryCommand)
+	r.Add(commands.LPCommand)
+	r.Add(commands.LinkCommand)
+	r.Add(commands.MeCommand)
+	r.Add(commands.ProfileCommand)
+	r.Add(commands.ProfileUserCommand)
 	return r
 }
 
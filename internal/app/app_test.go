@@ -2,12 +2,16 @@ package app
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
 )
 
 type lockedBuffer struct {
@@ -87,3 +91,28 @@ func TestGoSafeRunsFunction(t *testing.T) {
 		t.Fatal("background task did not run")
 	}
 }
+
+type failingLimiterBackend struct{ calls int }
+
+func (f *failingLimiterBackend) ReserveRiotRequest(context.Context, []riot.RateLimitBucket) (time.Duration, error) {
+	f.calls++
+	return 0, errors.New("database down")
+}
+
+func TestLoggedLimiterBackendThrottlesWarnings(t *testing.T) {
+	var out lockedBuffer
+	backend := &failingLimiterBackend{}
+	logged := &loggedLimiterBackend{backend: backend, logger: slog.New(slog.NewTextHandler(&out, nil))}
+
+	for range 3 {
+		if _, err := logged.ReserveRiotRequest(t.Context(), nil); err == nil {
+			t.Fatal("expected backend error to be returned")
+		}
+	}
+	if backend.calls != 3 {
+		t.Fatalf("backend calls = %d, want 3", backend.calls)
+	}
+	if got := strings.Count(out.String(), "Shared Riot rate limit unavailable"); got != 1 {
+		t.Fatalf("warnings = %d, want 1; got: %s", got, out.String())
+	}
+}
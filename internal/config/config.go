@@ -3,8 +3,10 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -27,7 +29,17 @@ type Config struct {
 	DatabaseURL  string
 	RiotAPIKey   string
 	RateLimitCfg string
-	LogLevel     slog.Level
+	// RiotBaseURL overrides where Riot API requests go, e.g. a local stand-in; "{routing}" is replaced
+	// by the routing value of the request.
+	RiotBaseURL string
+	// SharedRateLimit coordinates the Riot rate limits of every replica through the database.
+	SharedRateLimit bool
+	// CDNBundle is a directory or tarball made by cmd/cdnbundle that replaces Data Dragon and
+	// CommunityDragon; DataDragonURL and CommunityDragonURL point at mirrors of them instead.
+	CDNBundle          string
+	DataDragonURL      string
+	CommunityDragonURL string
+	LogLevel           slog.Level
 }
 
 func Parse() (Config, error) {
@@ -68,17 +80,61 @@ func Parse() (Config, error) {
 		rateLimitCfg = defaultRateLimitCfg
 	}
 
+	riotBaseURL, err := parseBaseURL("RIOT_API_BASE_URL")
+	if err != nil {
+		return Config{}, err
+	}
+
+	sharedRateLimit := false
+	if raw := strings.TrimSpace(os.Getenv("RIOT_SHARED_RATE_LIMIT")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("RIOT_SHARED_RATE_LIMIT is invalid: %q", raw)
+		}
+		sharedRateLimit = parsed
+	}
+
+	cdnBundle := strings.TrimSpace(os.Getenv("CDN_BUNDLE"))
+	dataDragonURL, err := parseBaseURL("CDN_DATA_DRAGON_URL")
+	if err != nil {
+		return Config{}, err
+	}
+	communityDragonURL, err := parseBaseURL("CDN_COMMUNITY_DRAGON_URL")
+	if err != nil {
+		return Config{}, err
+	}
+	if cdnBundle != "" && (dataDragonURL != "" || communityDragonURL != "") {
+		return Config{}, fmt.Errorf("CDN_BUNDLE cannot be combined with CDN_DATA_DRAGON_URL or CDN_COMMUNITY_DRAGON_URL")
+	}
+
 	return Config{
-		DiscordToken: token,
-		GuildID:      guildID,
-		IsDev:        isDev,
-		DatabaseURL:  databaseURL,
-		RiotAPIKey:   riotAPIKey,
-		RateLimitCfg: rateLimitCfg,
-		LogLevel:     logLevel,
+		DiscordToken:       token,
+		GuildID:            guildID,
+		IsDev:              isDev,
+		DatabaseURL:        databaseURL,
+		RiotAPIKey:         riotAPIKey,
+		RateLimitCfg:       rateLimitCfg,
+		RiotBaseURL:        riotBaseURL,
+		SharedRateLimit:    sharedRateLimit,
+		CDNBundle:          cdnBundle,
+		DataDragonURL:      dataDragonURL,
+		CommunityDragonURL: communityDragonURL,
+		LogLevel:           logLevel,
 	}, nil
 }
 
+// parseBaseURL reads an optional http(s) base URL from the environment.
+func parseBaseURL(name string) (string, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return "", nil
+	}
+	if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%s is invalid: %q", name, raw)
+	}
+	return raw, nil
+}
+
 func inferLogLevel(appEnv string) slog.Level {
 	if appEnv == "debug" {
 		return slog.LevelDebug
@@ -177,3 +177,96 @@ func TestParse_InvalidDiscordTokenFormat(t *testing.T) {
 		t.Fatalf("expected invalid DISCORD_TOKEN format error")
 	}
 }
+
+func TestParse_SharedRateLimit(t *testing.T) {
+	t.Setenv("APP_ENV", "prod")
+	t.Setenv("DISCORD_TOKEN", validDiscordToken)
+	t.Setenv("DISCORD_GUILD_ID", "")
+	t.Setenv("DATABASE_URL", validDatabaseURL)
+	t.Setenv("RIOT_API_KEY", validRiotAPIKey)
+
+	t.Setenv("RIOT_SHARED_RATE_LIMIT", "")
+	cfg, err := Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SharedRateLimit {
+		t.Fatalf("expected shared rate limit disabled by default")
+	}
+
+	t.Setenv("RIOT_SHARED_RATE_LIMIT", "true")
+	cfg, err = Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.SharedRateLimit {
+		t.Fatalf("expected shared rate limit enabled")
+	}
+
+	t.Setenv("RIOT_SHARED_RATE_LIMIT", "sometimes")
+	if _, err := Parse(); err == nil {
+		t.Fatalf("expected invalid RIOT_SHARED_RATE_LIMIT error")
+	}
+}
+
+func TestParse_RiotBaseURL(t *testing.T) {
+	t.Setenv("APP_ENV", "prod")
+	t.Setenv("DISCORD_TOKEN", validDiscordToken)
+	t.Setenv("DISCORD_GUILD_ID", "")
+	t.Setenv("DATABASE_URL", validDatabaseURL)
+	t.Setenv("RIOT_API_KEY", validRiotAPIKey)
+
+	t.Setenv("RIOT_API_BASE_URL", "http://127.0.0.1:8089/{routing}")
+	cfg, err := Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RiotBaseURL != "http://127.0.0.1:8089/{routing}" {
+		t.Fatalf("unexpected RiotBaseURL: %q", cfg.RiotBaseURL)
+	}
+
+	t.Setenv("RIOT_API_BASE_URL", "127.0.0.1:8089")
+	if _, err := Parse(); err == nil {
+		t.Fatalf("expected invalid RIOT_API_BASE_URL error")
+	}
+}
+
+func TestParse_CDNSources(t *testing.T) {
+	t.Setenv("APP_ENV", "prod")
+	t.Setenv("DISCORD_TOKEN", validDiscordToken)
+	t.Setenv("DISCORD_GUILD_ID", "")
+	t.Setenv("DATABASE_URL", validDatabaseURL)
+	t.Setenv("RIOT_API_KEY", validRiotAPIKey)
+
+	t.Setenv("CDN_BUNDLE", "")
+	t.Setenv("CDN_DATA_DRAGON_URL", "http://mirror.local/ddragon")
+	t.Setenv("CDN_COMMUNITY_DRAGON_URL", "http://mirror.local/cdragon")
+	cfg, err := Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DataDragonURL != "http://mirror.local/ddragon" || cfg.CommunityDragonURL != "http://mirror.local/cdragon" {
+		t.Fatalf("unexpected CDN mirrors: %q, %q", cfg.DataDragonURL, cfg.CommunityDragonURL)
+	}
+
+	t.Setenv("CDN_BUNDLE", "cdn-bundle.tar.gz")
+	if _, err := Parse(); err == nil {
+		t.Fatalf("expected error when CDN_BUNDLE is combined with mirrors")
+	}
+
+	t.Setenv("CDN_DATA_DRAGON_URL", "")
+	t.Setenv("CDN_COMMUNITY_DRAGON_URL", "")
+	cfg, err = Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.CDNBundle != "cdn-bundle.tar.gz" {
+		t.Fatalf("unexpected CDNBundle: %q", cfg.CDNBundle)
+	}
+
+	t.Setenv("CDN_BUNDLE", "")
+	t.Setenv("CDN_DATA_DRAGON_URL", "mirror.local")
+	if _, err := Parse(); err == nil {
+		t.Fatalf("expected invalid CDN_DATA_DRAGON_URL error")
+	}
+}
@@ -55,6 +55,35 @@ func OptionValueByName(options []*discordgo.ApplicationCommandInteractionDataOpt
 	return ""
 }
 
+func OptionIntByName(options []*discordgo.ApplicationCommandInteractionDataOption, name string) (int, bool) {
+	for _, option := range options {
+		if option.Name != name {
+			continue
+		}
+		switch value := option.Value.(type) {
+		case float64:
+			return int(value), true
+		case int64:
+			return int(value), true
+		case int:
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+func OptionBoolByName(options []*discordgo.ApplicationCommandInteractionDataOption, name string) (bool, bool) {
+	for _, option := range options {
+		if option.Name != name {
+			continue
+		}
+		if value, ok := option.Value.(bool); ok {
+			return value, true
+		}
+	}
+	return false, false
+}
+
 func FocusedOptionValue(options []*discordgo.ApplicationCommandInteractionDataOption) string {
 	for _, option := range options {
 		if option.Focused {
@@ -67,6 +96,16 @@ func FocusedOptionValue(options []*discordgo.ApplicationCommandInteractionDataOp
 	return ""
 }
 
+// FocusedOptionName returns the name of the option being autocompleted.
+func FocusedOptionName(options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	for _, option := range options {
+		if option.Focused {
+			return option.Name
+		}
+	}
+	return ""
+}
+
 func isValidLen(s string, min, max int) bool {
 	l := utf8.RuneCountInString(s)
 	return l >= min && l <= max
@@ -16,28 +16,41 @@ import (
 type Command struct {
 	Data    *discordgo.ApplicationCommand
 	Handler CommandHandler
+	// Components maps custom-ID prefixes to the handlers of the buttons and menus the command sends.
+	Components map[string]ComponentHandler
 }
 
 type CommandHandler func(s *discordgo.Session, i *discordgo.InteractionCreate)
 
+// commandKey identifies a handler. Discord lets a slash command and a context-menu command share a name.
+type commandKey struct {
+	Type discordgo.ApplicationCommandType
+	Name string
+}
+
 type Registry struct {
-	commands []*discordgo.ApplicationCommand
-	handlers map[string]CommandHandler
+	commands   []*discordgo.ApplicationCommand
+	handlers   map[commandKey]CommandHandler
+	components map[string]ComponentHandler
 }
 
 func NewRegistry() *Registry {
-	return &Registry{handlers: make(map[string]CommandHandler)}
+	return &Registry{handlers: make(map[commandKey]CommandHandler), components: make(map[string]ComponentHandler)}
 }
 
+// Add registers a slash, user or message command; a zero Data.Type means a slash command.
 func (r *Registry) Add(cmd *Command) {
 	if r == nil || cmd == nil || cmd.Data == nil || cmd.Handler == nil {
 		return
 	}
 	if r.handlers == nil {
-		r.handlers = make(map[string]CommandHandler)
+		r.handlers = make(map[commandKey]CommandHandler)
 	}
 	r.commands = append(r.commands, cmd.Data)
-	r.handlers[cmd.Data.Name] = cmd.Handler
+	r.handlers[commandKey{Type: normalizeCommandType(cmd.Data.Type), Name: cmd.Data.Name}] = cmd.Handler
+	for prefix, handler := range cmd.Components {
+		r.AddComponent(prefix, handler)
+	}
 }
 
 func (r *Registry) Commands() []*discordgo.ApplicationCommand {
@@ -49,14 +62,21 @@ func (r *Registry) Commands() []*discordgo.ApplicationCommand {
 	return out
 }
 
-func (r *Registry) Handler(name string) (CommandHandler, bool) {
+func (r *Registry) Handler(commandType discordgo.ApplicationCommandType, name string) (CommandHandler, bool) {
 	if r == nil {
 		return nil, false
 	}
-	h, ok := r.handlers[name]
+	h, ok := r.handlers[commandKey{Type: normalizeCommandType(commandType), Name: name}]
 	return h, ok
 }
 
+func normalizeCommandType(commandType discordgo.ApplicationCommandType) discordgo.ApplicationCommandType {
+	if commandType == 0 {
+		return discordgo.ChatApplicationCommand
+	}
+	return commandType
+}
+
 type Bot struct {
 	session  *discordgo.Session
 	registry *Registry
@@ -160,12 +180,16 @@ func (b *Bot) handleInteraction(s *discordgo.Session, i *discordgo.InteractionCr
 	if i == nil || i.Interaction == nil {
 		return
 	}
+	if i.Type == discordgo.InteractionMessageComponent || i.Type == discordgo.InteractionModalSubmit {
+		b.handleComponent(s, i)
+		return
+	}
 	if i.Type != discordgo.InteractionApplicationCommand && i.Type != discordgo.InteractionApplicationCommandAutocomplete {
 		return
 	}
 	b.logInteraction(i)
-	cmdName := i.ApplicationCommandData().Name
-	if h, ok := b.registry.Handler(cmdName); ok {
+	data := i.ApplicationCommandData()
+	if h, ok := b.registry.Handler(data.CommandType, data.Name); ok {
 		h(s, i)
 		return
 	}
@@ -201,8 +225,13 @@ func InteractionUserID(i *discordgo.InteractionCreate) (username, userID string)
 func (b *Bot) logInteraction(i *discordgo.InteractionCreate) {
 	username, userID := InteractionUserID(i)
 	interactionType := "command"
-	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+	switch {
+	case i.Type == discordgo.InteractionApplicationCommandAutocomplete:
 		interactionType = "autocomplete"
+	case i.ApplicationCommandData().CommandType == discordgo.UserApplicationCommand:
+		interactionType = "user_command"
+	case i.ApplicationCommandData().CommandType == discordgo.MessageApplicationCommand:
+		interactionType = "message_command"
 	}
 	b.logger.Info("Interaction", "command", i.ApplicationCommandData().Name, "type", interactionType, "username", username, "userID", userID, "guildID", i.GuildID)
 }
@@ -76,3 +76,14 @@ func AccountTargetOptions() []*discordgo.ApplicationCommandOption {
 		NickInputOption,
 	}
 }
+
+// OptionalAccountTargetOptions is AccountTargetOptions for commands that fall back to the caller's linked account.
+func OptionalAccountTargetOptions() []*discordgo.ApplicationCommandOption {
+	options := AccountTargetOptions()
+	for i, option := range options {
+		optional := *option
+		optional.Required = false
+		options[i] = &optional
+	}
+	return options
+}
@@ -185,3 +185,37 @@ func captureBulkOverwriteCalls(bot *Bot) func() []overwriteCall {
 		return out
 	}
 }
+
+func TestRegistry_DispatchesByCommandType(t *testing.T) {
+	var called []string
+	registry := NewRegistry()
+	registry.Add(&Command{
+		Data: &discordgo.ApplicationCommand{Name: "profile"},
+		Handler: func(*discordgo.Session, *discordgo.InteractionCreate) {
+			called = append(called, "chat")
+		},
+	})
+	registry.Add(&Command{
+		Data: &discordgo.ApplicationCommand{Name: "profile", Type: discordgo.UserApplicationCommand},
+		Handler: func(*discordgo.Session, *discordgo.InteractionCreate) {
+			called = append(called, "user")
+		},
+	})
+	if _, ok := registry.Handler(discordgo.MessageApplicationCommand, "profile"); ok {
+		t.Fatal("expected no message command handler")
+	}
+
+	bot := &Bot{registry: registry, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, commandType := range []discordgo.ApplicationCommandType{discordgo.UserApplicationCommand, discordgo.ChatApplicationCommand} {
+		bot.handleInteraction(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "profile", CommandType: commandType},
+		}})
+	}
+	if !reflect.DeepEqual(called, []string{"user", "chat"}) {
+		t.Fatalf("handlers called = %#v, want [user chat]", called)
+	}
+	if got := len(registry.Commands()); got != 2 {
+		t.Fatalf("registered commands = %d, want 2", got)
+	}
+}
@@ -49,7 +49,9 @@ var FreeWeekCommand = &discord.Command{
 }
 
 type Runtime struct {
-	RiotAPIKey     string
+	// Riot serves the Riot API calls of the commands; commands that need it report they are not
+	// configured while it is nil.
+	Riot           riot.API
 	PlatformRegion string
 	Database       storage.CommandDB
 }
@@ -62,7 +64,7 @@ var (
 func ConfigureRuntime(cfg Runtime) {
 	runtimeMu.Lock()
 	runtime = Runtime{
-		RiotAPIKey:     strings.TrimSpace(cfg.RiotAPIKey),
+		Riot:           cfg.Riot,
 		PlatformRegion: runtimePlatformRegion(cfg.PlatformRegion),
 		Database:       cfg.Database,
 	}
@@ -84,7 +86,7 @@ func runtimePlatformRegion(region string) string {
 
 func handleFreeWeek(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	rt := currentRuntime()
-	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && strings.TrimSpace(rt.RiotAPIKey) != "" && strings.TrimSpace(rt.PlatformRegion) != "") {
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil && strings.TrimSpace(rt.PlatformRegion) != "") {
 		return
 	}
 
@@ -120,7 +122,7 @@ func loadFreeWeekRotation(ctx context.Context, rt Runtime) (riot.ChampionRotatio
 		return cached, nil
 	}
 
-	rotation, err := riot.FetchChampionRotation(ctx, rt.PlatformRegion, rt.RiotAPIKey)
+	rotation, err := rt.Riot.FetchChampionRotation(ctx, rt.PlatformRegion)
 	if err != nil {
 		if found {
 			slog.Warn("Refresh failed, serving stale cache", "region", rt.PlatformRegion, "age", now.Sub(fetchedAt), "error", err)
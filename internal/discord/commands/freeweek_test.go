@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bingbr/League-API-bot/internal/discord/embedtest"
+	"github.com/bingbr/League-API-bot/internal/riot"
 	"github.com/bingbr/League-API-bot/internal/storage/postgres"
 )
 
@@ -59,6 +61,26 @@ func TestMergeChampionIDs(t *testing.T) {
 	}
 }
 
+func TestBuildFreeWeekEmbed(t *testing.T) {
+	embed := buildFreeWeekEmbed(
+		riot.ChampionRotation{
+			FreeChampionIDs:              []int{245, 103, 157, 64, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			FreeChampionIDsForNewPlayers: []int{222, 254, 427, 82, 131},
+			MaxNewPlayerLevel:            10,
+		},
+		map[int]postgres.ChampionDisplay{
+			245: {ChampionID: 245, Name: "Ekko", DiscordIcon: "<:Ekko:1>"},
+			103: {ChampionID: 103, Name: "Ahri", DiscordIcon: "<:Ahri:2>"},
+			157: {ChampionID: 157, Name: "Yasuo"},
+			222: {ChampionID: 222, Name: "Jinx", DiscordIcon: "<:Jinx:3>"},
+		},
+	)
+	embedtest.Golden(t, "free_week", embed)
+
+	empty := buildFreeWeekEmbed(riot.ChampionRotation{MaxNewPlayerLevel: 10}, nil)
+	embedtest.Golden(t, "free_week_empty", empty)
+}
+
 func TestChampionLines(t *testing.T) {
 	champions := map[int]postgres.ChampionDisplay{
 		1: {ChampionID: 1, Name: "Annie", DiscordIcon: "<:Annie:1>"},
@@ -0,0 +1,308 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	historyTimeout      = 20 * time.Second
+	historyEmbedColor   = 0x3B88C3
+	historyDefaultCount = 5
+	historyMaxCount     = 10
+	historyMaxPage      = 10
+	historyFetchLimit   = 4
+)
+
+// -- Command Definition --
+var HistoryCommand = &discord.Command{
+	Data: &discordgo.ApplicationCommand{
+		Name:        "history",
+		Description: "View the latest games of an account.",
+		IntegrationTypes: &[]discordgo.ApplicationIntegrationType{
+			discordgo.ApplicationIntegrationGuildInstall,
+			discordgo.ApplicationIntegrationUserInstall,
+		},
+		Contexts: &[]discordgo.InteractionContextType{
+			discordgo.InteractionContextGuild,
+			discordgo.InteractionContextBotDM,
+			discordgo.InteractionContextPrivateChannel,
+		},
+		Options: append(discord.AccountTargetOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "queue",
+				Description: "Only show games from this queue.",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Ranked Solo/Duo", Value: 420},
+					{Name: "Ranked Flex", Value: 440},
+					{Name: "Normal Draft", Value: 400},
+					{Name: "Swiftplay", Value: 480},
+					{Name: "ARAM", Value: 450},
+				},
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "count",
+				Description: "Number of games per page.",
+				MinValue:    new(1.0),
+				MaxValue:    historyMaxCount,
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "page",
+				Description: "Page of the match history.",
+				MinValue:    new(1.0),
+				MaxValue:    historyMaxPage,
+			},
+		),
+	},
+	Handler: handleHistory,
+}
+
+type historyQuery struct {
+	QueueID int
+	Count   int
+	Page    int
+}
+
+type historyGame struct {
+	Match  riot.MatchDetail
+	Player riot.MatchPlayer
+}
+
+func handleHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	region, nick, tag, validationErr := discord.ParseAccountTargetOptions(i, options)
+	if validationErr != "" {
+		discord.RespondWithError(s, i, validationErr)
+		return
+	}
+	query := parseHistoryQuery(options)
+
+	if err := discord.RunDeferredEmbedCommand(s, i, historyTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
+		account, err := rt.Riot.FetchAccountByRiotID(ctx, region, nick, tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account: %w", err)
+		}
+		games, err := loadHistoryGames(ctx, rt, region, account.PUUID, query)
+		if err != nil {
+			return nil, fmt.Errorf("load match history: %w", err)
+		}
+
+		champions := loadOrEmptyDisplays(func() (map[int]postgres.ChampionDisplay, error) {
+			return rt.Database.ChampionDisplayByIDs(ctx, historyChampionIDs(games))
+		})
+		queueNames := loadHistoryQueueNames(ctx, rt.Database, games)
+		return []*discordgo.MessageEmbed{buildHistoryEmbed(account, games, champions, queueNames, query)}, nil
+	}, func(err error) string {
+		return mapSearchDeferredError(i, err, nick, tag)
+	}); err != nil {
+		slog.Error("Failed to handle deferred history interaction", "error", err)
+	}
+}
+
+func parseHistoryQuery(options []*discordgo.ApplicationCommandInteractionDataOption) historyQuery {
+	query := historyQuery{Count: historyDefaultCount, Page: 1}
+	if queueID, ok := discord.OptionIntByName(options, "queue"); ok && queueID > 0 {
+		query.QueueID = queueID
+	}
+	if count, ok := discord.OptionIntByName(options, "count"); ok {
+		query.Count = min(max(count, 1), historyMaxCount)
+	}
+	if page, ok := discord.OptionIntByName(options, "page"); ok {
+		query.Page = min(max(page, 1), historyMaxPage)
+	}
+	return query
+}
+
+func loadHistoryGames(ctx context.Context, rt Runtime, region, puuid string, query historyQuery) ([]historyGame, error) {
+	continent := riot.PlatformContinent(region)
+	if continent == "" {
+		return nil, fmt.Errorf("region is invalid")
+	}
+
+	matchIDs, err := rt.Riot.FetchMatchIDsByPUUID(ctx, continent, puuid, riot.MatchIDsFilter{
+		Start:   (query.Page - 1) * query.Count,
+		Count:   query.Count,
+		QueueID: query.QueueID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matchIDs) == 0 {
+		return []historyGame{}, nil
+	}
+
+	matches, err := loadHistoryMatches(ctx, rt, continent, matchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	games := make([]historyGame, 0, len(matchIDs))
+	for _, matchID := range matchIDs {
+		match, ok := matches[matchID]
+		if !ok {
+			continue
+		}
+		player := riot.MatchPlayerByPUUID(match.Info.Players, puuid)
+		if player == nil {
+			continue
+		}
+		games = append(games, historyGame{Match: match, Player: *player})
+	}
+	return games, nil
+}
+
+// loadHistoryMatches serves matches from track_match_snapshots and only asks Riot for the missing ones.
+func loadHistoryMatches(ctx context.Context, rt Runtime, continent string, matchIDs []string) (map[string]riot.MatchDetail, error) {
+	cached, err := rt.Database.GetTrackMatchSnapshots(ctx, matchIDs)
+	if err != nil {
+		slog.Warn("Failed to load cached matches for /history", "error", err)
+		cached = map[string]riot.MatchDetail{}
+	}
+
+	missing := make([]string, 0, len(matchIDs))
+	for _, matchID := range matchIDs {
+		if _, ok := cached[matchID]; !ok {
+			missing = append(missing, matchID)
+		}
+	}
+	if len(missing) == 0 {
+		return cached, nil
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(historyFetchLimit)
+	for _, matchID := range missing {
+		g.Go(func() error {
+			match, err := rt.Riot.FetchMatchByID(gctx, continent, matchID)
+			if err != nil {
+				return err
+			}
+			if err := rt.Database.UpsertTrackMatchSnapshot(gctx, match); err != nil {
+				slog.Warn("Failed to cache match for /history", "matchID", matchID, "error", err)
+			}
+			mu.Lock()
+			cached[matchID] = match
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return cached, nil
+}
+
+func loadHistoryQueueNames(ctx context.Context, db storage.HistoryDB, games []historyGame) map[int]string {
+	names := make(map[int]string)
+	for _, game := range games {
+		queueID := game.Match.Info.QueueID
+		if _, ok := names[queueID]; ok {
+			continue
+		}
+		display, found, err := db.QueueDisplayByID(ctx, queueID)
+		if err != nil {
+			slog.Warn("Failed to load queue display for /history", "queueID", queueID, "error", err)
+		}
+		names[queueID] = historyQueueName(queueID, display, found)
+	}
+	return names
+}
+
+func historyQueueName(queueID int, display postgres.QueueDisplay, found bool) string {
+	if name := strings.TrimSpace(display.Name); found && name != "" {
+		return name
+	}
+	if queueID == 0 {
+		return "Custom"
+	}
+	return fmt.Sprintf("Queue %d", queueID)
+}
+
+func historyChampionIDs(games []historyGame) []int {
+	ids := make([]int, 0, len(games))
+	for _, game := range games {
+		ids = append(ids, game.Player.ChampionID)
+	}
+	return mergeChampionIDs(ids)
+}
+
+func buildHistoryEmbed(account riot.RiotAccount, games []historyGame, champions map[int]postgres.ChampionDisplay, queueNames map[int]string, query historyQuery) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    "Match History",
+			IconURL: cdn.ProfileIconURL(588),
+		},
+		Title:  riot.FormatRiotID(account.GameName, account.TagLine),
+		Color:  historyEmbedColor,
+		Fields: make([]*discordgo.MessageEmbedField, 0, len(games)),
+	}
+	if len(games) == 0 {
+		embed.Description = "No games found for this page."
+		discord.ApplyDefaultFooter(embed)
+		return embed
+	}
+
+	wins := 0
+	for _, game := range games {
+		if game.Player.Win {
+			wins++
+		}
+		embed.Fields = append(embed.Fields, historyGameField(game, champions, queueNames))
+	}
+	first := (query.Page-1)*query.Count + 1
+	embed.Description = fmt.Sprintf("**Page %d** · games %d-%d · %dW %dL", query.Page, first, first+len(games)-1, wins, len(games)-wins)
+	discord.ApplyDefaultFooter(embed)
+	return embed
+}
+
+func historyGameField(game historyGame, champions map[int]postgres.ChampionDisplay, queueNames map[int]string) *discordgo.MessageEmbedField {
+	result := "❌ Defeat"
+	if game.Player.Win {
+		result = "✅ Victory"
+	}
+	queueName := queueNames[game.Match.Info.QueueID]
+	if queueName == "" {
+		queueName = historyQueueName(game.Match.Info.QueueID, postgres.QueueDisplay{}, false)
+	}
+
+	duration := riot.FormatDuration(riot.MatchDurationSeconds(game.Match.Info))
+	if endedAt := game.Match.Info.GameEndTimestamp; endedAt > 0 {
+		duration = fmt.Sprintf("%s · <t:%d:R>", duration, endedAt/1000)
+	}
+	return &discordgo.MessageEmbedField{
+		Name:  fmt.Sprintf("%s · %s", result, queueName),
+		Value: fmt.Sprintf("%s · %s\n%s", championLines([]int{game.Player.ChampionID}, champions)[0], riot.FormatKDA(game.Player), duration),
+	}
+}
+
+func loadOrEmptyDisplays[K comparable, V any](load func() (map[K]V, error)) map[K]V {
+	loaded, err := load()
+	if err != nil {
+		slog.Warn("Failed to load display data", "error", err)
+	}
+	if loaded == nil {
+		return map[K]V{}
+	}
+	return loaded
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseHistoryQuery(t *testing.T) {
+	query := parseHistoryQuery(nil)
+	if query.Count != historyDefaultCount || query.Page != 1 || query.QueueID != 0 {
+		t.Fatalf("parseHistoryQuery(nil) = %+v", query)
+	}
+
+	query = parseHistoryQuery([]*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "queue", Value: float64(420)},
+		{Name: "count", Value: float64(50)},
+		{Name: "page", Value: float64(3)},
+	})
+	if query.Count != historyMaxCount || query.Page != 3 || query.QueueID != 420 {
+		t.Fatalf("parseHistoryQuery() = %+v", query)
+	}
+}
+
+func TestBuildHistoryEmbed(t *testing.T) {
+	games := []historyGame{
+		{
+			Match:  riot.MatchDetail{Info: riot.MatchInfo{QueueID: 420, GameDuration: 1872, GameEndTimestamp: 1770422047398}},
+			Player: riot.MatchPlayer{ChampionID: 103, Kills: 10, Deaths: 2, Assists: 8, Win: true},
+		},
+		{
+			Match:  riot.MatchDetail{Info: riot.MatchInfo{QueueID: 450, GameDuration: 900}},
+			Player: riot.MatchPlayer{ChampionID: 245, Kills: 1, Deaths: 5, Assists: 3},
+		},
+	}
+	embed := buildHistoryEmbed(
+		riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"},
+		games,
+		map[int]postgres.ChampionDisplay{103: {ChampionID: 103, Name: "Ahri", DiscordIcon: "<:Ahri:1>"}},
+		map[int]string{420: "Ranked Solo/Duo"},
+		historyQuery{Count: 5, Page: 2},
+	)
+
+	if embed.Title != "Bekko#Ekko" {
+		t.Fatalf("embed.Title = %q", embed.Title)
+	}
+	if embed.Description != "**Page 2** · games 6-7 · 1W 1L" {
+		t.Fatalf("embed.Description = %q", embed.Description)
+	}
+	if len(embed.Fields) != 2 {
+		t.Fatalf("len(embed.Fields) = %d, want 2", len(embed.Fields))
+	}
+	if embed.Fields[0].Name != "✅ Victory · Ranked Solo/Duo" {
+		t.Fatalf("embed.Fields[0].Name = %q", embed.Fields[0].Name)
+	}
+	if !strings.HasPrefix(embed.Fields[0].Value, "<:Ahri:1> Ahri · 10/2/8 9.00:1\n31m12s · <t:1770422047:R>") {
+		t.Fatalf("embed.Fields[0].Value = %q", embed.Fields[0].Value)
+	}
+	if embed.Fields[1].Name != "❌ Defeat · Queue 450" {
+		t.Fatalf("embed.Fields[1].Name = %q", embed.Fields[1].Name)
+	}
+	if !strings.HasPrefix(embed.Fields[1].Value, "ID 245 · 1/5/3") {
+		t.Fatalf("embed.Fields[1].Value = %q", embed.Fields[1].Value)
+	}
+}
+
+func TestBuildHistoryEmbed_Empty(t *testing.T) {
+	embed := buildHistoryEmbed(riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"}, nil, nil, nil, historyQuery{Count: 5, Page: 1})
+	if embed.Description != "No games found for this page." || len(embed.Fields) != 0 {
+		t.Fatalf("unexpected empty embed: %q fields=%d", embed.Description, len(embed.Fields))
+	}
+}
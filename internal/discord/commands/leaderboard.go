@@ -2,10 +2,13 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/bingbr/League-API-bot/internal/discord"
@@ -18,14 +21,14 @@ import (
 )
 
 const (
-	leadboardTimeout          = 20 * time.Second
-	leadboardEmbedColor       = 0xF4B38B
-	leadboardTrackedLimit     = 25
-	leadboardFetchLimit       = 8
-	leadboardMMRUnranked      = -1
-	leadboardDescription      = "List of the best solo/duo players on this Discord server."
-	leadboardRankedMMRBaseTop = 2800
-	leadboardFieldValueLimit  = 1024
+	leadboardTimeout     = 20 * time.Second
+	leadboardEmbedColor  = 0xF4B38B
+	leadboardPageSize    = 10
+	leadboardPageTTL     = 15 * time.Minute
+	leadboardPagePrefix  = "leaderboard"
+	leadboardFetchLimit  = 8
+	leadboardMMRUnranked = -1
+	leadboardDescription = "List of the best solo/duo players on this Discord server."
 )
 
 // -- Command Definition --
@@ -41,17 +44,22 @@ var LeadboardCommand = &discord.Command{
 		},
 	},
 	Handler: handleLeadboard,
+	Components: map[string]discord.ComponentHandler{
+		leadboardPagePrefix: handleLeadboardPage,
+	},
 }
 
 type leadboardRow struct {
 	account postgres.TrackedAccount
 	solo    *riot.LeagueEntry
 	mmr     int
+	// unknown marks an account whose rank was never stored and could not be fetched.
+	unknown bool
 }
 
 func handleLeadboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	rt := currentRuntime()
-	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && strings.TrimSpace(rt.RiotAPIKey) != "") {
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
 		return
 	}
 
@@ -63,71 +71,130 @@ func handleLeadboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	if err := discord.RunDeferredEmbedCommand(s, i, leadboardTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
-		rows, err := loadLeadboardRows(ctx, rt, guildID)
-		if err != nil {
-			return nil, fmt.Errorf("load leaderboard rows: %w", err)
-		}
-		if len(rows) == 0 {
-			return []*discordgo.MessageEmbed{leadboardInfoEmbed("No tracked accounts found for this server.\nUse `/track add` first.")}, nil
-		}
-
-		sortLeadboardRows(rows)
-		rankIcons := loadLeadboardRankIcons(ctx, rt.Database, rows)
-		return buildLeadboardEmbeds(rows, rankIcons), nil
-	}, func(error) string {
-		return "Could not load leaderboard right now. Please try again."
-	}); err != nil {
+	if err := discord.RunDeferredCommand(s, i, leadboardTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		return loadLeadboardPage(ctx, rt, guildID, 1)
+	}, mapLeadboardError); err != nil {
 		slog.Error("Failed to run deferred /leadboard", "error", err)
 	}
 }
 
-func loadLeadboardRows(ctx context.Context, rt Runtime, guildID string) ([]leadboardRow, error) {
-	tracked, err := rt.Database.ListTrackedAccounts(ctx, guildID, leadboardTrackedLimit)
+// handleLeadboardPage answers the Previous/Next buttons. The ranking is rebuilt from stored ranks, so pages
+// stay put between clicks, and only the rows of the new page are refreshed from Riot.
+func handleLeadboardPage(s *discordgo.Session, i *discordgo.InteractionCreate, id discord.ComponentID) {
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+	guildID := strings.TrimSpace(i.GuildID)
+	page, err := strconv.Atoi(id.Arg(0))
+	if err != nil || guildID == "" {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+	if !requireTrackConfig(s, i, rt.Database, guildID) {
+		return
+	}
+	if err := discord.RunDeferredComponentUpdate(s, i, leadboardTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		return loadLeadboardPage(ctx, rt, guildID, page)
+	}, mapLeadboardError); err != nil {
+		slog.Error("Failed to update leaderboard page", "error", err)
+	}
+}
+
+func loadLeadboardPage(ctx context.Context, rt Runtime, guildID string, page int) (discord.DeferredResponse, error) {
+	entries, err := rt.Database.ListTrackLeaderboardEntries(ctx, guildID, rankedSoloQueue)
 	if err != nil {
-		return nil, fmt.Errorf("list tracked accounts: %w", err)
+		return discord.DeferredResponse{}, fmt.Errorf("load leaderboard rows: %w", err)
 	}
-	if len(tracked) == 0 {
-		return []leadboardRow{}, nil
+	if len(entries) == 0 {
+		return discord.DeferredResponse{
+			Embeds:     []*discordgo.MessageEmbed{leadboardInfoEmbed("No tracked accounts found for this server.\nUse `/track add` first.")},
+			Components: []discordgo.MessageComponent{},
+		}, nil
 	}
 
-	rows := make([]leadboardRow, len(tracked))
-	for i, account := range tracked {
-		rows[i] = leadboardRow{account: account, mmr: leadboardMMRUnranked}
+	rows := leadboardRowsFromEntries(entries)
+	sortLeadboardRows(rows)
+	pages := leadboardPageCount(len(rows))
+	page = min(max(page, 1), pages)
+	failed := refreshLeadboardRows(ctx, rt.Riot, guildID, leadboardPageRows(rows, page))
+	rankIcons := loadLeadboardRankIcons(ctx, rt.Database, leadboardPageRows(rows, page))
+	return discord.DeferredResponse{
+		Embeds:     buildLeadboardEmbeds(rows, rankIcons, page, failed),
+		Components: leadboardPageComponents(page, pages),
+	}, nil
+}
+
+func mapLeadboardError(error) string {
+	return "Could not load leaderboard right now. Please try again."
+}
+
+// leadboardRowsFromEntries ranks the accounts by their latest stored solo queue snapshot, kept up to date
+// by the tracking service. Accounts without a snapshot yet are ranked last.
+func leadboardRowsFromEntries(entries []postgres.TrackLeaderboardEntry) []leadboardRow {
+	rows := make([]leadboardRow, 0, len(entries))
+	for _, entry := range entries {
+		row := leadboardRow{account: entry.Account, mmr: leadboardMMRUnranked, unknown: entry.Snapshot == nil}
+		if entry.Snapshot != nil {
+			row.setSolo(entry.Snapshot.LeagueEntry())
+		}
+		rows = append(rows, row)
 	}
+	return rows
+}
+
+func (r *leadboardRow) setSolo(entry riot.LeagueEntry) {
+	r.unknown = false
+	r.solo, r.mmr = nil, leadboardMMRUnranked
+	if strings.TrimSpace(entry.Tier) == "" {
+		return
+	}
+	r.solo, r.mmr = &entry, soloQueueMMR(entry)
+}
+
+// leagueEntryFetcher is the part of riot.API the leaderboard refreshes ranks with.
+type leagueEntryFetcher interface {
+	FetchLeagueEntriesByPUUID(ctx context.Context, platformRegion, puuid string) ([]riot.LeagueEntry, error)
+}
 
+// refreshLeadboardRows replaces the stored ranks of the rows with current ones from Riot and returns how many
+// rows could not be refreshed; those keep their stored rank. The rows are not reordered, so the pages of
+// one ranking never overlap.
+func refreshLeadboardRows(ctx context.Context, api leagueEntryFetcher, guildID string, rows []leadboardRow) int {
+	var failed atomic.Int32
 	g, gctx := errgroup.WithContext(ctx)
 	g.SetLimit(leadboardFetchLimit)
 	for idx := range rows {
 		g.Go(func() error {
 			region := riot.NormalizePlatformRegion(rows[idx].account.PlatformRegion)
-			if region == "" {
-				return nil
-			}
 			puuid := strings.TrimSpace(rows[idx].account.PUUID)
-			if puuid == "" {
+			if region == "" || puuid == "" {
 				return nil
 			}
 
-			entries, err := riot.FetchLeagueEntriesByPUUID(gctx, region, puuid, rt.RiotAPIKey)
+			entries, err := api.FetchLeagueEntriesByPUUID(gctx, region, puuid)
 			if err != nil {
-				slog.Warn("Failed to fetch solo queue entry for /leadboard", "guildID", guildID, "riotID", rows[idx].account.RiotID(), "error", err)
+				slog.Warn("Failed to refresh solo queue entry for /leadboard", "guildID", guildID, "riotID", rows[idx].account.RiotID(), "error", err)
+				failed.Add(1)
 				return nil
 			}
-			solo := riot.QueueEntry(entries, rankedSoloQueue)
-			if solo == nil {
-				return nil
+			entry := riot.LeagueEntry{QueueType: rankedSoloQueue}
+			if solo := riot.QueueEntry(entries, rankedSoloQueue); solo != nil {
+				entry = *solo
 			}
-
-			entryCopy := *solo
-			rows[idx].solo = &entryCopy
-			rows[idx].mmr = soloQueueMMR(entryCopy)
+			rows[idx].setSolo(entry)
 			return nil
 		})
 	}
 	_ = g.Wait()
+	return int(failed.Load())
+}
 
-	return rows, nil
+// leadboardPageRows returns the rows of one page; page is 1-based and clamped to the available pages.
+func leadboardPageRows(rows []leadboardRow, page int) []leadboardRow {
+	page = min(max(page, 1), leadboardPageCount(len(rows)))
+	start := (page - 1) * leadboardPageSize
+	return rows[start:min(start+leadboardPageSize, len(rows))]
 }
 
 func loadLeadboardRankIcons(ctx context.Context, db storage.SearchDB, rows []leadboardRow) map[string]string {
@@ -155,13 +222,24 @@ func sortLeadboardRows(rows []leadboardRow) {
 	})
 }
 
-func buildLeadboardEmbeds(rows []leadboardRow, rankIcons map[string]string) []*discordgo.MessageEmbed {
+// buildLeadboardEmbeds renders one page of sorted rows; page is 1-based and clamped to the available pages.
+// When stale rows of the page could not be refreshed from Riot, the description says their ranks may be out
+// of date.
+func buildLeadboardEmbeds(rows []leadboardRow, rankIcons map[string]string, page, stale int) []*discordgo.MessageEmbed {
+	pages := leadboardPageCount(len(rows))
+	page = min(max(page, 1), pages)
+	rows = leadboardPageRows(rows, page)
+
 	nickLines := make([]string, 0, len(rows))
 	rankLines := make([]string, 0, len(rows))
 	winRateLines := make([]string, 0, len(rows))
 	for _, row := range rows {
 		nickLines = append(nickLines, row.account.RiotID())
-		rankLines = append(rankLines, riot.RankedLineWithIcon(row.solo, rankIcons, "Unranked"))
+		if row.unknown {
+			rankLines = append(rankLines, "Unknown")
+		} else {
+			rankLines = append(rankLines, riot.RankedLineWithIcon(row.solo, rankIcons, "Unranked"))
+		}
 		winRateLines = append(winRateLines, riot.RecordLineOr(row.solo, "W", "L", "-"))
 	}
 
@@ -178,10 +256,39 @@ func buildLeadboardEmbeds(rows []leadboardRow, rankIcons map[string]string) []*d
 			{Name: "Win Rate", Value: leadboardFieldValue(winRateLines), Inline: true},
 		},
 	}
+	if stale > 0 {
+		embed.Description += fmt.Sprintf("\n%d of these ranks could not be refreshed from Riot and may be out of date.", stale)
+	}
+	if pages > 1 {
+		embed.Description = fmt.Sprintf("%s\nPage %d/%d", embed.Description, page, pages)
+	}
 	discord.ApplyDefaultFooter(embed)
 	return []*discordgo.MessageEmbed{embed}
 }
 
+func leadboardPageCount(rows int) int {
+	return max(1, (rows+leadboardPageSize-1)/leadboardPageSize)
+}
+
+// leadboardPageComponents returns the Previous/Next buttons, or an empty row set for a single page.
+func leadboardPageComponents(page, pages int) []discordgo.MessageComponent {
+	if pages <= 1 {
+		return []discordgo.MessageComponent{}
+	}
+	previous, errPrevious := discord.EncodeComponentID(leadboardPagePrefix, leadboardPageTTL, strconv.Itoa(page-1))
+	next, errNext := discord.EncodeComponentID(leadboardPagePrefix, leadboardPageTTL, strconv.Itoa(page+1))
+	if errPrevious != nil || errNext != nil {
+		slog.Warn("Failed to encode leaderboard page buttons", "error", errors.Join(errPrevious, errNext))
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Previous", Style: discordgo.SecondaryButton, CustomID: previous, Disabled: page <= 1},
+			discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: next, Disabled: page >= pages},
+		}},
+	}
+}
+
 func leadboardInfoEmbed(message string) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
 		Author: &discordgo.MessageEmbedAuthor{
@@ -196,62 +303,16 @@ func leadboardInfoEmbed(message string) *discordgo.MessageEmbed {
 }
 
 func soloQueueMMR(entry riot.LeagueEntry) int {
-	base := leadboardTierBaseMMR(entry.Tier)
-	if base < 0 {
+	value, ok := riot.RankValue(entry)
+	if !ok {
 		return leadboardMMRUnranked
 	}
-	if riot.HideDivisionForTier(entry.Tier) {
-		return base + entry.LeaguePoints
-	}
-	return base + leadboardSubTierMMR(entry.Rank) + entry.LeaguePoints
-}
-
-func leadboardTierBaseMMR(tier string) int {
-	switch strings.ToUpper(strings.TrimSpace(tier)) {
-	case "IRON":
-		return 0
-	case "BRONZE":
-		return 400
-	case "SILVER":
-		return 800
-	case "GOLD":
-		return 1200
-	case "PLATINUM":
-		return 1600
-	case "EMERALD":
-		return 2000
-	case "DIAMOND":
-		return 2400
-	case "MASTER", "GRANDMASTER", "CHALLENGER":
-		return leadboardRankedMMRBaseTop
-	default:
-		return -1
-	}
-}
-
-func leadboardSubTierMMR(subTier string) int {
-	switch strings.ToUpper(strings.TrimSpace(subTier)) {
-	case "IV":
-		return 0
-	case "III":
-		return 100
-	case "II":
-		return 200
-	case "I":
-		return 300
-	default:
-		return 0
-	}
+	return value
 }
 
 func leadboardFieldValue(lines []string) string {
 	if len(lines) == 0 {
 		return "-"
 	}
-	value := strings.Join(lines, "\n")
-	runes := []rune(value)
-	if len(runes) <= leadboardFieldValueLimit {
-		return value
-	}
-	return string(runes[:leadboardFieldValueLimit-3]) + "..."
+	return discord.TruncateText(strings.Join(lines, "\n"), discord.EmbedFieldValueLimit)
 }
@@ -1,12 +1,18 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/discord/embedtest"
 	"github.com/bingbr/League-API-bot/internal/riot"
 	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
 )
 
 func TestSoloQueueMMR(t *testing.T) {
@@ -49,6 +55,67 @@ func TestSoloQueueMMR(t *testing.T) {
 	}
 }
 
+func TestLeadboardRowsFromEntries(t *testing.T) {
+	gold := postgres.NewTrackRankSnapshot("br1", "p-gold", riot.LeagueEntry{QueueType: rankedSoloQueue, Tier: "GOLD", Rank: "II", LeaguePoints: 87}, "", postgres.TrackRankPhasePoll, time.Now())
+	unranked := postgres.NewTrackRankSnapshot("br1", "p-unranked", riot.LeagueEntry{QueueType: rankedSoloQueue}, "", postgres.TrackRankPhasePoll, time.Now())
+	rows := leadboardRowsFromEntries([]postgres.TrackLeaderboardEntry{
+		{Account: postgres.TrackedAccount{PUUID: "p-new"}},
+		{Account: postgres.TrackedAccount{PUUID: "p-unranked"}, Snapshot: &unranked},
+		{Account: postgres.TrackedAccount{PUUID: "p-gold"}, Snapshot: &gold},
+	})
+	sortLeadboardRows(rows)
+
+	if rows[0].account.PUUID != "p-gold" || rows[0].solo == nil || rows[0].mmr != 1487 {
+		t.Fatalf("first row = %+v, want the stored GOLD II rank", rows[0])
+	}
+	for _, row := range rows[1:] {
+		if row.solo != nil || row.mmr != leadboardMMRUnranked || row.unknown != (row.account.PUUID == "p-new") {
+			t.Fatalf("row %s = %+v, want unranked and unknown only without a snapshot", row.account.PUUID, row)
+		}
+	}
+}
+
+type leagueEntriesByPUUID map[string][]riot.LeagueEntry
+
+func (f leagueEntriesByPUUID) FetchLeagueEntriesByPUUID(_ context.Context, _, puuid string) ([]riot.LeagueEntry, error) {
+	entries, ok := f[puuid]
+	if !ok {
+		return nil, errors.New("rate limited")
+	}
+	return entries, nil
+}
+
+func TestRefreshLeadboardRowsKeepsStoredRankOnFailure(t *testing.T) {
+	stored := riot.LeagueEntry{QueueType: rankedSoloQueue, Tier: "SILVER", Rank: "I", LeaguePoints: 10}
+	rows := []leadboardRow{
+		{account: postgres.TrackedAccount{PlatformRegion: "br1", PUUID: "p-fresh"}, mmr: leadboardMMRUnranked, unknown: true},
+		{account: postgres.TrackedAccount{PlatformRegion: "br1", PUUID: "p-failed"}, solo: &stored, mmr: soloQueueMMR(stored)},
+	}
+	api := leagueEntriesByPUUID{"p-fresh": {{QueueType: rankedSoloQueue, Tier: "GOLD", Rank: "IV", LeaguePoints: 5}}}
+
+	if failed := refreshLeadboardRows(t.Context(), api, "g1", rows); failed != 1 {
+		t.Fatalf("refreshLeadboardRows() failed = %d, want 1", failed)
+	}
+	if rows[0].unknown || rows[0].solo == nil || rows[0].solo.Tier != "GOLD" {
+		t.Fatalf("refreshed row = %+v, want the fetched GOLD IV rank", rows[0])
+	}
+	if rows[1].solo == nil || rows[1].solo.Tier != "SILVER" {
+		t.Fatalf("failed row = %+v, want the stored SILVER I rank", rows[1])
+	}
+
+	embed := buildLeadboardEmbeds(rows, map[string]string{}, 1, 1)[0]
+	if !strings.Contains(embed.Description, "1 of these ranks could not be refreshed") {
+		t.Fatalf("description = %q, want the partial refresh note", embed.Description)
+	}
+}
+
+func TestBuildLeadboardEmbedsShowsUnknownRank(t *testing.T) {
+	rows := []leadboardRow{{account: postgres.TrackedAccount{NickName: "New", TagLine: "BR1"}, mmr: leadboardMMRUnranked, unknown: true}}
+	if got := buildLeadboardEmbeds(rows, map[string]string{}, 1, 1)[0].Fields[1].Value; got != "Unknown" {
+		t.Fatalf("rank field = %q, want Unknown", got)
+	}
+}
+
 func TestSortLeadboardRows(t *testing.T) {
 	rows := []leadboardRow{
 		{
@@ -85,7 +152,7 @@ func TestBuildLeadboardEmbeds(t *testing.T) {
 		})
 	}
 
-	embeds := buildLeadboardEmbeds(rows, map[string]string{})
+	embeds := buildLeadboardEmbeds(rows, map[string]string{}, 1, 0)
 	if len(embeds) != 1 {
 		t.Fatalf("len(embeds) = %d, want 1", len(embeds))
 	}
@@ -102,3 +169,82 @@ func TestBuildLeadboardEmbeds(t *testing.T) {
 		t.Fatalf("Nick field should not include numeric prefix: %q", embeds[0].Fields[0].Value)
 	}
 }
+
+func TestBuildLeadboardEmbedsGolden(t *testing.T) {
+	tiers := []string{"CHALLENGER", "GRANDMASTER", "MASTER", "DIAMOND", "EMERALD", "PLATINUM", "GOLD", "SILVER", "BRONZE", "IRON"}
+	rows := make([]leadboardRow, 0, 12)
+	for i := range 12 {
+		row := leadboardRow{
+			account: postgres.TrackedAccount{NickName: fmt.Sprintf("Player%02d", i+1), TagLine: "NA1"},
+			mmr:     3000 - 250*i,
+		}
+		if i < len(tiers) {
+			row.solo = &riot.LeagueEntry{QueueType: rankedSoloQueue, Tier: tiers[i], Rank: "II", LeaguePoints: 10 * i, Wins: 50 + i, Losses: 40}
+		}
+		rows = append(rows, row)
+	}
+	rows[2].account.NickName = "페이커는최고의선수입니다"
+	rows[4].account.NickName = "ThisRiotIDIsFarTooLong"
+	rankIcons := map[string]string{"challenger": "<:Challenger:1>", "master": "<:Master:2>", "gold": "<:Gold:3>", "unranked": "<:Unranked:4>"}
+
+	embedtest.Golden(t, "leaderboard_page1", buildLeadboardEmbeds(rows, rankIcons, 1, 0)...)
+	embedtest.Golden(t, "leaderboard_page2", buildLeadboardEmbeds(rows, rankIcons, 2, 0)...)
+}
+
+func TestBuildLeadboardEmbeds_Paginates(t *testing.T) {
+	rows := make([]leadboardRow, 0, 23)
+	for i := range 23 {
+		rows = append(rows, leadboardRow{
+			account: postgres.TrackedAccount{NickName: fmt.Sprintf("Player%02d", i+1), TagLine: "NA1"},
+			mmr:     1000 - i,
+		})
+	}
+
+	tests := []struct {
+		page      int
+		wantPage  string
+		wantFirst string
+		wantLines int
+	}{
+		{page: 1, wantPage: "Page 1/3", wantFirst: "Player01#NA1", wantLines: 10},
+		{page: 3, wantPage: "Page 3/3", wantFirst: "Player21#NA1", wantLines: 3},
+		{page: 9, wantPage: "Page 3/3", wantFirst: "Player21#NA1", wantLines: 3},
+		{page: 0, wantPage: "Page 1/3", wantFirst: "Player01#NA1", wantLines: 10},
+	}
+	for _, tc := range tests {
+		embed := buildLeadboardEmbeds(rows, map[string]string{}, tc.page, 0)[0]
+		if !strings.HasSuffix(embed.Description, tc.wantPage) {
+			t.Fatalf("page %d description = %q, want suffix %q", tc.page, embed.Description, tc.wantPage)
+		}
+		lines := strings.Split(embed.Fields[0].Value, "\n")
+		if lines[0] != tc.wantFirst || len(lines) != tc.wantLines {
+			t.Fatalf("page %d nicks = %q, want first %q and %d lines", tc.page, lines, tc.wantFirst, tc.wantLines)
+		}
+	}
+
+	if embed := buildLeadboardEmbeds(rows[:5], map[string]string{}, 1, 0)[0]; embed.Description != leadboardDescription {
+		t.Fatalf("single page description = %q", embed.Description)
+	}
+}
+
+func TestLeadboardPageComponents(t *testing.T) {
+	if got := leadboardPageComponents(1, 1); len(got) != 0 {
+		t.Fatalf("single page components = %#v, want none", got)
+	}
+
+	row, ok := leadboardPageComponents(1, 3)[0].(discordgo.ActionsRow)
+	if !ok || len(row.Components) != 2 {
+		t.Fatalf("components = %#v, want one row with two buttons", row)
+	}
+	previous, next := row.Components[0].(discordgo.Button), row.Components[1].(discordgo.Button)
+	if !previous.Disabled || next.Disabled {
+		t.Fatalf("first page buttons disabled = %v/%v, want true/false", previous.Disabled, next.Disabled)
+	}
+	id, err := discord.ParseComponentID(next.CustomID)
+	if err != nil || id.Prefix != leadboardPagePrefix || id.Arg(0) != "2" || id.Expires.IsZero() {
+		t.Fatalf("next custom id = %+v, %v", id, err)
+	}
+	if _, ok := LeadboardCommand.Components[leadboardPagePrefix]; !ok {
+		t.Fatal("leaderboard page handler is not registered")
+	}
+}
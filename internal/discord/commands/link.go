@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	linkTimeout      = 15 * time.Second
+	linkEmbedColor   = 0x5865F2
+	linkVerifyWindow = 10 * time.Minute
+	linkFirstIconID  = 1 // Profile icons 0-28 are owned by every account; 0 is skipped as too common.
+	linkLastIconID   = 28
+)
+
+var (
+	errAccountNotLinked = errors.New("discord user has no linked account")
+	errLinkNotPending   = errors.New("no pending link verification")
+	errLinkExpired      = errors.New("link verification expired")
+	errLinkIconMismatch = errors.New("profile icon does not match")
+
+	// pickLinkIcon chooses the verification icon; tests replace it to get a stable value.
+	pickLinkIcon = func(current int) int {
+		return linkIconFromRoll(current, rand.IntN(linkLastIconID-linkFirstIconID))
+	}
+)
+
+var linkCommandContexts = &[]discordgo.InteractionContextType{
+	discordgo.InteractionContextGuild,
+	discordgo.InteractionContextBotDM,
+	discordgo.InteractionContextPrivateChannel,
+}
+
+var linkIntegrationTypes = &[]discordgo.ApplicationIntegrationType{
+	discordgo.ApplicationIntegrationGuildInstall,
+	discordgo.ApplicationIntegrationUserInstall,
+}
+
+// -- Command Definition --
+var LinkCommand = &discord.Command{
+	Data: &discordgo.ApplicationCommand{
+		Name:             "link",
+		Description:      "Link your Discord account to your Riot account.",
+		IntegrationTypes: linkIntegrationTypes,
+		Contexts:         linkCommandContexts,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "account",
+				Description: "Start linking a Riot account.",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     discord.AccountTargetOptions(),
+			},
+			{
+				Name:        "verify",
+				Description: "Finish linking after changing your profile icon.",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        "remove",
+				Description: "Unlink your Riot account.",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+		},
+	},
+	Handler: handleLink,
+}
+
+var MeCommand = &discord.Command{
+	Data: &discordgo.ApplicationCommand{
+		Name:             "me",
+		Description:      "View information about your linked account.",
+		IntegrationTypes: linkIntegrationTypes,
+		Contexts:         linkCommandContexts,
+	},
+	Handler: handleMe,
+}
+
+func handleLink(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+	_, userID := discord.InteractionUserID(i)
+	subcommand, options, ok := trackSubcommand(i)
+	if !ok || userID == "" {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+
+	switch subcommand {
+	case "account":
+		region, nick, tag, validationErr := discord.ParseAccountTargetOptions(i, options)
+		if validationErr != "" {
+			discord.RespondWithError(s, i, validationErr)
+			return
+		}
+		runLinkCommand(s, i, func(ctx context.Context) (*discordgo.MessageEmbed, error) {
+			return startAccountLink(ctx, rt, userID, region, nick, tag)
+		}, func(err error) string {
+			if msg, ok := discord.MapAccountNotFoundHint(i, err, nick, tag); ok {
+				return msg
+			}
+			return "Could not connect to Riot servers.\nPlease try again later."
+		})
+	case "verify":
+		runLinkCommand(s, i, func(ctx context.Context) (*discordgo.MessageEmbed, error) {
+			return verifyAccountLink(ctx, rt, userID)
+		}, mapLinkVerifyError)
+	case "remove":
+		runLinkCommand(s, i, func(ctx context.Context) (*discordgo.MessageEmbed, error) {
+			removed, err := rt.Database.RemoveAccountLink(ctx, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to remove account link: %w", err)
+			}
+			if !removed {
+				return linkEmbed("No Riot account is linked to you.", 0), nil
+			}
+			return linkEmbed("Your Riot account was unlinked.", 0), nil
+		}, func(error) string {
+			return "Could not unlink your account right now. Please try again."
+		})
+	default:
+		discord.RespondWithError(s, i, "Invalid command input.")
+	}
+}
+
+func handleMe(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+	runLinkedSearch(s, i, rt)
+}
+
+func runLinkCommand(s *discordgo.Session, i *discordgo.InteractionCreate, exec func(ctx context.Context) (*discordgo.MessageEmbed, error), mapErr discord.DeferredErrorMapper) {
+	if err := discord.RunDeferredEmbedCommand(s, i, linkTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
+		embed, err := exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []*discordgo.MessageEmbed{embed}, nil
+	}, mapErr); err != nil {
+		slog.Error("Failed to handle deferred link interaction", "error", err)
+	}
+}
+
+// startAccountLink stores a pending verification asking for a default icon the account is not using yet.
+func startAccountLink(ctx context.Context, rt Runtime, userID, region, nick, tag string) (*discordgo.MessageEmbed, error) {
+	platformRegion := riot.NormalizePlatformRegion(region)
+	account, err := rt.Riot.FetchAccountByRiotID(ctx, platformRegion, nick, tag)
+	if err != nil {
+		return nil, err
+	}
+	summoner, err := rt.Riot.FetchSummonerByPUUID(ctx, platformRegion, account.PUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := postgres.AccountLinkVerification{
+		UserID:         userID,
+		PlatformRegion: platformRegion,
+		PUUID:          account.PUUID,
+		GameName:       account.GameName,
+		TagLine:        account.TagLine,
+		ProfileIconID:  pickLinkIcon(summoner.ProfileIconID),
+		ExpiresAt:      time.Now().UTC().Add(linkVerifyWindow),
+	}
+	if err := rt.Database.UpsertAccountLinkVerification(ctx, verification); err != nil {
+		return nil, fmt.Errorf("failed to save link verification: %w", err)
+	}
+	return linkStartEmbed(verification), nil
+}
+
+// verifyAccountLink checks the pending icon against the live profile and stores the link on a match.
+func verifyAccountLink(ctx context.Context, rt Runtime, userID string) (*discordgo.MessageEmbed, error) {
+	verification, found, err := rt.Database.AccountLinkVerification(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load link verification: %w", err)
+	}
+	if !found {
+		return nil, errLinkNotPending
+	}
+	if time.Now().UTC().After(verification.ExpiresAt) {
+		return nil, errLinkExpired
+	}
+
+	summoner, err := rt.Riot.FetchSummonerByPUUID(ctx, verification.PlatformRegion, verification.PUUID)
+	if err != nil {
+		return nil, err
+	}
+	if summoner.ProfileIconID != verification.ProfileIconID {
+		return nil, errLinkIconMismatch
+	}
+
+	link, completed, err := rt.Database.CompleteAccountLink(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save account link: %w", err)
+	}
+	if !completed {
+		return nil, errLinkNotPending
+	}
+	message := fmt.Sprintf("**%s** is now linked to <@%s>.\nYou can change your profile icon back. Use `/me` to view your account.", link.RiotID(), userID)
+	return linkEmbed(message, summoner.ProfileIconID), nil
+}
+
+// linkIconFromRoll maps a roll in [0, 27) to a default icon, skipping the one the account already uses
+// so the verification always needs a change.
+func linkIconFromRoll(current, roll int) int {
+	icon := linkFirstIconID + roll
+	if current >= linkFirstIconID && current <= linkLastIconID && icon >= current {
+		icon++
+	}
+	return icon
+}
+
+func mapLinkVerifyError(err error) string {
+	switch {
+	case errors.Is(err, errLinkNotPending):
+		return "There is no account waiting for verification.\nUse `/link account` first."
+	case errors.Is(err, errLinkExpired):
+		return "The verification expired.\nUse `/link account` to get a new profile icon."
+	case errors.Is(err, errLinkIconMismatch):
+		return "Your profile icon does not match yet.\nIt can take a minute to update after you change it."
+	default:
+		return "Could not connect to Riot servers.\nPlease try again later."
+	}
+}
+
+func linkStartEmbed(v postgres.AccountLinkVerification) *discordgo.MessageEmbed {
+	embed := linkEmbed(fmt.Sprintf(
+		"To prove you own **%s**, change its profile icon to the one shown here, then run `/link verify` <t:%d:R>.",
+		v.RiotID(), v.ExpiresAt.Unix()), v.ProfileIconID)
+	embed.Title = "Verify Account"
+	return embed
+}
+
+func linkEmbed(message string, iconID int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    "Account Link",
+			IconURL: cdn.ProfileIconURL(29),
+		},
+		Description: message,
+		Color:       linkEmbedColor,
+	}
+	if iconID > 0 {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: cdn.ProfileIconURL(iconID)}
+	}
+	discord.ApplyDefaultFooter(embed)
+	return embed
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestLinkIconFromRoll(t *testing.T) {
+	tests := []struct {
+		current, roll, want int
+	}{
+		{current: 5119, roll: 0, want: 1},
+		{current: 5119, roll: 26, want: 27},
+		{current: 1, roll: 0, want: 2},
+		{current: 10, roll: 8, want: 9},
+		{current: 10, roll: 9, want: 11},
+		{current: 28, roll: 26, want: 27},
+		{current: 27, roll: 26, want: 28},
+	}
+	for _, tc := range tests {
+		got := linkIconFromRoll(tc.current, tc.roll)
+		if got != tc.want {
+			t.Fatalf("linkIconFromRoll(%d, %d) = %d, want %d", tc.current, tc.roll, got, tc.want)
+		}
+		if got == tc.current || got < linkFirstIconID || got > linkLastIconID {
+			t.Fatalf("linkIconFromRoll(%d, %d) = %d is not a usable verification icon", tc.current, tc.roll, got)
+		}
+	}
+}
+
+func TestMapLinkVerifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: fmt.Errorf("wrapped: %w", errLinkNotPending), want: "/link account` first"},
+		{err: errLinkExpired, want: "expired"},
+		{err: errLinkIconMismatch, want: "does not match"},
+		{err: errors.New("boom"), want: "Riot servers"},
+	}
+	for _, tc := range tests {
+		if got := mapLinkVerifyError(tc.err); !strings.Contains(got, tc.want) {
+			t.Fatalf("mapLinkVerifyError(%v) = %q, want it to contain %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestLinkStartEmbed(t *testing.T) {
+	expires := time.Unix(1_700_000_000, 0).UTC()
+	embed := linkStartEmbed(postgres.AccountLinkVerification{GameName: "Ahri", TagLine: "BR1", ProfileIconID: 12, ExpiresAt: expires})
+	if embed.Thumbnail == nil || embed.Thumbnail.URL != cdn.ProfileIconURL(12) {
+		t.Fatalf("thumbnail = %+v, want icon 12", embed.Thumbnail)
+	}
+	if !strings.Contains(embed.Description, "**Ahri#BR1**") || !strings.Contains(embed.Description, "<t:1700000000:R>") {
+		t.Fatalf("description = %q", embed.Description)
+	}
+}
+
+func TestSearchOptionsAreOptional(t *testing.T) {
+	for _, option := range SearchCommand.Data.Options {
+		if option.Required {
+			t.Fatalf("/search option %q is required; it should fall back to the linked account", option.Name)
+		}
+	}
+	for _, option := range discord.AccountTargetOptions() {
+		if !option.Required {
+			t.Fatalf("shared option %q lost Required", option.Name)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/lpgraph"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	lpGraphTimeout     = 15 * time.Second
+	lpGraphEmbedColor  = 0x3498DB
+	lpGraphIconID      = 4568
+	lpGraphDefaultDays = 30
+	lpGraphFileName    = "lp-graph.png"
+)
+
+var errLPGraphAccountNotTracked = errors.New("account is not tracked in this server")
+
+// -- Command Definition --
+var LPCommand = &discord.Command{
+	Data: &discordgo.ApplicationCommand{
+		Name:        "lp",
+		Description: "View the LP progression of a tracked account.",
+		IntegrationTypes: &[]discordgo.ApplicationIntegrationType{
+			discordgo.ApplicationIntegrationGuildInstall,
+		},
+		Contexts: &[]discordgo.InteractionContextType{
+			discordgo.InteractionContextGuild,
+		},
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "graph",
+				Description: "Draw the Solo/Duo LP history of a tracked account.",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "account",
+						Description:  "Select account in format nickname#tagline.",
+						MinLength:    new(7),
+						MaxLength:    22,
+						Autocomplete: true,
+						Required:     true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "days",
+						Description: "How far back to look.",
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "7 days", Value: 7},
+							{Name: "30 days", Value: 30},
+							{Name: "90 days", Value: 90},
+						},
+					},
+				},
+			},
+		},
+	},
+	Handler: handleLP,
+}
+
+func handleLP(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		handleLPAutocomplete(s, i)
+		return
+	}
+	guildID, ok := discord.RequireGuildCommand(s, i)
+	if !ok {
+		return
+	}
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil) {
+		return
+	}
+	subcommand, options, ok := trackSubcommand(i)
+	if !ok || subcommand != "graph" {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+
+	riotID := strings.TrimSpace(discord.OptionValueByName(options, "account"))
+	days := lpGraphDefaultDays
+	if value, ok := discord.OptionIntByName(options, "days"); ok && value > 0 {
+		days = value
+	}
+
+	if err := discord.RunDeferredCommand(s, i, lpGraphTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		account, found, err := rt.Database.TrackedAccountByRiotID(ctx, guildID, riotID)
+		if err != nil {
+			return discord.DeferredResponse{}, fmt.Errorf("failed to load tracked account: %w", err)
+		}
+		if !found {
+			return discord.DeferredResponse{}, errLPGraphAccountNotTracked
+		}
+
+		to := time.Now().UTC()
+		from := to.AddDate(0, 0, -days)
+		snapshots, err := rt.Database.ListTrackRankSnapshots(ctx, account.PUUID, rankedSoloQueue, from)
+		if err != nil {
+			return discord.DeferredResponse{}, fmt.Errorf("failed to load rank snapshots: %w", err)
+		}
+		return buildLPGraphResponse(account, lpGraphPoints(snapshots), days, from, to)
+	}, func(err error) string {
+		if errors.Is(err, errLPGraphAccountNotTracked) {
+			return fmt.Sprintf("**%s** is not tracked in this server.\nUse `/track add` first.", riotID)
+		}
+		return "Could not draw the LP graph right now. Please try again."
+	}); err != nil {
+		slog.Error("Failed to handle deferred lp interaction", "error", err)
+	}
+}
+
+func handleLPAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	guildID := strings.TrimSpace(i.GuildID)
+	rt := currentRuntime()
+	_, options, ok := trackSubcommand(i)
+	if !ok || guildID == "" || rt.Database == nil {
+		respondTrackAutocompleteChoices(s, i, nil)
+		return
+	}
+	respondTrackedAccountAutocomplete(s, i, rt.Database, guildID, discord.FocusedOptionValue(options))
+}
+
+// lpGraphPoints converts snapshots to the same flattened scale the leaderboard sorts by.
+// Unranked snapshots are skipped, and consecutive duplicates are collapsed so idle periods draw flat.
+func lpGraphPoints(snapshots []postgres.TrackRankSnapshot) []lpgraph.Point {
+	points := make([]lpgraph.Point, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		value, ok := riot.RankValue(snapshot.LeagueEntry())
+		if !ok {
+			continue
+		}
+		if n := len(points); n > 1 && points[n-1].Value == value && points[n-2].Value == value {
+			points[n-1].At = snapshot.CapturedAt
+			continue
+		}
+		points = append(points, lpgraph.Point{At: snapshot.CapturedAt, Value: value})
+	}
+	return points
+}
+
+func buildLPGraphResponse(account postgres.TrackedAccount, points []lpgraph.Point, days int, from, to time.Time) (discord.DeferredResponse, error) {
+	embed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    fmt.Sprintf("LP History · Last %d days", days),
+			IconURL: cdn.ProfileIconURL(lpGraphIconID),
+		},
+		Title: account.RiotID(),
+		Color: lpGraphEmbedColor,
+	}
+	discord.ApplyDefaultFooter(embed)
+
+	if len(points) == 0 {
+		embed.Description = "No ranked Solo/Duo data recorded in this period yet.\nSnapshots are taken after tracked games and periodically while the account is tracked."
+		return discord.DeferredResponse{Embeds: []*discordgo.MessageEmbed{embed}}, nil
+	}
+
+	image, err := lpgraph.Render(points, lpgraph.Options{From: from, To: to})
+	if err != nil {
+		return discord.DeferredResponse{}, fmt.Errorf("render lp graph: %w", err)
+	}
+	first, last := points[0].Value, points[len(points)-1].Value
+	embed.Description = fmt.Sprintf("**Net**: %+d LP since <t:%d:R>", last-first, points[0].At.Unix())
+	embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + lpGraphFileName}
+	return discord.DeferredResponse{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Files: []*discordgo.File{{
+			Name:        lpGraphFileName,
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(image),
+		}},
+	}, nil
+}
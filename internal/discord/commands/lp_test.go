@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/lpgraph"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestLPGraphPoints(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := func(hours int, tier, rank string, lp int) postgres.TrackRankSnapshot {
+		return postgres.TrackRankSnapshot{Tier: tier, Rank: rank, LeaguePoints: lp, CapturedAt: start.Add(time.Duration(hours) * time.Hour)}
+	}
+
+	points := lpGraphPoints([]postgres.TrackRankSnapshot{
+		snapshot(0, "", "", 0),
+		snapshot(1, "GOLD", "II", 54),
+		snapshot(2, "GOLD", "I", 10),
+		snapshot(3, "GOLD", "I", 10),
+		snapshot(4, "GOLD", "I", 10),
+		snapshot(5, "MASTER", "I", 120),
+	})
+
+	want := []lpgraph.Point{
+		{At: start.Add(time.Hour), Value: 1454},
+		{At: start.Add(2 * time.Hour), Value: 1510},
+		{At: start.Add(4 * time.Hour), Value: 1510},
+		{At: start.Add(5 * time.Hour), Value: 2920},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("lpGraphPoints() len = %d, want %d (%v)", len(points), len(want), points)
+	}
+	for idx := range want {
+		if !points[idx].At.Equal(want[idx].At) || points[idx].Value != want[idx].Value {
+			t.Fatalf("point[%d] = %+v, want %+v", idx, points[idx], want[idx])
+		}
+	}
+}
+
+func TestBuildLPGraphResponse(t *testing.T) {
+	account := postgres.TrackedAccount{NickName: "Bekko", TagLine: "Ekko"}
+	to := time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+
+	empty, err := buildLPGraphResponse(account, nil, 7, from, to)
+	if err != nil {
+		t.Fatalf("buildLPGraphResponse(empty) error = %v", err)
+	}
+	if len(empty.Files) != 0 || !strings.Contains(empty.Embeds[0].Description, "No ranked Solo/Duo data") {
+		t.Fatalf("empty response = %+v", empty.Embeds[0])
+	}
+
+	points := []lpgraph.Point{{At: from.Add(time.Hour), Value: 1454}, {At: to.Add(-time.Hour), Value: 1510}}
+	response, err := buildLPGraphResponse(account, points, 7, from, to)
+	if err != nil {
+		t.Fatalf("buildLPGraphResponse() error = %v", err)
+	}
+	if len(response.Files) != 1 || response.Files[0].Name != lpGraphFileName {
+		t.Fatalf("response.Files = %+v", response.Files)
+	}
+	embed := response.Embeds[0]
+	if embed.Title != "Bekko#Ekko" || embed.Image == nil || embed.Image.URL != "attachment://"+lpGraphFileName {
+		t.Fatalf("embed = %+v", embed)
+	}
+	if !strings.Contains(embed.Description, "+56 LP") {
+		t.Fatalf("embed.Description = %q", embed.Description)
+	}
+}
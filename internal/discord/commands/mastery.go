@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	masteryTimeout      = 15 * time.Second
+	masteryEmbedColor   = 0x9B59B6
+	masteryIconID       = 5367
+	masteryCacheTTL     = time.Hour
+	masteryCacheCount   = 10
+	masteryDefaultCount = 5
+	searchMasteryCount  = 3
+)
+
+// -- Command Definition --
+var MasteryCommand = &discord.Command{
+	Data: &discordgo.ApplicationCommand{
+		Name:        "mastery",
+		Description: "View the champions an account has mastered the most.",
+		IntegrationTypes: &[]discordgo.ApplicationIntegrationType{
+			discordgo.ApplicationIntegrationGuildInstall,
+			discordgo.ApplicationIntegrationUserInstall,
+		},
+		Contexts: &[]discordgo.InteractionContextType{
+			discordgo.InteractionContextGuild,
+			discordgo.InteractionContextBotDM,
+			discordgo.InteractionContextPrivateChannel,
+		},
+		Options: append(discord.AccountTargetOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "count",
+				Description: "Number of champions to show.",
+				MinValue:    new(1.0),
+				MaxValue:    masteryCacheCount,
+			},
+		),
+	},
+	Handler: handleMastery,
+}
+
+type masterySummary struct {
+	Masteries []riot.ChampionMastery
+	Score     int
+}
+
+func handleMastery(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	region, nick, tag, validationErr := discord.ParseAccountTargetOptions(i, options)
+	if validationErr != "" {
+		discord.RespondWithError(s, i, validationErr)
+		return
+	}
+	count := masteryDefaultCount
+	if value, ok := discord.OptionIntByName(options, "count"); ok {
+		count = min(max(value, 1), masteryCacheCount)
+	}
+
+	if err := discord.RunDeferredEmbedCommand(s, i, masteryTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
+		account, err := rt.Riot.FetchAccountByRiotID(ctx, region, nick, tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account: %w", err)
+		}
+		summary, err := loadChampionMasteries(ctx, rt, region, account.PUUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch champion masteries: %w", err)
+		}
+
+		masteries := summary.Masteries[:min(count, len(summary.Masteries))]
+		champions := loadOrEmptyDisplays(func() (map[int]postgres.ChampionDisplay, error) {
+			return rt.Database.ChampionDisplayByIDs(ctx, masteryChampionIDs(masteries))
+		})
+		return []*discordgo.MessageEmbed{buildMasteryEmbed(account, masterySummary{Masteries: masteries, Score: summary.Score}, champions)}, nil
+	}, func(err error) string {
+		return mapSearchDeferredError(i, err, nick, tag)
+	}); err != nil {
+		slog.Error("Failed to handle deferred mastery interaction", "error", err)
+	}
+}
+
+// loadChampionMasteries serves the top masteries from riot_champion_masteries and refreshes them once expired.
+func loadChampionMasteries(ctx context.Context, rt Runtime, region, puuid string) (masterySummary, error) {
+	platformRegion := riot.NormalizePlatformRegion(region)
+	if platformRegion == "" {
+		return masterySummary{}, fmt.Errorf("region is invalid")
+	}
+
+	now := time.Now().UTC()
+	var (
+		cached masterySummary
+		found  bool
+	)
+	if rt.Database != nil {
+		masteries, score, expiresAt, ok, err := rt.Database.GetChampionMasteries(ctx, platformRegion, puuid)
+		if err != nil {
+			slog.Warn("Failed to load cached champion masteries", "region", platformRegion, "error", err)
+		}
+		cached, found = masterySummary{Masteries: masteries, Score: score}, ok
+		if ok && now.Before(expiresAt) {
+			return cached, nil
+		}
+	}
+
+	var fetched masterySummary
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		fetched.Masteries, err = rt.Riot.FetchTopChampionMasteries(gctx, platformRegion, puuid, masteryCacheCount)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		fetched.Score, err = rt.Riot.FetchChampionMasteryScore(gctx, platformRegion, puuid)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		if found {
+			slog.Warn("Refresh failed, serving stale champion masteries", "region", platformRegion, "error", err)
+			return cached, nil
+		}
+		return masterySummary{}, err
+	}
+
+	if rt.Database != nil {
+		if err := rt.Database.UpsertChampionMasteries(ctx, platformRegion, puuid, fetched.Masteries, fetched.Score, now, now.Add(masteryCacheTTL)); err != nil {
+			slog.Warn("Failed to cache champion masteries", "region", platformRegion, "error", err)
+		}
+	}
+	return fetched, nil
+}
+
+func masteryChampionIDs(masteries []riot.ChampionMastery) []int {
+	ids := make([]int, 0, len(masteries))
+	for _, mastery := range masteries {
+		ids = append(ids, mastery.ChampionID)
+	}
+	return mergeChampionIDs(ids)
+}
+
+func buildMasteryEmbed(account riot.RiotAccount, summary masterySummary, champions map[int]postgres.ChampionDisplay) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    "Champion Mastery",
+			IconURL: cdn.ProfileIconURL(masteryIconID),
+		},
+		Title:       riot.FormatRiotID(account.GameName, account.TagLine),
+		Description: fmt.Sprintf("**Mastery score**: %s", formatThousands(summary.Score)),
+		Color:       masteryEmbedColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Top Champions", Value: strings.Join(masteryLines(summary.Masteries, champions, true), "\n")},
+		},
+	}
+	discord.ApplyDefaultFooter(embed)
+	return embed
+}
+
+func masteryLines(masteries []riot.ChampionMastery, champions map[int]postgres.ChampionDisplay, withLastPlayed bool) []string {
+	if len(masteries) == 0 {
+		return []string{"No champion mastery yet."}
+	}
+
+	lines := make([]string, 0, len(masteries))
+	for _, mastery := range masteries {
+		champion := championLines([]int{mastery.ChampionID}, champions)[0]
+		line := fmt.Sprintf("%s · Lv. %d · %s pts", champion, mastery.ChampionLevel, formatThousands(mastery.ChampionPoints))
+		if withLastPlayed && mastery.LastPlayTime > 0 {
+			line = fmt.Sprintf("%s · <t:%d:R>", line, mastery.LastPlayTime/1000)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func formatThousands(value int) string {
+	digits := strconv.Itoa(max(value, 0))
+	var b strings.Builder
+	for idx, digit := range digits {
+		if idx > 0 && (len(digits)-idx)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
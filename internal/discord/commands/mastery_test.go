@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestFormatThousands(t *testing.T) {
+	tests := map[int]string{
+		0:       "0",
+		999:     "999",
+		1000:    "1,000",
+		1234567: "1,234,567",
+		-5:      "0",
+	}
+	for value, want := range tests {
+		if got := formatThousands(value); got != want {
+			t.Fatalf("formatThousands(%d) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestBuildMasteryEmbed(t *testing.T) {
+	embed := buildMasteryEmbed(
+		riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"},
+		masterySummary{
+			Score: 1234,
+			Masteries: []riot.ChampionMastery{
+				{ChampionID: 245, ChampionLevel: 52, ChampionPoints: 612345, LastPlayTime: 1770422047398},
+				{ChampionID: 103, ChampionLevel: 12, ChampionPoints: 98000},
+			},
+		},
+		map[int]postgres.ChampionDisplay{245: {Name: "Ekko", DiscordIcon: "<:Ekko:1>"}},
+	)
+
+	if embed.Title != "Bekko#Ekko" {
+		t.Fatalf("embed.Title = %q", embed.Title)
+	}
+	if embed.Description != "**Mastery score**: 1,234" {
+		t.Fatalf("embed.Description = %q", embed.Description)
+	}
+	lines := strings.Split(embed.Fields[0].Value, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("len(lines) = %d, want 2", len(lines))
+	}
+	if lines[0] != "<:Ekko:1> Ekko · Lv. 52 · 612,345 pts · <t:1770422047:R>" {
+		t.Fatalf("lines[0] = %q", lines[0])
+	}
+	if lines[1] != "ID 103 · Lv. 12 · 98,000 pts" {
+		t.Fatalf("lines[1] = %q", lines[1])
+	}
+}
+
+func TestBuildMasteryEmbed_Empty(t *testing.T) {
+	embed := buildMasteryEmbed(riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"}, masterySummary{}, nil)
+	if embed.Fields[0].Value != "No champion mastery yet." {
+		t.Fatalf("embed.Fields[0].Value = %q", embed.Fields[0].Value)
+	}
+}
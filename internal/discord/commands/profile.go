@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	profileTimeout         = 15 * time.Second
+	profileUserCommandName = "League profile"
+)
+
+var errProfileNotFound = errors.New("discord user has no league profile")
+
+// -- Command Definition --
+var ProfileCommand = &discord.Command{
+	Data: &discordgo.ApplicationCommand{
+		Name:             "profile",
+		Description:      "Choose the account shown by the League profile app.",
+		IntegrationTypes: linkIntegrationTypes,
+		Contexts:         linkCommandContexts,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "set",
+				Description: "Set the Riot account shown when someone opens your League profile.",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     discord.AccountTargetOptions(),
+			},
+			{
+				Name:        "clear",
+				Description: "Remove the Riot account set with /profile set.",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+		},
+	},
+	Handler: handleProfile,
+}
+
+// ProfileUserCommand is the user context-menu entry: right-click a member → Apps → League profile.
+var ProfileUserCommand = &discord.Command{
+	Data: &discordgo.ApplicationCommand{
+		Name:             profileUserCommandName,
+		Type:             discordgo.UserApplicationCommand,
+		IntegrationTypes: linkIntegrationTypes,
+		Contexts:         linkCommandContexts,
+	},
+	Handler: handleProfileUserCommand,
+}
+
+func handleProfile(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+	_, userID := discord.InteractionUserID(i)
+	subcommand, options, ok := trackSubcommand(i)
+	if !ok || userID == "" {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+
+	switch subcommand {
+	case "set":
+		region, nick, tag, validationErr := discord.ParseAccountTargetOptions(i, options)
+		if validationErr != "" {
+			discord.RespondWithError(s, i, validationErr)
+			return
+		}
+		runLinkCommand(s, i, func(ctx context.Context) (*discordgo.MessageEmbed, error) {
+			platformRegion := riot.NormalizePlatformRegion(region)
+			account, err := rt.Riot.FetchAccountByRiotID(ctx, platformRegion, nick, tag)
+			if err != nil {
+				return nil, err
+			}
+			if err := rt.Database.SetAccountProfile(ctx, postgres.AccountProfile{
+				UserID:         userID,
+				PlatformRegion: platformRegion,
+				PUUID:          account.PUUID,
+				GameName:       account.GameName,
+				TagLine:        account.TagLine,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to save profile: %w", err)
+			}
+			message := fmt.Sprintf("Your League profile is now **%s**.\nRight-click your name → Apps → %s to view it.", riot.FormatRiotID(account.GameName, account.TagLine), profileUserCommandName)
+			return linkEmbed(message, 0), nil
+		}, func(err error) string {
+			if msg, ok := discord.MapAccountNotFoundHint(i, err, nick, tag); ok {
+				return msg
+			}
+			return "Could not connect to Riot servers.\nPlease try again later."
+		})
+	case "clear":
+		runLinkCommand(s, i, func(ctx context.Context) (*discordgo.MessageEmbed, error) {
+			removed, err := rt.Database.RemoveAccountProfile(ctx, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to remove profile: %w", err)
+			}
+			if !removed {
+				return linkEmbed("You have no League profile set.", 0), nil
+			}
+			return linkEmbed("Your League profile was cleared.", 0), nil
+		}, func(error) string {
+			return "Could not clear your profile right now. Please try again."
+		})
+	default:
+		discord.RespondWithError(s, i, "Invalid command input.")
+	}
+}
+
+func handleProfileUserCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+	data := i.ApplicationCommandData()
+	targetID := strings.TrimSpace(data.TargetID)
+	if targetID == "" {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+	targetName := profileTargetName(data, targetID)
+
+	if err := discord.RunDeferredCommand(s, i, profileTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		account, found, err := rt.Database.ResolveUserAccount(ctx, i.GuildID, targetID)
+		if err != nil {
+			return discord.DeferredResponse{}, fmt.Errorf("failed to resolve profile account: %w", err)
+		}
+		if !found {
+			return discord.DeferredResponse{}, errProfileNotFound
+		}
+		profile, err := loadSearchDataByPUUID(ctx, rt, account.PlatformRegion, account.PUUID)
+		if err != nil {
+			return discord.DeferredResponse{}, err
+		}
+		return buildSearchResponse(ctx, rt, profile), nil
+	}, func(err error) string {
+		if errors.Is(err, errProfileNotFound) {
+			return fmt.Sprintf("**%s** has no League account registered.\nThey can use `/profile set` or `/link account`.", targetName)
+		}
+		return "Could not connect to Riot servers.\nPlease try again later."
+	}); err != nil {
+		slog.Error("Failed to handle deferred profile interaction", "error", err)
+	}
+}
+
+// profileTargetName returns the display name of the right-clicked member for error messages.
+func profileTargetName(data discordgo.ApplicationCommandInteractionData, targetID string) string {
+	if data.Resolved != nil {
+		if member, ok := data.Resolved.Members[targetID]; ok && member != nil && strings.TrimSpace(member.Nick) != "" {
+			return strings.TrimSpace(member.Nick)
+		}
+		if user, ok := data.Resolved.Users[targetID]; ok && user != nil {
+			if name := strings.TrimSpace(user.GlobalName); name != "" {
+				return name
+			}
+			if name := strings.TrimSpace(user.Username); name != "" {
+				return name
+			}
+		}
+	}
+	return "This member"
+}
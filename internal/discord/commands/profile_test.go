@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestProfileUserCommandDefinition(t *testing.T) {
+	data := ProfileUserCommand.Data
+	if data.Type != discordgo.UserApplicationCommand {
+		t.Fatalf("type = %v, want user command", data.Type)
+	}
+	if data.Description != "" || len(data.Options) != 0 {
+		t.Fatalf("user commands cannot have a description or options: %+v", data)
+	}
+}
+
+func TestProfileTargetName(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolved *discordgo.ApplicationCommandInteractionDataResolved
+		want     string
+	}{
+		{name: "no resolved data", want: "This member"},
+		{
+			name: "member nick wins",
+			resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+				Members: map[string]*discordgo.Member{"42": {Nick: " Mid Main "}},
+				Users:   map[string]*discordgo.User{"42": {Username: "ahri", GlobalName: "Ahri"}},
+			},
+			want: "Mid Main",
+		},
+		{
+			name:     "global name",
+			resolved: &discordgo.ApplicationCommandInteractionDataResolved{Users: map[string]*discordgo.User{"42": {Username: "ahri", GlobalName: "Ahri"}}},
+			want:     "Ahri",
+		},
+		{
+			name:     "username",
+			resolved: &discordgo.ApplicationCommandInteractionDataResolved{Users: map[string]*discordgo.User{"42": {Username: "ahri"}}},
+			want:     "ahri",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := discordgo.ApplicationCommandInteractionData{TargetID: "42", Resolved: tc.resolved}
+			if got := profileTargetName(data, "42"); got != tc.want {
+				t.Fatalf("profileTargetName() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/bingbr/League-API-bot/internal/discord"
 	"github.com/bingbr/League-API-bot/internal/riot"
 	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
 	"github.com/bwmarrin/discordgo"
 	"golang.org/x/sync/errgroup"
 )
@@ -19,6 +21,9 @@ const (
 	searchEmbedColor = 0x2b2b2b
 	rankedSoloQueue  = "RANKED_SOLO_5x5"
 	rankedFlexQueue  = "RANKED_FLEX_SR"
+
+	searchRefreshPrefix = "search"
+	searchRefreshTTL    = 24 * time.Hour
 )
 
 // -- Command Definition --
@@ -35,47 +40,128 @@ var SearchCommand = &discord.Command{
 			discordgo.InteractionContextBotDM,
 			discordgo.InteractionContextPrivateChannel,
 		},
-		Options: discord.AccountTargetOptions(),
+		Options: discord.OptionalAccountTargetOptions(),
 	},
 	Handler: handleSearch,
+	Components: map[string]discord.ComponentHandler{
+		searchRefreshPrefix: handleSearchRefresh,
+	},
 }
 
 func handleSearch(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	runtime := currentRuntime()
-	if !discord.RequireCommandConfigured(s, i, strings.TrimSpace(runtime.RiotAPIKey) != "") {
+	if !discord.RequireCommandConfigured(s, i, runtime.Riot != nil) {
 		return
 	}
 
+	// Without a Riot ID, /search shows the account the caller linked with /link.
 	options := i.ApplicationCommandData().Options
+	if discord.OptionValueByName(options, "region") == "" && discord.OptionValueByName(options, "nick") == "" {
+		runLinkedSearch(s, i, runtime)
+		return
+	}
 	region, nick, tag, validationErr := discord.ParseAccountTargetOptions(i, options)
 	if validationErr != "" {
 		discord.RespondWithError(s, i, validationErr)
 		return
 	}
 
-	if err := discord.RunDeferredEmbedCommand(s, i, searchTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
-		account, summoner, entries, err := loadSearchData(ctx, runtime.RiotAPIKey, region, nick, tag)
+	if err := discord.RunDeferredCommand(s, i, searchTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		data, err := loadSearchData(ctx, runtime, region, nick, tag)
+		if err != nil {
+			return discord.DeferredResponse{}, fmt.Errorf("failed to fetch account: %w", err)
+		}
+		return buildSearchResponse(ctx, runtime, data), nil
+	}, func(err error) string {
+		return mapSearchDeferredError(i, err, nick, tag)
+	}); err != nil {
+		slog.Error("Failed to handle deferred search interaction", "error", err)
+	}
+}
+
+// runLinkedSearch answers /search and /me for the account linked to the caller.
+func runLinkedSearch(s *discordgo.Session, i *discordgo.InteractionCreate, runtime Runtime) {
+	if runtime.Database == nil {
+		discord.RespondWithError(s, i, "Account linking is unavailable.\nUse `/search` with a region and nick.")
+		return
+	}
+	_, userID := discord.InteractionUserID(i)
+	if err := discord.RunDeferredCommand(s, i, searchTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		data, err := loadLinkedSearchData(ctx, runtime, userID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch account: %w", err)
+			return discord.DeferredResponse{}, err
 		}
+		return buildSearchResponse(ctx, runtime, data), nil
+	}, func(err error) string {
+		if errors.Is(err, errAccountNotLinked) {
+			return "You have not linked a Riot account yet.\nUse `/link account` or pass a region and nick."
+		}
+		return "Could not connect to Riot servers.\nPlease try again later."
+	}); err != nil {
+		slog.Error("Failed to handle deferred linked search interaction", "error", err)
+	}
+}
 
-		rankIcons := map[string]string{}
-		if runtime.Database != nil {
-			icons, err := runtime.Database.RankIconsByTiers(ctx, riot.RankTiersToLookup(entries))
-			if err != nil {
-				slog.Warn("Failed to load ranked tier icons", "error", err)
-			} else {
-				rankIcons = icons
-			}
+// handleSearchRefresh answers the Refresh button by reloading the account shown in the message.
+func handleSearchRefresh(s *discordgo.Session, i *discordgo.InteractionCreate, id discord.ComponentID) {
+	runtime := currentRuntime()
+	region, nick, tag := id.Arg(0), id.Arg(1), id.Arg(2)
+	if riot.NormalizePlatformRegion(region) == "" || nick == "" || tag == "" || runtime.Riot == nil {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+	if err := discord.RunDeferredComponentUpdate(s, i, searchTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		data, err := loadSearchData(ctx, runtime, region, nick, tag)
+		if err != nil {
+			return discord.DeferredResponse{}, fmt.Errorf("failed to refresh account: %w", err)
 		}
-		return []*discordgo.MessageEmbed{buildSearchEmbed(account, summoner, entries, rankIcons)}, nil
+		return buildSearchResponse(ctx, runtime, data), nil
 	}, func(err error) string {
 		return mapSearchDeferredError(i, err, nick, tag)
 	}); err != nil {
-		slog.Error("Failed to handle deferred search interaction", "error", err)
+		slog.Error("Failed to refresh search message", "error", err)
 	}
 }
 
+// buildSearchResponse is the /search reply: the profile embed and a Refresh button.
+func buildSearchResponse(ctx context.Context, runtime Runtime, data searchData) discord.DeferredResponse {
+	return discord.DeferredResponse{
+		Embeds:     []*discordgo.MessageEmbed{buildSearchEmbedWithDisplays(ctx, runtime, data)},
+		Components: searchRefreshComponents(data),
+	}
+}
+
+// searchRefreshComponents encodes the Riot ID in the button; accounts too long for a custom ID get no button.
+func searchRefreshComponents(data searchData) []discordgo.MessageComponent {
+	customID, err := discord.EncodeComponentID(searchRefreshPrefix, searchRefreshTTL, data.PlatformRegion, data.Account.GameName, data.Account.TagLine)
+	if err != nil || data.PlatformRegion == "" || data.Account.GameName == "" {
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Refresh", Style: discordgo.SecondaryButton, CustomID: customID},
+		}},
+	}
+}
+
+// buildSearchEmbedWithDisplays loads the optional rank icons and champion names before building the embed.
+func buildSearchEmbedWithDisplays(ctx context.Context, runtime Runtime, data searchData) *discordgo.MessageEmbed {
+	rankIcons := map[string]string{}
+	champions := map[int]postgres.ChampionDisplay{}
+	if runtime.Database != nil {
+		icons, err := runtime.Database.RankIconsByTiers(ctx, riot.RankTiersToLookup(data.Entries))
+		if err != nil {
+			slog.Warn("Failed to load ranked tier icons", "error", err)
+		} else {
+			rankIcons = icons
+		}
+		champions = loadOrEmptyDisplays(func() (map[int]postgres.ChampionDisplay, error) {
+			return runtime.Database.ChampionDisplayByIDs(ctx, masteryChampionIDs(data.Masteries))
+		})
+	}
+	return buildSearchEmbed(data, rankIcons, champions)
+}
+
 func mapSearchDeferredError(i *discordgo.InteractionCreate, err error, nick, tag string) string {
 	if msg, ok := discord.MapAccountNotFoundHint(i, err, nick, tag); ok {
 		return msg
@@ -83,41 +169,92 @@ func mapSearchDeferredError(i *discordgo.InteractionCreate, err error, nick, tag
 	return "Could not connect to Riot servers.\nPlease try again later."
 }
 
-func loadSearchData(ctx context.Context, riotAPIKey, region, nick, tag string) (riot.RiotAccount, riot.SummonerProfile, []riot.LeagueEntry, error) {
+type searchData struct {
+	PlatformRegion string
+	Account        riot.RiotAccount
+	Summoner       riot.SummonerProfile
+	Entries        []riot.LeagueEntry
+	Masteries      []riot.ChampionMastery
+}
+
+func loadSearchData(ctx context.Context, rt Runtime, region, nick, tag string) (searchData, error) {
 	platformRegion := riot.NormalizePlatformRegion(region)
 	if platformRegion == "" {
-		return riot.RiotAccount{}, riot.SummonerProfile{}, nil, fmt.Errorf("region is invalid")
+		return searchData{}, fmt.Errorf("region is invalid")
 	}
 
-	account, err := riot.FetchAccountByRiotID(ctx, platformRegion, nick, tag, riotAPIKey)
+	account, err := rt.Riot.FetchAccountByRiotID(ctx, platformRegion, nick, tag)
 	if err != nil {
-		return riot.RiotAccount{}, riot.SummonerProfile{}, nil, err
+		return searchData{}, err
 	}
+	return loadSearchProfile(ctx, rt, platformRegion, account)
+}
 
-	var (
-		summoner riot.SummonerProfile
-		entries  []riot.LeagueEntry
-	)
+// loadLinkedSearchData resolves the linked account by PUUID, so renamed accounts still work,
+// and refreshes the stored Riot ID when it changed.
+func loadLinkedSearchData(ctx context.Context, rt Runtime, userID string) (searchData, error) {
+	link, found, err := rt.Database.AccountLinkByUser(ctx, userID)
+	if err != nil {
+		return searchData{}, fmt.Errorf("failed to load account link: %w", err)
+	}
+	if !found {
+		return searchData{}, errAccountNotLinked
+	}
+
+	data, err := loadSearchDataByPUUID(ctx, rt, link.PlatformRegion, link.PUUID)
+	if err != nil {
+		return searchData{}, err
+	}
+	if account := data.Account; account.GameName != "" && riot.FormatRiotID(account.GameName, account.TagLine) != link.RiotID() {
+		if err := rt.Database.UpdateAccountLinkRiotID(ctx, userID, account.GameName, account.TagLine); err != nil {
+			slog.Warn("Failed to refresh linked Riot ID", "userID", userID, "error", err)
+		}
+	}
+	return data, nil
+}
+
+// loadSearchDataByPUUID loads a stored account, picking up its current Riot ID.
+func loadSearchDataByPUUID(ctx context.Context, rt Runtime, platformRegion, puuid string) (searchData, error) {
+	account, err := rt.Riot.FetchAccountByPUUID(ctx, platformRegion, puuid)
+	if err != nil {
+		return searchData{}, fmt.Errorf("failed to fetch account by puuid: %w", err)
+	}
+	return loadSearchProfile(ctx, rt, riot.NormalizePlatformRegion(platformRegion), account)
+}
+
+func loadSearchProfile(ctx context.Context, rt Runtime, platformRegion string, account riot.RiotAccount) (searchData, error) {
+	data := searchData{PlatformRegion: platformRegion, Account: account}
 	g, gctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		var err error
-		summoner, err = riot.FetchSummonerByPUUID(gctx, platformRegion, account.PUUID, riotAPIKey)
+		data.Summoner, err = rt.Riot.FetchSummonerByPUUID(gctx, platformRegion, account.PUUID)
 		return err
 	})
 	g.Go(func() error {
 		var err error
-		entries, err = riot.FetchLeagueEntriesByPUUID(gctx, platformRegion, account.PUUID, riotAPIKey)
+		data.Entries, err = rt.Riot.FetchLeagueEntriesByPUUID(gctx, platformRegion, account.PUUID)
 		return err
 	})
+	g.Go(func() error {
+		// Masteries are a bonus field; a failure here should not hide the rest of the profile.
+		summary, err := loadChampionMasteries(gctx, rt, platformRegion, account.PUUID)
+		if err != nil {
+			slog.Warn("Failed to load champion masteries for /search", "error", err)
+			return nil
+		}
+		data.Masteries = summary.Masteries[:min(searchMasteryCount, len(summary.Masteries))]
+		return nil
+	})
 	if err := g.Wait(); err != nil {
-		return riot.RiotAccount{}, riot.SummonerProfile{}, nil, err
+		return searchData{}, err
 	}
-	return account, summoner, entries, nil
+	return data, nil
 }
 
-func buildSearchEmbed(account riot.RiotAccount, summoner riot.SummonerProfile, entries []riot.LeagueEntry, rankIcons map[string]string) *discordgo.MessageEmbed {
-	solo := riot.QueueEntry(entries, rankedSoloQueue)
-	flex := riot.QueueEntry(entries, rankedFlexQueue)
+func buildSearchEmbed(data searchData, rankIcons map[string]string, champions map[int]postgres.ChampionDisplay) *discordgo.MessageEmbed {
+	account, summoner := data.Account, data.Summoner
+	solo := riot.QueueEntry(data.Entries, rankedSoloQueue)
+	flex := riot.QueueEntry(data.Entries, rankedFlexQueue)
 	lastSeen := "Unknown"
 	if summoner.RevisionDate > 0 {
 		lastSeen = fmt.Sprintf("<t:%d:R>", summoner.RevisionDate/1000)
@@ -139,6 +276,12 @@ func buildSearchEmbed(account riot.RiotAccount, summoner riot.SummonerProfile, e
 			{Name: "Flex", Value: rankedFieldValue(flex, rankIcons), Inline: true},
 		},
 	}
+	if len(data.Masteries) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Top Mastery",
+			Value: strings.Join(masteryLines(data.Masteries, champions, false), "\n"),
+		})
+	}
 	discord.ApplyDefaultFooter(embed)
 	return embed
 }
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/discord/embedtest"
 	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -69,17 +71,20 @@ func TestQueueEntry(t *testing.T) {
 
 func TestBuildSearchEmbed(t *testing.T) {
 	embed := buildSearchEmbed(
-		riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"},
-		riot.SummonerProfile{ProfileIconID: 7070, RevisionDate: 1770422047398, SummonerLevel: 1027},
-		[]riot.LeagueEntry{
-			{QueueType: rankedSoloQueue, Tier: "MASTER", Rank: "I", LeaguePoints: 580, Wins: 56, Losses: 43},
-			{QueueType: rankedFlexQueue, Tier: "CHALLENGER", Rank: "I", LeaguePoints: 1389, Wins: 67, Losses: 22},
+		searchData{
+			Account:  riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"},
+			Summoner: riot.SummonerProfile{ProfileIconID: 7070, RevisionDate: 1770422047398, SummonerLevel: 1027},
+			Entries: []riot.LeagueEntry{
+				{QueueType: rankedSoloQueue, Tier: "MASTER", Rank: "I", LeaguePoints: 580, Wins: 56, Losses: 43},
+				{QueueType: rankedFlexQueue, Tier: "CHALLENGER", Rank: "I", LeaguePoints: 1389, Wins: 67, Losses: 22},
+			},
 		},
 		map[string]string{
 			"master":     "<:Master:1>",
 			"challenger": "<:Challenger:1>",
 			"unranked":   "<:Unranked:1>",
 		},
+		nil,
 	)
 
 	if embed.Title != "Bekko#Ekko" {
@@ -103,12 +108,34 @@ func TestBuildSearchEmbed(t *testing.T) {
 	if embed.Timestamp == "" {
 		t.Fatalf("embed.Timestamp should not be empty")
 	}
+	embedtest.Golden(t, "search_ranked", embed)
+}
+
+func TestBuildSearchEmbed_TopMastery(t *testing.T) {
+	embed := buildSearchEmbed(
+		searchData{
+			Account:   riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"},
+			Masteries: []riot.ChampionMastery{{ChampionID: 245, ChampionLevel: 52, ChampionPoints: 612345, LastPlayTime: 1770422047398}},
+		},
+		nil,
+		map[int]postgres.ChampionDisplay{245: {Name: "Ekko"}},
+	)
+
+	if len(embed.Fields) != 3 || embed.Fields[2].Name != "Top Mastery" {
+		t.Fatalf("embed.Fields = %#v", embed.Fields)
+	}
+	if embed.Fields[2].Value != "Ekko · Lv. 52 · 612,345 pts" {
+		t.Fatalf("embed.Fields[2].Value = %q", embed.Fields[2].Value)
+	}
+	embedtest.Golden(t, "search_top_mastery", embed)
 }
 
 func TestBuildSearchEmbed_UsesEnglishText(t *testing.T) {
 	embed := buildSearchEmbed(
-		riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"},
-		riot.SummonerProfile{ProfileIconID: 7070, RevisionDate: 0, SummonerLevel: 1},
+		searchData{
+			Account:  riot.RiotAccount{GameName: "Bekko", TagLine: "Ekko"},
+			Summoner: riot.SummonerProfile{ProfileIconID: 7070, RevisionDate: 0, SummonerLevel: 1},
+		},
 		nil,
 		nil,
 	)
@@ -197,3 +224,24 @@ func TestMapSearchDeferredError_Fallback(t *testing.T) {
 		t.Fatalf("unexpected fallback message: %q", got)
 	}
 }
+
+func TestSearchRefreshComponents(t *testing.T) {
+	data := searchData{PlatformRegion: "br1", Account: riot.RiotAccount{GameName: "Ahri:Main", TagLine: "BR1"}}
+	components := searchRefreshComponents(data)
+	if len(components) != 1 {
+		t.Fatalf("components = %#v, want one row", components)
+	}
+	button := components[0].(discordgo.ActionsRow).Components[0].(discordgo.Button)
+	id, err := discord.ParseComponentID(button.CustomID)
+	if err != nil || id.Prefix != searchRefreshPrefix || id.Arg(0) != "br1" || id.Arg(1) != "Ahri:Main" || id.Arg(2) != "BR1" {
+		t.Fatalf("refresh custom id = %+v, %v", id, err)
+	}
+
+	if got := searchRefreshComponents(searchData{PlatformRegion: "br1"}); len(got) != 0 {
+		t.Fatalf("components without account = %#v, want none", got)
+	}
+	long := searchData{PlatformRegion: "br1", Account: riot.RiotAccount{GameName: strings.Repeat("%", 40), TagLine: "BR1"}}
+	if got := searchRefreshComponents(long); len(got) != 0 {
+		t.Fatalf("components for an overlong custom id = %#v, want none", got)
+	}
+}
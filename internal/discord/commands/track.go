@@ -74,11 +74,49 @@ var TrackCommand = &discord.Command{
 							discordgo.ChannelTypeGuildText,
 						},
 					},
+					{
+						Name:        "post_mode",
+						Description: "How finished games are posted.",
+						Type:        discordgo.ApplicationCommandOptionString,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Reply to the live message", Value: postgres.TrackPostModeReply},
+							{Name: "Edit the live message", Value: postgres.TrackPostModeEdit},
+						},
+					},
+					{
+						Name:        "rename_notices",
+						Description: "Post when a tracked player changes their Riot ID.",
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+					},
+					{
+						Name:        "event",
+						Description: "Only send this kind of update to the channel.",
+						Type:        discordgo.ApplicationCommandOptionString,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Live and post-game", Value: postgres.TrackRouteEventGame},
+							{Name: "Rank changes", Value: postgres.TrackRouteEventRank},
+							{Name: "Name changes", Value: postgres.TrackRouteEventRename},
+						},
+					},
+					{
+						Name:         "queue",
+						Description:  "Only send updates of this queue or queue category to the channel.",
+						Type:         discordgo.ApplicationCommandOptionString,
+						Autocomplete: true,
+					},
 				},
 			},
+			trackBulkSubcommand,
+			trackListSubcommand,
+			trackImportSubcommand,
+			trackFilterCommandGroup,
 		},
 	},
 	Handler: track,
+	Components: map[string]discord.ComponentHandler{
+		trackBulkPrefix: handleTrackBulkSubmit,
+		trackListPrefix: handleTrackListPage,
+	},
 }
 
 func track(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -105,14 +143,22 @@ func track(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		handleTrackConfig(s, i, rt.Database, guildID, options)
 	case "add":
 		handleTrackAdd(s, i, rt, guildID, options)
+	case "bulk":
+		handleTrackBulk(s, i, rt, guildID, options)
+	case "list":
+		handleTrackList(s, i, rt.Database, guildID, options)
+	case "import":
+		handleTrackImport(s, i, rt, guildID, options)
 	case "remove":
 		handleTrackRemove(s, i, rt.Database, guildID, options)
+	case "filter":
+		handleTrackFilter(s, i, rt.Database, guildID, options)
 	default:
 		discord.RespondWithError(s, i, "Invalid command input.")
 	}
 }
 
-func handleTrackConfig(s *discordgo.Session, i *discordgo.InteractionCreate, db storage.TrackDB, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+func handleTrackConfig(s *discordgo.Session, i *discordgo.InteractionCreate, db storage.CommandDB, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
 	if !hasManageServerPermission(i) {
 		discord.RespondWithError(s, i, "You need the `Manage Server` permission to use `/track config`.")
 		return
@@ -128,21 +174,54 @@ func handleTrackConfig(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		return
 	}
 
+	postMode := discord.OptionValueByName(options, "post_mode")
+	announceRenames, setAnnounceRenames := discord.OptionBoolByName(options, "rename_notices")
+	event, queue := discord.OptionValueByName(options, "event"), discord.OptionValueByName(options, "queue")
+	if event != "" || queue != "" {
+		var renames *bool
+		if setAnnounceRenames {
+			renames = &announceRenames
+		}
+		handleTrackRoute(s, i, db, guildID, channelID, event, queue, postMode, renames)
+		return
+	}
 	if err := withTrackDBTimeout(func(ctx context.Context) error {
-		return db.UpsertTrackGuildConfig(ctx, guildID, channelID)
+		if err := db.UpsertTrackGuildConfig(ctx, guildID, channelID); err != nil {
+			return err
+		}
+		if setAnnounceRenames {
+			if err := db.SetTrackGuildAnnounceRenames(ctx, guildID, announceRenames); err != nil {
+				return err
+			}
+		}
+		if postMode == "" {
+			return nil
+		}
+		return db.SetTrackGuildPostMode(ctx, guildID, postMode)
 	}); err != nil {
 		slog.Error("Failed to save /track config", "guildID", guildID, "channelID", channelID, "error", err)
 		discord.RespondWithError(s, i, "Could not save tracking configuration. Please try again.")
 		return
 	}
 
-	if err := discord.RespondWithEmbed(s, i, trackInfoEmbed(fmt.Sprintf("Account tracking updates will be sent to <#%s>.", channelID))); err != nil {
+	message := trackConfigMessage(channelID, postMode)
+	if setAnnounceRenames {
+		message += "\n" + trackRenameNoticesMessage(announceRenames)
+	}
+	if err := discord.RespondWithEmbed(s, i, trackInfoEmbed(message)); err != nil {
 		slog.Error("Failed to respond /track config", "error", err)
 	}
 }
 
+func trackRenameNoticesMessage(announce bool) string {
+	if announce {
+		return "Riot ID changes of tracked players will be announced."
+	}
+	return "Riot ID changes of tracked players will not be announced."
+}
+
 func handleTrackAdd(s *discordgo.Session, i *discordgo.InteractionCreate, rt Runtime, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
-	if rt.RiotAPIKey == "" {
+	if rt.Riot == nil {
 		discord.RespondWithError(s, i, "The command is not configured.")
 		return
 	}
@@ -158,7 +237,7 @@ func handleTrackAdd(s *discordgo.Session, i *discordgo.InteractionCreate, rt Run
 
 	_, userID := discord.InteractionUserID(i)
 	if err := discord.RunDeferredEmbedCommand(s, i, trackAddTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
-		account, err := riot.FetchAccountByRiotID(ctx, region, nickname, tagline, rt.RiotAPIKey)
+		account, err := rt.Riot.FetchAccountByRiotID(ctx, region, nickname, tagline)
 		if err != nil {
 			return nil, fmt.Errorf("fetch track account by riot id: %w", err)
 		}
@@ -222,7 +301,7 @@ func handleTrackRemove(s *discordgo.Session, i *discordgo.InteractionCreate, db
 
 func handleTrackAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	subcommand, options, ok := trackSubcommand(i)
-	if !ok || subcommand != "remove" {
+	if !ok || (subcommand != "remove" && subcommand != "filter" && subcommand != "config") {
 		respondTrackAutocompleteChoices(s, i, nil)
 		return
 	}
@@ -233,6 +312,14 @@ func handleTrackAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreat
 		respondTrackAutocompleteChoices(s, i, nil)
 		return
 	}
+	switch subcommand {
+	case "filter":
+		handleTrackFilterAutocomplete(s, i, guildID, rt.Database, options)
+		return
+	case "config":
+		respondTrackQueueAutocomplete(s, i, rt.Database, discord.FocusedOptionValue(options))
+		return
+	}
 
 	_, configured, err := loadTrackGuildConfig(rt.Database, guildID)
 	if err != nil || !configured {
@@ -243,17 +330,19 @@ func handleTrackAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreat
 		return
 	}
 
+	respondTrackedAccountAutocomplete(s, i, rt.Database, guildID, discord.FocusedOptionValue(options))
+}
+
+func respondTrackedAccountAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, db storage.TrackDB, guildID, query string) {
 	trackedAccounts, err := withTrackDBTimeoutValue(func(ctx context.Context) ([]postgres.TrackedAccount, error) {
-		return rt.Database.ListTrackedAccounts(ctx, guildID, trackAutocompleteLimit)
+		return db.ListTrackedAccounts(ctx, guildID, trackAutocompleteLimit)
 	})
 	if err != nil {
 		slog.Warn("Failed to list tracked accounts for autocomplete", "guildID", guildID, "error", err)
 		respondTrackAutocompleteChoices(s, i, nil)
 		return
 	}
-
-	choices := buildTrackAutocompleteChoices(trackedAccounts, discord.FocusedOptionValue(options))
-	respondTrackAutocompleteChoices(s, i, choices)
+	respondTrackAutocompleteChoices(s, i, buildTrackAutocompleteChoices(trackedAccounts, query))
 }
 
 func buildTrackAutocompleteChoices(tracked []postgres.TrackedAccount, query string) []*discordgo.ApplicationCommandOptionChoice {
@@ -374,6 +463,18 @@ func trackInfoEmbed(message string) *discordgo.MessageEmbed {
 	return embed
 }
 
+func trackConfigMessage(channelID, postMode string) string {
+	message := fmt.Sprintf("Account tracking updates will be sent to <#%s>.", channelID)
+	switch {
+	case postMode == "":
+		return message
+	case postgres.NormalizeTrackPostMode(postMode) == postgres.TrackPostModeEdit:
+		return message + "\nPost-game results will replace the live game message."
+	default:
+		return message + "\nPost-game results will be posted as a reply to the live game message."
+	}
+}
+
 func mapTrackAddDeferredError(i *discordgo.InteractionCreate, err error, nickname, tagline string) string {
 	if msg, ok := discord.MapAccountNotFoundHint(i, err, nickname, tagline); ok {
 		return msg
@@ -0,0 +1,283 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	trackBulkPrefix       = "trackbulk"
+	trackBulkInputID      = "accounts"
+	trackBulkModalTTL     = 15 * time.Minute
+	trackBulkTimeout      = 45 * time.Second
+	trackBulkMaxAccounts  = 25
+	trackBulkConcurrency  = 4
+	trackBulkInputMaxLen  = 2000
+	trackBulkReportMaxLen = 3900 // Leaves room below the 4096 embed description limit for the summary.
+)
+
+var trackBulkSubcommand = &discordgo.ApplicationCommandOption{
+	Name:        "bulk",
+	Description: "Add many accounts to track at once.",
+	Type:        discordgo.ApplicationCommandOptionSubCommand,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "region",
+			Description: "Region of the lines that do not set one.",
+			Choices:     discord.RegionOption.Choices,
+		},
+	},
+}
+
+// trackBulkLine is one pasted line. Err is set when the line cannot be looked up at all.
+type trackBulkLine struct {
+	Number   int
+	Raw      string
+	Region   string
+	GameName string
+	TagLine  string
+	Err      string
+}
+
+func (l trackBulkLine) RiotID() string {
+	return riot.FormatRiotID(l.GameName, l.TagLine)
+}
+
+type trackBulkResult struct {
+	Line    trackBulkLine
+	Label   string
+	Created bool
+	Err     string
+}
+
+// handleTrackBulk opens the modal; the default region travels in its custom ID to the submit handler.
+func handleTrackBulk(s *discordgo.Session, i *discordgo.InteractionCreate, rt Runtime, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if rt.Riot == nil {
+		discord.RespondWithError(s, i, "The command is not configured.")
+		return
+	}
+	if !hasManageServerPermission(i) {
+		discord.RespondWithError(s, i, "You need the `Manage Server` permission to use `/track bulk`.")
+		return
+	}
+	if !requireTrackConfig(s, i, rt.Database, guildID) {
+		return
+	}
+
+	customID, err := discord.EncodeComponentID(trackBulkPrefix, trackBulkModalTTL, discord.OptionValueByName(options, "region"))
+	if err != nil {
+		slog.Error("Failed to encode /track bulk modal id", "error", err)
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+	if err := discord.RespondWithModal(s, i, customID, "Track accounts", discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID:    trackBulkInputID,
+				Label:       fmt.Sprintf("Accounts, one per line (up to %d)", trackBulkMaxAccounts),
+				Style:       discordgo.TextInputParagraph,
+				Placeholder: "nickname#tagline region\nnickname#tagline",
+				Required:    true,
+				MaxLength:   trackBulkInputMaxLen,
+			},
+		},
+	}); err != nil {
+		slog.Error("Failed to open /track bulk modal", "error", err)
+	}
+}
+
+func handleTrackBulkSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, id discord.ComponentID) {
+	guildID, ok := discord.RequireGuildCommand(s, i)
+	if !ok {
+		return
+	}
+	rt := currentRuntime()
+	if !discord.RequireCommandConfigured(s, i, rt.Database != nil && rt.Riot != nil) {
+		return
+	}
+	if !hasManageServerPermission(i) {
+		discord.RespondWithError(s, i, "You need the `Manage Server` permission to use `/track bulk`.")
+		return
+	}
+	if !requireTrackConfig(s, i, rt.Database, guildID) {
+		return
+	}
+
+	lines := parseTrackBulkLines(discord.ModalTextValue(i, trackBulkInputID), id.Arg(0))
+	if len(lines) == 0 {
+		discord.RespondWithError(s, i, "No accounts were entered.")
+		return
+	}
+	if len(lines) > trackBulkMaxAccounts {
+		discord.RespondWithError(s, i, fmt.Sprintf("You can add up to %d accounts at once.", trackBulkMaxAccounts))
+		return
+	}
+
+	_, userID := discord.InteractionUserID(i)
+	if err := discord.RunDeferredEmbedCommand(s, i, trackBulkTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
+		results := addTrackBulkAccounts(ctx, rt, guildID, userID, lines)
+		return []*discordgo.MessageEmbed{trackBulkReportEmbed(results)}, nil
+	}, nil); err != nil {
+		slog.Error("Failed to run deferred /track bulk", "error", err)
+	}
+}
+
+// parseTrackBulkLines reads "nickname#tagline [region]" lines. Blank lines are skipped and lines without a
+// region use defaultRegion. A Riot ID seen twice only keeps its first line.
+func parseTrackBulkLines(text, defaultRegion string) []trackBulkLine {
+	defaultRegion = riot.NormalizePlatformRegion(defaultRegion)
+	lines := make([]trackBulkLine, 0)
+	seen := make(map[string]bool)
+	for n, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		line := trackBulkLine{Number: n + 1, Raw: raw, Region: defaultRegion}
+		lines = append(lines, parseTrackBulkLine(line, seen))
+	}
+	return lines
+}
+
+func parseTrackBulkLine(line trackBulkLine, seen map[string]bool) trackBulkLine {
+	idx := strings.LastIndex(line.Raw, "#")
+	if idx < 0 {
+		line.Err = "Use format nickname#tagline."
+		return line
+	}
+	// Tag lines have no spaces, so anything after the tag is the region.
+	rest := strings.Fields(line.Raw[idx+1:])
+	if len(rest) > 2 {
+		line.Err = "Use format nickname#tagline region."
+		return line
+	}
+	if len(rest) == 2 {
+		line.Region = riot.NormalizePlatformRegion(rest[1])
+		if line.Region == "" {
+			line.Err = fmt.Sprintf("Unknown region `%s`.", rest[1])
+			return line
+		}
+	}
+	riotID := line.Raw[:idx+1]
+	if len(rest) > 0 {
+		riotID += rest[0]
+	}
+	gameName, tagLine, err := riot.SplitRiotID(riotID)
+	if err != nil {
+		line.Err = "Use format nickname#tagline."
+		return line
+	}
+	line.GameName, line.TagLine = gameName, tagLine
+	if line.Region == "" {
+		line.Err = "The region is required."
+		return line
+	}
+
+	key := line.Region + "/" + strings.ToLower(line.RiotID())
+	if seen[key] {
+		line.Err = "Duplicate of an earlier line."
+		return line
+	}
+	seen[key] = true
+	return line
+}
+
+// addTrackBulkAccounts looks up and stores every valid line. Results keep the order of lines.
+func addTrackBulkAccounts(ctx context.Context, rt Runtime, guildID, userID string, lines []trackBulkLine) []trackBulkResult {
+	results := make([]trackBulkResult, len(lines))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(trackBulkConcurrency)
+	for idx, line := range lines {
+		results[idx] = trackBulkResult{Line: line, Label: line.Raw, Err: line.Err}
+		if line.Err != "" {
+			continue
+		}
+		results[idx].Label = line.RiotID()
+		g.Go(func() error {
+			results[idx] = addTrackBulkAccount(ctx, rt, guildID, userID, line)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+func addTrackBulkAccount(ctx context.Context, rt Runtime, guildID, userID string, line trackBulkLine) trackBulkResult {
+	result := trackBulkResult{Line: line, Label: line.RiotID()}
+	account, err := rt.Riot.FetchAccountByRiotID(ctx, line.Region, line.GameName, line.TagLine)
+	if err != nil {
+		slog.Warn("Failed to fetch /track bulk account", "guildID", guildID, "account", result.Label, "error", err)
+		result.Err = mapTrackBulkError(err)
+		return result
+	}
+	result.Label = riot.FormatRiotID(account.GameName, account.TagLine)
+
+	created, err := rt.Database.AddTrackedAccount(ctx, postgres.TrackedAccount{
+		GuildID:        guildID,
+		PlatformRegion: line.Region,
+		PUUID:          account.PUUID,
+		NickName:       account.GameName,
+		TagLine:        account.TagLine,
+		AddedBy:        userID,
+	})
+	if err != nil {
+		slog.Error("Failed to add /track bulk account", "guildID", guildID, "account", result.Label, "error", err)
+		result.Err = "Could not be saved. Please try again."
+		return result
+	}
+	result.Created = created
+	return result
+}
+
+func mapTrackBulkError(err error) string {
+	if riot.IsAccountByRiotIDNotFound(err) {
+		return "Account not found."
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "Timed out. Please try again."
+	}
+	return "Could not connect to Riot servers."
+}
+
+func trackBulkReportEmbed(results []trackBulkResult) *discordgo.MessageEmbed {
+	var added, existing, hidden int
+	var b strings.Builder
+	for _, result := range results {
+		region := strings.ToUpper(result.Line.Region)
+		var line string
+		switch {
+		case result.Err != "":
+			line = fmt.Sprintf("\n❌ Line %d `%s`: %s", result.Line.Number, result.Label, result.Err)
+		case result.Created:
+			added++
+			line = fmt.Sprintf("\n✅ `%s` (%s) is now tracked.", result.Label, region)
+		default:
+			existing++
+			line = fmt.Sprintf("\n➖ `%s` (%s) was already tracked.", result.Label, region)
+		}
+		if hidden > 0 || b.Len()+len(line) > trackBulkReportMaxLen {
+			hidden++
+			continue
+		}
+		b.WriteString(line)
+	}
+	if hidden > 0 {
+		fmt.Fprintf(&b, "\n…and %d more.", hidden)
+	}
+	summary := fmt.Sprintf("Added **%d** of **%d** accounts to tracking on this server.", added, len(results))
+	if existing > 0 {
+		summary += fmt.Sprintf(" %d already tracked.", existing)
+	}
+	return trackInfoEmbed(summary + "\n" + b.String())
+}
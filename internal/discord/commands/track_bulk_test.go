@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTrackBulkLines(t *testing.T) {
+	text := "Faker#KR1 kr\n\n  Ahri Main#BR1  \nLux#BR1 xx1\nnotag\nfaker#kr1 KR\nA#B c d\nZed#EUW euw1"
+	lines := parseTrackBulkLines(text, "br1")
+
+	want := []struct {
+		number int
+		region string
+		riotID string
+		err    string
+	}{
+		{1, "kr", "Faker#KR1", ""},
+		{3, "br1", "Ahri Main#BR1", ""},
+		{4, "", "", "Unknown region"},
+		{5, "br1", "", "nickname#tagline"},
+		{6, "kr", "faker#kr1", "Duplicate"},
+		{7, "br1", "", "nickname#tagline region"},
+		{8, "euw1", "Zed#EUW", ""},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("len(lines) = %d, want %d: %+v", len(lines), len(want), lines)
+	}
+	for idx, w := range want {
+		line := lines[idx]
+		if line.Number != w.number || line.Region != w.region {
+			t.Fatalf("line %d = %+v, want number %d region %q", idx, line, w.number, w.region)
+		}
+		if w.err != "" {
+			if !strings.Contains(line.Err, w.err) {
+				t.Fatalf("line %d error = %q, want it to contain %q", idx, line.Err, w.err)
+			}
+			continue
+		}
+		if line.Err != "" || line.RiotID() != w.riotID {
+			t.Fatalf("line %d = %+v, want %q without error", idx, line, w.riotID)
+		}
+	}
+}
+
+func TestParseTrackBulkLines_RequiresRegion(t *testing.T) {
+	lines := parseTrackBulkLines("Faker#KR1\nCaps#EUW euw1", "")
+	if len(lines) != 2 || !strings.Contains(lines[0].Err, "region is required") || lines[1].Err != "" {
+		t.Fatalf("lines = %+v, want only the first line to miss a region", lines)
+	}
+}
+
+func TestTrackBulkReportEmbed(t *testing.T) {
+	results := []trackBulkResult{
+		{Line: trackBulkLine{Number: 1, Region: "kr"}, Label: "Faker#KR1", Created: true},
+		{Line: trackBulkLine{Number: 2, Region: "br1"}, Label: "Ahri#BR1"},
+		{Line: trackBulkLine{Number: 3, Region: "br1"}, Label: "Lux#BR1", Err: "Account not found."},
+	}
+	got := trackBulkReportEmbed(results).Description
+	for _, want := range []string{
+		"Added **1** of **3** accounts to tracking on this server. 1 already tracked.",
+		"✅ `Faker#KR1` (KR) is now tracked.",
+		"➖ `Ahri#BR1` (BR1) was already tracked.",
+		"❌ Line 3 `Lux#BR1`: Account not found.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("report = %q, want it to contain %q", got, want)
+		}
+	}
+}
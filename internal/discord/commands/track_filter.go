@@ -0,0 +1,363 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+const trackFilterListLimit = 100
+
+var trackFilterAccountOption = &discordgo.ApplicationCommandOption{
+	Type:         discordgo.ApplicationCommandOptionString,
+	Name:         "account",
+	Description:  "Only apply to this tracked account (nickname#tagline).",
+	MinLength:    new(7),
+	MaxLength:    22,
+	Autocomplete: true,
+}
+
+var trackFilterQueueOption = &discordgo.ApplicationCommandOption{
+	Type:         discordgo.ApplicationCommandOptionString,
+	Name:         "queue",
+	Description:  "Queue or queue category.",
+	Autocomplete: true,
+	Required:     true,
+}
+
+var trackFilterCommandGroup = &discordgo.ApplicationCommandOption{
+	Name:        "filter",
+	Description: "Choose which queues are posted.",
+	Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "add",
+			Description: "Include or exclude a queue from tracking posts.",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "mode",
+					Description: "Include only these queues, or exclude this one.",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Include", Value: postgres.TrackQueueFilterInclude},
+						{Name: "Exclude", Value: postgres.TrackQueueFilterExclude},
+					},
+				},
+				trackFilterQueueOption,
+				trackFilterAccountOption,
+			},
+		},
+		{
+			Name:        "remove",
+			Description: "Remove a queue filter.",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options:     []*discordgo.ApplicationCommandOption{trackFilterQueueOption, trackFilterAccountOption},
+		},
+		{
+			Name:        "clear",
+			Description: "Remove every server filter, or every filter of one account.",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options:     []*discordgo.ApplicationCommandOption{trackFilterAccountOption},
+		},
+		{
+			Name:        "list",
+			Description: "Show the queue filters of this server.",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+	},
+}
+
+func handleTrackFilter(s *discordgo.Session, i *discordgo.InteractionCreate, db storage.CommandDB, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(options) == 0 || options[0] == nil {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+	subcommand, subOptions := options[0].Name, options[0].Options
+	if subcommand != "list" && !hasManageServerPermission(i) {
+		discord.RespondWithError(s, i, "You need the `Manage Server` permission to change queue filters.")
+		return
+	}
+	if !requireTrackConfig(s, i, db, guildID) {
+		return
+	}
+
+	var (
+		message string
+		err     error
+	)
+	switch subcommand {
+	case "add", "remove", "clear":
+		message, err = changeTrackFilter(db, guildID, subcommand, subOptions)
+	case "list":
+		message, err = listTrackFilters(db, guildID)
+	default:
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+	if err != nil {
+		if userErr, ok := errors.AsType[trackFilterInputError](err); ok {
+			discord.RespondWithError(s, i, string(userErr))
+			return
+		}
+		slog.Error("Failed to handle /track filter", "guildID", guildID, "subcommand", subcommand, "error", err)
+		discord.RespondWithError(s, i, "Could not update queue filters right now. Please try again.")
+		return
+	}
+	if err := discord.RespondWithEmbed(s, i, trackInfoEmbed(message)); err != nil {
+		slog.Error("Failed to respond /track filter", "error", err)
+	}
+}
+
+// trackFilterInputError carries a message meant for the user instead of a generic failure.
+type trackFilterInputError string
+
+func (e trackFilterInputError) Error() string { return string(e) }
+
+func changeTrackFilter(db storage.TrackFilterDB, guildID, subcommand string, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	puuid, scope, err := resolveTrackFilterScope(db, guildID, discord.OptionValueByName(options, "account"))
+	if err != nil {
+		return "", err
+	}
+
+	if subcommand == "clear" {
+		removed, err := withTrackDBTimeoutValue(func(ctx context.Context) (int64, error) {
+			return db.ClearTrackQueueFilters(ctx, guildID, puuid)
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Removed %d queue filter(s) for %s.", removed, scope), nil
+	}
+
+	kind, value, ok := parseTrackFilterTarget(discord.OptionValueByName(options, "queue"))
+	if !ok {
+		return "", trackFilterInputError("Select a queue or category from the list.")
+	}
+	label := trackFilterTargetLabel(db, kind, value)
+
+	if subcommand == "remove" {
+		removed, err := withTrackDBTimeoutValue(func(ctx context.Context) (bool, error) {
+			return db.RemoveTrackQueueFilter(ctx, guildID, puuid, kind, value)
+		})
+		if err != nil {
+			return "", err
+		}
+		if !removed {
+			return fmt.Sprintf("%s has no filter for %s.", capitalizeFirst(scope), label), nil
+		}
+		return fmt.Sprintf("Removed the filter for %s from %s.", label, scope), nil
+	}
+
+	mode := postgres.TrackQueueFilterExclude
+	if discord.OptionValueByName(options, "mode") == postgres.TrackQueueFilterInclude {
+		mode = postgres.TrackQueueFilterInclude
+	}
+	if err := withTrackDBTimeout(func(ctx context.Context) error {
+		return db.AddTrackQueueFilter(ctx, postgres.TrackQueueFilter{GuildID: guildID, PUUID: puuid, Kind: kind, Value: value, Mode: mode})
+	}); err != nil {
+		return "", err
+	}
+	if mode == postgres.TrackQueueFilterInclude {
+		return fmt.Sprintf("Games in %s will be posted for %s.\nOnce a scope has include filters, only included queues are posted.", label, scope), nil
+	}
+	return fmt.Sprintf("Games in %s will no longer be posted for %s.", label, scope), nil
+}
+
+// resolveTrackFilterScope maps the optional account option to a PUUID; an empty account means the whole server.
+func resolveTrackFilterScope(db storage.TrackFilterDB, guildID, riotID string) (string, string, error) {
+	if riotID == "" {
+		return "", "this server", nil
+	}
+	if _, _, err := riot.SplitRiotID(riotID); err != nil {
+		return "", "", trackFilterInputError("Select an account from the list.")
+	}
+	account, found, err := withTrackDBTimeoutLookup(func(ctx context.Context) (postgres.TrackedAccount, bool, error) {
+		return db.TrackedAccountByRiotID(ctx, guildID, riotID)
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if !found {
+		return "", "", trackFilterInputError(fmt.Sprintf("`%s` is not tracked on this server.", riotID))
+	}
+	return account.PUUID, fmt.Sprintf("`%s`", account.RiotID()), nil
+}
+
+func listTrackFilters(db storage.CommandDB, guildID string) (string, error) {
+	filters, err := withTrackDBTimeoutValue(func(ctx context.Context) ([]postgres.TrackQueueFilter, error) {
+		return db.ListTrackQueueFilters(ctx, guildID)
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(filters) == 0 {
+		return "No queue filters. Every supported queue is posted.", nil
+	}
+
+	accounts, err := withTrackDBTimeoutValue(func(ctx context.Context) ([]postgres.TrackedAccount, error) {
+		return db.ListTrackedAccounts(ctx, guildID, trackFilterListLimit)
+	})
+	if err != nil {
+		return "", err
+	}
+	riotIDs := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		riotIDs[account.PUUID] = account.RiotID()
+	}
+	return formatTrackFilters(filters, riotIDs, func(kind, value string) string {
+		return trackFilterTargetLabel(db, kind, value)
+	}), nil
+}
+
+// formatTrackFilters groups rules by scope: server rules first, then one block per account.
+func formatTrackFilters(filters []postgres.TrackQueueFilter, riotIDs map[string]string, label func(kind, value string) string) string {
+	var b strings.Builder
+	currentScope := "\x00"
+	for _, filter := range filters {
+		if filter.PUUID != currentScope {
+			currentScope = filter.PUUID
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			switch name, ok := riotIDs[filter.PUUID]; {
+			case filter.PUUID == "":
+				b.WriteString("**Server**\n")
+			case ok:
+				fmt.Fprintf(&b, "**%s**\n", name)
+			default:
+				b.WriteString("**Untracked account**\n")
+			}
+		}
+		marker := "➖"
+		if filter.Mode == postgres.TrackQueueFilterInclude {
+			marker = "➕"
+		}
+		fmt.Fprintf(&b, "%s %s\n", marker, label(filter.Kind, filter.Value))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parseTrackFilterTarget decodes autocomplete values ("queue:420", "category:kpvp") and bare queue IDs.
+func parseTrackFilterTarget(raw string) (kind, value string, ok bool) {
+	raw = strings.TrimSpace(raw)
+	if id, err := strconv.Atoi(raw); err == nil && id > 0 {
+		return postgres.TrackQueueFilterKindQueue, raw, true
+	}
+	kind, value, found := strings.Cut(raw, ":")
+	kind, value = strings.ToLower(strings.TrimSpace(kind)), strings.TrimSpace(value)
+	if !found || value == "" {
+		return "", "", false
+	}
+	switch kind {
+	case postgres.TrackQueueFilterKindQueue:
+		if id, err := strconv.Atoi(value); err != nil || id <= 0 {
+			return "", "", false
+		}
+		return kind, value, true
+	case postgres.TrackQueueFilterKindCategory:
+		return kind, strings.ToLower(value), true
+	default:
+		return "", "", false
+	}
+}
+
+func trackFilterTargetLabel(db storage.TrackFilterDB, kind, value string) string {
+	if kind == postgres.TrackQueueFilterKindCategory {
+		return fmt.Sprintf("category `%s`", value)
+	}
+	id, _ := strconv.Atoi(value)
+	display, found, err := withTrackDBTimeoutLookup(func(ctx context.Context) (postgres.QueueDisplay, bool, error) {
+		return db.QueueDisplayByID(ctx, id)
+	})
+	if err != nil || !found || strings.TrimSpace(display.Name) == "" {
+		return fmt.Sprintf("queue `%s`", value)
+	}
+	return fmt.Sprintf("**%s** (`%d`)", strings.TrimSpace(display.Name), id)
+}
+
+func handleTrackFilterAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, db storage.CommandDB, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(options) == 0 || options[0] == nil {
+		respondTrackAutocompleteChoices(s, i, nil)
+		return
+	}
+	subOptions := options[0].Options
+	query := discord.FocusedOptionValue(subOptions)
+
+	switch discord.FocusedOptionName(subOptions) {
+	case "account":
+		respondTrackedAccountAutocomplete(s, i, db, guildID, query)
+	case "queue":
+		respondTrackQueueAutocomplete(s, i, db, query)
+	default:
+		respondTrackAutocompleteChoices(s, i, nil)
+	}
+}
+
+func respondTrackQueueAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, db storage.TrackFilterDB, query string) {
+	queues, err := withTrackDBTimeoutValue(func(ctx context.Context) ([]postgres.QueueDisplay, error) {
+		return db.SearchQueueDisplays(ctx, query, trackAutocompleteLimit)
+	})
+	if err != nil {
+		slog.Warn("Failed to search queues for autocomplete", "error", err)
+	}
+	categories, err := withTrackDBTimeoutValue(func(ctx context.Context) ([]string, error) {
+		return db.QueueCategories(ctx)
+	})
+	if err != nil {
+		slog.Warn("Failed to list queue categories for autocomplete", "error", err)
+	}
+	respondTrackAutocompleteChoices(s, i, buildTrackFilterQueueChoices(queues, categories, query))
+}
+
+// buildTrackFilterQueueChoices lists matching categories first, then named queues.
+func buildTrackFilterQueueChoices(queues []postgres.QueueDisplay, categories []string, query string) []*discordgo.ApplicationCommandOptionChoice {
+	query = strings.ToLower(strings.TrimSpace(query))
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, trackAutocompleteLimit)
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if category == "" || (query != "" && !strings.Contains(strings.ToLower(category), query)) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  "Category: " + category,
+			Value: postgres.TrackQueueFilterKindCategory + ":" + strings.ToLower(category),
+		})
+	}
+	for _, queue := range queues {
+		if len(choices) >= trackAutocompleteLimit {
+			break
+		}
+		name := strings.TrimSpace(queue.Name)
+		if name == "" || queue.QueueID <= 0 {
+			continue
+		}
+		label := fmt.Sprintf("%s (%d)", name, queue.QueueID)
+		if labelRunes := []rune(label); len(labelRunes) > 100 {
+			label = string(labelRunes[:100])
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  label,
+			Value: fmt.Sprintf("%s:%d", postgres.TrackQueueFilterKindQueue, queue.QueueID),
+		})
+	}
+	return choices[:min(len(choices), trackAutocompleteLimit)]
+}
+
+func capitalizeFirst(value string) string {
+	if value == "" {
+		return value
+	}
+	runes := []rune(value)
+	return strings.ToUpper(string(runes[0])) + string(runes[1:])
+}
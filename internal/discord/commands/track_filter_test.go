@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestParseTrackFilterTarget(t *testing.T) {
+	tests := []struct {
+		raw       string
+		wantKind  string
+		wantValue string
+		wantOK    bool
+	}{
+		{raw: "queue:420", wantKind: "queue", wantValue: "420", wantOK: true},
+		{raw: " 450 ", wantKind: "queue", wantValue: "450", wantOK: true},
+		{raw: "category:kPvP", wantKind: "category", wantValue: "kpvp", wantOK: true},
+		{raw: "queue:aram"},
+		{raw: "map:11"},
+		{raw: "category:"},
+		{raw: ""},
+	}
+	for _, tc := range tests {
+		kind, value, ok := parseTrackFilterTarget(tc.raw)
+		if kind != tc.wantKind || value != tc.wantValue || ok != tc.wantOK {
+			t.Fatalf("parseTrackFilterTarget(%q) = %q, %q, %v; want %q, %q, %v", tc.raw, kind, value, ok, tc.wantKind, tc.wantValue, tc.wantOK)
+		}
+	}
+}
+
+func TestBuildTrackFilterQueueChoices(t *testing.T) {
+	queues := []postgres.QueueDisplay{
+		{QueueID: 420, Name: "Ranked Solo/Duo"},
+		{QueueID: 450, Name: "ARAM"},
+		{QueueID: 0, Name: "Custom"},
+	}
+	choices := buildTrackFilterQueueChoices(queues, []string{"kPvP", "kVersusAi"}, "")
+	if len(choices) != 4 {
+		t.Fatalf("len(choices) = %d, want 4", len(choices))
+	}
+	if choices[0].Name != "Category: kPvP" || choices[0].Value != "category:kpvp" {
+		t.Fatalf("choices[0] = %+v", choices[0])
+	}
+	if choices[2].Name != "Ranked Solo/Duo (420)" || choices[2].Value != "queue:420" {
+		t.Fatalf("choices[2] = %+v", choices[2])
+	}
+
+	choices = buildTrackFilterQueueChoices(queues[1:2], []string{"kPvP", "kVersusAi"}, "versus")
+	if len(choices) != 2 || choices[0].Value != "category:kversusai" || choices[1].Value != "queue:450" {
+		t.Fatalf("filtered choices = %+v, %+v", choices[0], choices[1])
+	}
+}
+
+func TestFormatTrackFilters(t *testing.T) {
+	filters := []postgres.TrackQueueFilter{
+		{Kind: "queue", Value: "450", Mode: postgres.TrackQueueFilterExclude},
+		{PUUID: "smurf", Kind: "queue", Value: "420", Mode: postgres.TrackQueueFilterInclude},
+		{PUUID: "gone", Kind: "category", Value: "kpvp", Mode: postgres.TrackQueueFilterExclude},
+	}
+	label := func(kind, value string) string { return kind + " " + value }
+
+	got := formatTrackFilters(filters, map[string]string{"smurf": "Smurf#BR1"}, label)
+	want := "**Server**\n➖ queue 450\n\n**Smurf#BR1**\n➕ queue 420\n\n**Untracked account**\n➖ category kpvp"
+	if got != want {
+		t.Fatalf("formatTrackFilters() = %q, want %q", got, want)
+	}
+}
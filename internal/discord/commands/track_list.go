@@ -0,0 +1,496 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	trackListPrefix          = "tracklist"
+	trackListPageSize        = 10
+	trackListPageTTL         = 15 * time.Minute
+	trackExportFormatCSV     = "csv"
+	trackExportFormatJSON    = "json"
+	trackImportTimeout       = 10 * time.Minute
+	trackImportMaxFileSize   = 256 << 10
+	trackListDBPageSize      = 100
+	trackImportHTTPTimeout   = 15 * time.Second
+	trackImportDefaultFormat = trackExportFormatCSV
+	// trackTransferMaxAccounts bounds both /track list export and /track import, so every export can be imported back.
+	trackTransferMaxAccounts = 1000
+)
+
+var (
+	errTrackImportFormat   = errors.New("file is not a tracked account export")
+	errTrackImportTooLarge = errors.New("import file is too large")
+	errTrackImportTooMany  = errors.New("import file has too many accounts")
+
+	trackImportHTTPClient = &http.Client{Timeout: trackImportHTTPTimeout}
+
+	// trackExportColumns is the CSV header written by /track list export and required by /track import.
+	trackExportColumns = []string{"region", "game_name", "tag_line", "added_by", "added_at"}
+)
+
+var trackListSubcommand = &discordgo.ApplicationCommandOption{
+	Name:        "list",
+	Description: "List the accounts tracked on this server.",
+	Type:        discordgo.ApplicationCommandOptionSubCommand,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "sort",
+			Description: "Order of the list.",
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Name", Value: postgres.TrackAccountSortName},
+				{Name: "Date added", Value: postgres.TrackAccountSortDate},
+				{Name: "Region", Value: postgres.TrackAccountSortRegion},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "region",
+			Description: "Only list accounts of this region.",
+			Choices:     discord.RegionOption.Choices,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "export",
+			Description: "Send the whole list as a file that /track import accepts.",
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "CSV", Value: trackExportFormatCSV},
+				{Name: "JSON", Value: trackExportFormatJSON},
+			},
+		},
+	},
+}
+
+var trackImportSubcommand = &discordgo.ApplicationCommandOption{
+	Name:        "import",
+	Description: "Track the accounts of a file exported with /track list.",
+	Type:        discordgo.ApplicationCommandOptionSubCommand,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionAttachment,
+			Name:        "file",
+			Description: "CSV or JSON file exported with /track list.",
+			Required:    true,
+		},
+	},
+}
+
+// trackExportAccount is one account of a JSON export.
+type trackExportAccount struct {
+	Region   string    `json:"region"`
+	GameName string    `json:"game_name"`
+	TagLine  string    `json:"tag_line"`
+	AddedBy  string    `json:"added_by,omitempty"`
+	AddedAt  time.Time `json:"added_at,omitzero"`
+}
+
+func handleTrackList(s *discordgo.Session, i *discordgo.InteractionCreate, db storage.TrackDB, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if !requireTrackConfig(s, i, db, guildID) {
+		return
+	}
+	sort, region := discord.OptionValueByName(options, "sort"), discord.OptionValueByName(options, "region")
+	if format := discord.OptionValueByName(options, "export"); format != "" {
+		if err := discord.RunDeferredCommand(s, i, trackAddTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+			return exportTrackedAccounts(ctx, db, guildID, sort, region, format)
+		}, mapTrackListError); err != nil {
+			slog.Error("Failed to run deferred /track list export", "error", err)
+		}
+		return
+	}
+
+	if err := discord.RunDeferredCommand(s, i, trackAddTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		return loadTrackListPage(ctx, db, guildID, sort, region, 1)
+	}, mapTrackListError); err != nil {
+		slog.Error("Failed to run deferred /track list", "error", err)
+	}
+}
+
+// handleTrackListPage answers the Previous/Next buttons; sort and region are carried in the custom ID.
+func handleTrackListPage(s *discordgo.Session, i *discordgo.InteractionCreate, id discord.ComponentID) {
+	rt := currentRuntime()
+	guildID := strings.TrimSpace(i.GuildID)
+	page, err := strconv.Atoi(id.Arg(0))
+	if err != nil || guildID == "" || rt.Database == nil {
+		discord.RespondWithError(s, i, "Invalid command input.")
+		return
+	}
+	if err := discord.RunDeferredComponentUpdate(s, i, trackAddTimeout, func(ctx context.Context) (discord.DeferredResponse, error) {
+		return loadTrackListPage(ctx, rt.Database, guildID, id.Arg(1), id.Arg(2), page)
+	}, mapTrackListError); err != nil {
+		slog.Error("Failed to update /track list page", "error", err)
+	}
+}
+
+func loadTrackListPage(ctx context.Context, db storage.TrackDB, guildID, sort, region string, page int) (discord.DeferredResponse, error) {
+	page = max(page, 1)
+	query := postgres.TrackedAccountPage{Sort: sort, Region: region, Offset: (page - 1) * trackListPageSize, Limit: trackListPageSize}
+	accounts, total, err := db.ListTrackedAccountsPage(ctx, guildID, query)
+	if err != nil {
+		return discord.DeferredResponse{}, fmt.Errorf("list tracked accounts page: %w", err)
+	}
+	// The list may have shrunk since the buttons were sent; show its last page instead.
+	if len(accounts) == 0 && total > 0 {
+		page = trackListPageCount(total)
+		query.Offset = (page - 1) * trackListPageSize
+		if accounts, total, err = db.ListTrackedAccountsPage(ctx, guildID, query); err != nil {
+			return discord.DeferredResponse{}, fmt.Errorf("list tracked accounts page: %w", err)
+		}
+	}
+	if total == 0 {
+		message := "No tracked accounts found for this server.\nUse `/track add` first."
+		if riot.NormalizePlatformRegion(region) != "" {
+			message = fmt.Sprintf("No tracked accounts found in %s.", strings.ToUpper(riot.NormalizePlatformRegion(region)))
+		}
+		return discord.DeferredResponse{
+			Embeds:     []*discordgo.MessageEmbed{trackInfoEmbed(message)},
+			Components: []discordgo.MessageComponent{},
+		}, nil
+	}
+
+	pages := trackListPageCount(total)
+	return discord.DeferredResponse{
+		Embeds:     []*discordgo.MessageEmbed{buildTrackListEmbed(accounts, sort, region, page, pages, total)},
+		Components: trackListPageComponents(sort, region, page, pages),
+	}, nil
+}
+
+func mapTrackListError(error) string {
+	return "Could not load tracked accounts right now. Please try again."
+}
+
+func buildTrackListEmbed(accounts []postgres.TrackedAccount, sort, region string, page, pages, total int) *discordgo.MessageEmbed {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sorted by %s", trackListSortLabel(sort))
+	if region = riot.NormalizePlatformRegion(region); region != "" {
+		fmt.Fprintf(&b, " · %s only", strings.ToUpper(region))
+	}
+	b.WriteString("\n")
+	offset := (page - 1) * trackListPageSize
+	for idx, account := range accounts {
+		addedBy := "unknown"
+		if account.AddedBy != "" {
+			addedBy = fmt.Sprintf("<@%s>", account.AddedBy)
+		}
+		fmt.Fprintf(&b, "\n**%d.** `%s` · %s · added by %s <t:%d:d>",
+			offset+idx+1, account.RiotID(), strings.ToUpper(account.PlatformRegion), addedBy, account.AddedAt.Unix())
+	}
+	fmt.Fprintf(&b, "\n\nPage %d/%d · %d accounts", page, pages, total)
+
+	embed := trackInfoEmbed(b.String())
+	embed.Title = "Tracked Accounts"
+	return embed
+}
+
+func trackListSortLabel(sort string) string {
+	switch sort {
+	case postgres.TrackAccountSortDate:
+		return "date added"
+	case postgres.TrackAccountSortRegion:
+		return "region"
+	default:
+		return "name"
+	}
+}
+
+func trackListPageCount(total int) int {
+	return max(1, (total+trackListPageSize-1)/trackListPageSize)
+}
+
+// trackListPageComponents returns the Previous/Next buttons, or an empty row set for a single page.
+func trackListPageComponents(sort, region string, page, pages int) []discordgo.MessageComponent {
+	if pages <= 1 {
+		return []discordgo.MessageComponent{}
+	}
+	previous, errPrevious := discord.EncodeComponentID(trackListPrefix, trackListPageTTL, strconv.Itoa(page-1), sort, region)
+	next, errNext := discord.EncodeComponentID(trackListPrefix, trackListPageTTL, strconv.Itoa(page+1), sort, region)
+	if errPrevious != nil || errNext != nil {
+		slog.Warn("Failed to encode /track list page buttons", "error", errors.Join(errPrevious, errNext))
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Previous", Style: discordgo.SecondaryButton, CustomID: previous, Disabled: page <= 1},
+			discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: next, Disabled: page >= pages},
+		}},
+	}
+}
+
+func exportTrackedAccounts(ctx context.Context, db storage.TrackDB, guildID, sort, region, format string) (discord.DeferredResponse, error) {
+	accounts := make([]postgres.TrackedAccount, 0)
+	total := 0
+	for len(accounts) < trackTransferMaxAccounts {
+		page, pageTotal, err := db.ListTrackedAccountsPage(ctx, guildID, postgres.TrackedAccountPage{
+			Sort: sort, Region: region, Offset: len(accounts), Limit: min(trackListDBPageSize, trackTransferMaxAccounts-len(accounts)),
+		})
+		if err != nil {
+			return discord.DeferredResponse{}, fmt.Errorf("list tracked accounts for export: %w", err)
+		}
+		accounts, total = append(accounts, page...), pageTotal
+		if len(page) == 0 || len(accounts) >= total {
+			break
+		}
+	}
+	if len(accounts) == 0 {
+		return discord.DeferredResponse{Embeds: []*discordgo.MessageEmbed{trackInfoEmbed("No tracked accounts found for this server.\nUse `/track add` first.")}}, nil
+	}
+
+	content, contentType, err := encodeTrackExport(accounts, format)
+	if err != nil {
+		return discord.DeferredResponse{}, err
+	}
+	return discord.DeferredResponse{
+		Embeds: []*discordgo.MessageEmbed{trackInfoEmbed(trackExportMessage(len(accounts), total))},
+		Files: []*discordgo.File{{
+			Name:        "tracked-accounts." + format,
+			ContentType: contentType,
+			Reader:      bytes.NewReader(content),
+		}},
+	}, nil
+}
+
+// trackExportMessage describes an export of exported out of total accounts, naming the cap when it cut the file short.
+func trackExportMessage(exported, total int) string {
+	if total > exported {
+		return fmt.Sprintf("Exported **%d** of **%d** tracked accounts; an export holds at most %d.\nUse the `region` option to export the others, and `/track import` with each file to track them again.", exported, total, trackTransferMaxAccounts)
+	}
+	return fmt.Sprintf("Exported **%d** tracked accounts.\nUse `/track import` with this file to track them again.", exported)
+}
+
+func encodeTrackExport(accounts []postgres.TrackedAccount, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case trackExportFormatJSON:
+		out := make([]trackExportAccount, 0, len(accounts))
+		for _, account := range accounts {
+			out = append(out, trackExportAccount{
+				Region:   account.PlatformRegion,
+				GameName: account.NickName,
+				TagLine:  account.TagLine,
+				AddedBy:  account.AddedBy,
+				AddedAt:  account.AddedAt,
+			})
+		}
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(out); err != nil {
+			return nil, "", fmt.Errorf("encode json export: %w", err)
+		}
+		return buf.Bytes(), "application/json", nil
+	case trackExportFormatCSV:
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write(trackExportColumns)
+		for _, account := range accounts {
+			_ = writer.Write([]string{account.PlatformRegion, account.NickName, account.TagLine, account.AddedBy, account.AddedAt.Format(time.RFC3339)})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, "", fmt.Errorf("encode csv export: %w", err)
+		}
+		return buf.Bytes(), "text/csv", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// handleTrackImport tracks the accounts of an exported file with the same lookups and report as /track bulk.
+func handleTrackImport(s *discordgo.Session, i *discordgo.InteractionCreate, rt Runtime, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if rt.Riot == nil {
+		discord.RespondWithError(s, i, "The command is not configured.")
+		return
+	}
+	if !hasManageServerPermission(i) {
+		discord.RespondWithError(s, i, "You need the `Manage Server` permission to use `/track import`.")
+		return
+	}
+	if !requireTrackConfig(s, i, rt.Database, guildID) {
+		return
+	}
+	attachment := trackImportAttachment(i, discord.OptionValueByName(options, "file"))
+	if attachment == nil {
+		discord.RespondWithError(s, i, "The file is required.")
+		return
+	}
+	if attachment.Size > trackImportMaxFileSize {
+		discord.RespondWithError(s, i, fmt.Sprintf("The file is too large. The limit is %d KB.", trackImportMaxFileSize>>10))
+		return
+	}
+
+	_, userID := discord.InteractionUserID(i)
+	if err := discord.RunDeferredEmbedCommand(s, i, trackImportTimeout, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
+		content, err := downloadTrackImport(ctx, attachment.URL)
+		if err != nil {
+			return nil, err
+		}
+		lines, err := parseTrackImport(content, attachment.Filename)
+		if err != nil {
+			return nil, err
+		}
+		results := addTrackBulkAccounts(ctx, rt, guildID, userID, lines)
+		return []*discordgo.MessageEmbed{trackBulkReportEmbed(results)}, nil
+	}, mapTrackImportError); err != nil {
+		slog.Error("Failed to run deferred /track import", "error", err)
+	}
+}
+
+func trackImportAttachment(i *discordgo.InteractionCreate, attachmentID string) *discordgo.MessageAttachment {
+	data := i.ApplicationCommandData()
+	if data.Resolved == nil || attachmentID == "" {
+		return nil
+	}
+	return data.Resolved.Attachments[attachmentID]
+}
+
+func downloadTrackImport(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build import request: %w", err)
+	}
+	resp, err := trackImportHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download import file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download import file: status %d", resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, trackImportMaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read import file: %w", err)
+	}
+	if len(content) > trackImportMaxFileSize {
+		return nil, errTrackImportTooLarge
+	}
+	return content, nil
+}
+
+// parseTrackImport reads a CSV or JSON export, picked by the file extension or else by its first character.
+func parseTrackImport(content []byte, filename string) ([]trackBulkLine, error) {
+	format := strings.TrimPrefix(strings.ToLower(path.Ext(filename)), ".")
+	if format != trackExportFormatCSV && format != trackExportFormatJSON {
+		format = trackImportDefaultFormat
+		if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+			format = trackExportFormatJSON
+		}
+	}
+
+	var accounts []trackExportAccount
+	var numbers []int
+	var err error
+	if format == trackExportFormatJSON {
+		accounts, numbers, err = decodeTrackImportJSON(content)
+	} else {
+		accounts, numbers, err = decodeTrackImportCSV(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, errTrackImportFormat
+	}
+	if len(accounts) > trackTransferMaxAccounts {
+		return nil, errTrackImportTooMany
+	}
+
+	lines := make([]trackBulkLine, 0, len(accounts))
+	seen := make(map[string]bool)
+	for idx, account := range accounts {
+		raw := strings.TrimSpace(fmt.Sprintf("%s#%s %s", strings.TrimSpace(account.GameName), strings.TrimSpace(account.TagLine), strings.TrimSpace(account.Region)))
+		lines = append(lines, parseTrackBulkLine(trackBulkLine{Number: numbers[idx], Raw: raw}, seen))
+	}
+	return lines, nil
+}
+
+func decodeTrackImportJSON(content []byte) ([]trackExportAccount, []int, error) {
+	var accounts []trackExportAccount
+	if err := json.Unmarshal(content, &accounts); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errTrackImportFormat, err)
+	}
+	numbers := make([]int, len(accounts))
+	for idx := range accounts {
+		numbers[idx] = idx + 1
+	}
+	return accounts, numbers, nil
+}
+
+// decodeTrackImportCSV maps columns by the header, so extra or reordered columns are fine.
+func decodeTrackImportCSV(content []byte) ([]trackExportAccount, []int, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errTrackImportFormat, err)
+	}
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = idx
+	}
+	regionCol, okRegion := columns["region"]
+	nameCol, okName := columns["game_name"]
+	tagCol, okTag := columns["tag_line"]
+	if !okRegion || !okName || !okTag {
+		return nil, nil, errTrackImportFormat
+	}
+
+	field := func(record []string, idx int) string {
+		if idx < len(record) {
+			return record[idx]
+		}
+		return ""
+	}
+	accounts := make([]trackExportAccount, 0)
+	numbers := make([]int, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", errTrackImportFormat, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		accounts = append(accounts, trackExportAccount{
+			Region:   field(record, regionCol),
+			GameName: field(record, nameCol),
+			TagLine:  field(record, tagCol),
+		})
+		numbers = append(numbers, line)
+	}
+	return accounts, numbers, nil
+}
+
+func mapTrackImportError(err error) string {
+	switch {
+	case errors.Is(err, errTrackImportFormat):
+		return "The file is not a tracked account export.\nUse a CSV or JSON file from `/track list export`."
+	case errors.Is(err, errTrackImportTooLarge):
+		return fmt.Sprintf("The file is too large. The limit is %d KB.", trackImportMaxFileSize>>10)
+	case errors.Is(err, errTrackImportTooMany):
+		return fmt.Sprintf("You can import up to %d accounts at once.", trackTransferMaxAccounts)
+	default:
+		return "Could not read the file right now. Please try again."
+	}
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestBuildTrackListEmbed(t *testing.T) {
+	addedAt := time.Unix(1_700_000_000, 0).UTC()
+	accounts := []postgres.TrackedAccount{
+		{PlatformRegion: "kr", NickName: "Faker", TagLine: "KR1", AddedBy: "42", AddedAt: addedAt},
+		{PlatformRegion: "kr", NickName: "Chovy", TagLine: "KR1", AddedAt: addedAt},
+	}
+	got := buildTrackListEmbed(accounts, postgres.TrackAccountSortDate, "KR", 2, 3, 22).Description
+	for _, want := range []string{
+		"Sorted by date added · KR only",
+		"**11.** `Faker#KR1` · KR · added by <@42> <t:1700000000:d>",
+		"**12.** `Chovy#KR1` · KR · added by unknown",
+		"Page 2/3 · 22 accounts",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("description = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestTrackListPageComponents(t *testing.T) {
+	if got := trackListPageComponents("name", "", 1, 1); len(got) != 0 {
+		t.Fatalf("single page components = %+v, want none", got)
+	}
+	if got := trackListPageComponents(postgres.TrackAccountSortRegion, "br1", 2, 3); len(got) != 1 {
+		t.Fatalf("components = %+v, want one row", got)
+	}
+}
+
+func TestTrackExportImportRoundTrip(t *testing.T) {
+	accounts := []postgres.TrackedAccount{
+		{PlatformRegion: "kr", NickName: "Faker", TagLine: "KR1", AddedBy: "42", AddedAt: time.Unix(1_700_000_000, 0).UTC()},
+		{PlatformRegion: "br1", NickName: "Ahri, Main", TagLine: "BR1"},
+	}
+	for _, format := range []string{trackExportFormatCSV, trackExportFormatJSON} {
+		content, _, err := encodeTrackExport(accounts, format)
+		if err != nil {
+			t.Fatalf("encodeTrackExport(%s) error = %v", format, err)
+		}
+		lines, err := parseTrackImport(content, "tracked-accounts."+format)
+		if err != nil {
+			t.Fatalf("parseTrackImport(%s) error = %v", format, err)
+		}
+		if len(lines) != 2 || lines[0].RiotID() != "Faker#KR1" || lines[0].Region != "kr" || lines[1].RiotID() != "Ahri, Main#BR1" || lines[1].Region != "br1" {
+			t.Fatalf("parseTrackImport(%s) = %+v", format, lines)
+		}
+		if lines[0].Err != "" || lines[1].Err != "" {
+			t.Fatalf("parseTrackImport(%s) errors = %q, %q", format, lines[0].Err, lines[1].Err)
+		}
+	}
+}
+
+func TestTrackExportAtTheLimitImportsBack(t *testing.T) {
+	accounts := make([]postgres.TrackedAccount, trackTransferMaxAccounts)
+	for idx := range accounts {
+		accounts[idx] = postgres.TrackedAccount{
+			PlatformRegion: "kr", NickName: fmt.Sprintf("페이커는최고의선수%04d", idx), TagLine: "KR1",
+			AddedBy: "123456789012345678", AddedAt: time.Unix(1_700_000_000, 0).UTC(),
+		}
+	}
+	for _, format := range []string{trackExportFormatCSV, trackExportFormatJSON} {
+		content, _, err := encodeTrackExport(accounts, format)
+		if err != nil {
+			t.Fatalf("encodeTrackExport(%s) error = %v", format, err)
+		}
+		if len(content) > trackImportMaxFileSize {
+			t.Fatalf("export of %d accounts as %s has %d bytes, above the %d bytes import limit", len(accounts), format, len(content), trackImportMaxFileSize)
+		}
+		if lines, err := parseTrackImport(content, "tracked-accounts."+format); err != nil || len(lines) != len(accounts) {
+			t.Fatalf("parseTrackImport(%s) = %d lines, %v, want %d", format, len(lines), err, len(accounts))
+		}
+	}
+}
+
+func TestTrackExportMessage(t *testing.T) {
+	if got := trackExportMessage(12, 12); !strings.HasPrefix(got, "Exported **12** tracked accounts.") {
+		t.Fatalf("trackExportMessage(complete) = %q", got)
+	}
+	got := trackExportMessage(trackTransferMaxAccounts, 1200)
+	if !strings.Contains(got, fmt.Sprintf("**%d** of **1200**", trackTransferMaxAccounts)) || !strings.Contains(got, "`region`") {
+		t.Fatalf("trackExportMessage(truncated) = %q, want the total and how to export the rest", got)
+	}
+}
+
+func TestParseTrackImport(t *testing.T) {
+	csvContent := "\ufeffTag_Line,Region,Game_Name\nKR1,kr,Faker\n\nKR1,,Chovy\n"
+	lines, err := parseTrackImport([]byte(csvContent), "")
+	if err != nil {
+		t.Fatalf("parseTrackImport(csv) error = %v", err)
+	}
+	if len(lines) != 2 || lines[0].RiotID() != "Faker#KR1" || lines[0].Number != 2 {
+		t.Fatalf("lines = %+v", lines)
+	}
+	if lines[1].Number != 4 || !strings.Contains(lines[1].Err, "region is required") {
+		t.Fatalf("line without region = %+v", lines[1])
+	}
+
+	if lines, err := parseTrackImport([]byte(`[{"region":"kr","game_name":"Faker","tag_line":"KR1"}]`), "upload.txt"); err != nil || len(lines) != 1 {
+		t.Fatalf("parseTrackImport(json sniffed) = %+v, %v", lines, err)
+	}
+	for _, content := range []string{"nick,tag\nFaker,KR1", "[]", "{"} {
+		if _, err := parseTrackImport([]byte(content), ""); !errors.Is(err, errTrackImportFormat) {
+			t.Fatalf("parseTrackImport(%q) error = %v, want errTrackImportFormat", content, err)
+		}
+	}
+
+	tooMany := "region,game_name,tag_line\n" + strings.Repeat("kr,Faker,KR1\n", trackTransferMaxAccounts+1)
+	if _, err := parseTrackImport([]byte(tooMany), "a.csv"); !errors.Is(err, errTrackImportTooMany) {
+		t.Fatalf("parseTrackImport(too many) error = %v, want errTrackImportTooMany", err)
+	}
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/storage"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleTrackRoute saves a routing rule from /track config. Routing an event back to the
+// default channel removes the rule, since the default channel already receives it. The post mode
+// and rename notices are saved with it when given.
+func handleTrackRoute(s *discordgo.Session, i *discordgo.InteractionCreate, db storage.CommandDB, guildID, channelID, event, queue, postMode string, announceRenames *bool) {
+	cfg, configured, err := loadTrackGuildConfig(db, guildID)
+	if err != nil {
+		slog.Error("Failed to load track config", "guildID", guildID, "error", err)
+		discord.RespondWithError(s, i, "Could not read tracking configuration. Please try again.")
+		return
+	}
+	if !configured {
+		discord.RespondWithError(s, i, "Set the default channel first with `/track config` without `event` or `queue`.")
+		return
+	}
+
+	if event == "" {
+		event = postgres.TrackRouteEventGame
+	}
+	route := postgres.TrackChannelRoute{GuildID: guildID, Event: event, ChannelID: channelID}
+	if queue != "" && event == postgres.TrackRouteEventRename {
+		discord.RespondWithError(s, i, "Name changes are not tied to a queue. Remove the `queue` option.")
+		return
+	}
+	if queue != "" {
+		kind, value, ok := parseTrackFilterTarget(queue)
+		if !ok {
+			discord.RespondWithError(s, i, "Select a queue or category from the list.")
+			return
+		}
+		route.QueueKind, route.QueueValue = kind, value
+	}
+	toDefault := strings.TrimSpace(cfg.ChannelID) == channelID
+
+	routes, err := withTrackDBTimeoutValue(func(ctx context.Context) ([]postgres.TrackChannelRoute, error) {
+		if toDefault {
+			if _, err := db.RemoveTrackChannelRoute(ctx, guildID, route.Event, route.QueueKind, route.QueueValue); err != nil {
+				return nil, err
+			}
+		} else if err := db.UpsertTrackChannelRoute(ctx, route); err != nil {
+			return nil, err
+		}
+		if postMode != "" {
+			if err := db.SetTrackGuildPostMode(ctx, guildID, postMode); err != nil {
+				return nil, err
+			}
+		}
+		if announceRenames != nil {
+			if err := db.SetTrackGuildAnnounceRenames(ctx, guildID, *announceRenames); err != nil {
+				return nil, err
+			}
+		}
+		return db.ListTrackChannelRoutes(ctx, guildID)
+	})
+	if err != nil {
+		slog.Error("Failed to save /track config route", "guildID", guildID, "channelID", channelID, "event", event, "queue", queue, "error", err)
+		discord.RespondWithError(s, i, "Could not save tracking configuration. Please try again.")
+		return
+	}
+
+	label := trackRouteLabel(db, route)
+	message := fmt.Sprintf("%s will be sent to <#%s>.", label, channelID)
+	if toDefault {
+		message = fmt.Sprintf("%s will be sent to the default channel <#%s>.", label, channelID)
+	}
+	if announceRenames != nil {
+		message += "\n" + trackRenameNoticesMessage(*announceRenames)
+	}
+	message += "\n\n" + formatTrackRoutes(cfg.ChannelID, routes, func(route postgres.TrackChannelRoute) string {
+		return trackRouteLabel(db, route)
+	})
+	if err := discord.RespondWithEmbed(s, i, trackInfoEmbed(message)); err != nil {
+		slog.Error("Failed to respond /track config", "error", err)
+	}
+}
+
+func trackRouteLabel(db storage.TrackFilterDB, route postgres.TrackChannelRoute) string {
+	label := "Live and post-game updates"
+	switch route.Event {
+	case postgres.TrackRouteEventRank:
+		label = "Rank changes"
+	case postgres.TrackRouteEventRename:
+		label = "Name changes"
+	}
+	if route.QueueKind == "" {
+		return label
+	}
+	return label + " in " + trackFilterTargetLabel(db, route.QueueKind, route.QueueValue)
+}
+
+func formatTrackRoutes(defaultChannelID string, routes []postgres.TrackChannelRoute, label func(postgres.TrackChannelRoute) string) string {
+	lines := make([]string, 0, len(routes)+1)
+	lines = append(lines, fmt.Sprintf("**Default**: <#%s>", defaultChannelID))
+	for _, route := range routes {
+		lines = append(lines, fmt.Sprintf("• %s → <#%s>", label(route), route.ChannelID))
+	}
+	return strings.Join(lines, "\n")
+}
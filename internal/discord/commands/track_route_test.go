@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestFormatTrackRoutes(t *testing.T) {
+	routes := []postgres.TrackChannelRoute{
+		{Event: postgres.TrackRouteEventGame, QueueKind: "queue", QueueValue: "420", ChannelID: "111"},
+		{Event: postgres.TrackRouteEventRank, ChannelID: "222"},
+	}
+	label := func(route postgres.TrackChannelRoute) string { return route.Event + ":" + route.QueueValue }
+
+	got := formatTrackRoutes("100", routes, label)
+	want := "**Default**: <#100>\n• game:420 → <#111>\n• rank: → <#222>"
+	if got != want {
+		t.Fatalf("formatTrackRoutes() = %q, want %q", got, want)
+	}
+	if got := formatTrackRoutes("100", nil, label); got != "**Default**: <#100>" {
+		t.Fatalf("formatTrackRoutes(no routes) = %q", got)
+	}
+}
+
+func TestTrackRouteLabelWithoutQueue(t *testing.T) {
+	if got := trackRouteLabel(nil, postgres.TrackChannelRoute{Event: postgres.TrackRouteEventRank}); got != "Rank changes" {
+		t.Fatalf("trackRouteLabel(rank) = %q", got)
+	}
+	if got := trackRouteLabel(nil, postgres.TrackChannelRoute{Event: postgres.TrackRouteEventGame}); got != "Live and post-game updates" {
+		t.Fatalf("trackRouteLabel(game) = %q", got)
+	}
+	if got := trackRouteLabel(nil, postgres.TrackChannelRoute{Event: postgres.TrackRouteEventRename}); got != "Name changes" {
+		t.Fatalf("trackRouteLabel(rename) = %q", got)
+	}
+}
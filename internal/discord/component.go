@@ -0,0 +1,233 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ComponentHandler answers a button, select menu or modal submit whose custom ID starts with the registered prefix.
+type ComponentHandler func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID)
+
+// ComponentID is a decoded custom ID. Components carry their state in the ID itself, so a click keeps
+// working after a restart; Expires bounds how long an old message stays interactive.
+type ComponentID struct {
+	Prefix  string
+	Args    []string
+	Expires time.Time
+}
+
+const (
+	componentIDSeparator = ":"
+	componentIDMaxLen    = 100
+)
+
+var (
+	ErrComponentIDTooLong = errors.New("component custom id is too long")
+	errComponentIDInvalid = errors.New("component custom id is invalid")
+
+	componentArgEscaper   = strings.NewReplacer("%", "%25", componentIDSeparator, "%3A")
+	componentArgUnescaper = strings.NewReplacer("%3A", componentIDSeparator, "%25", "%")
+
+	componentNow = time.Now
+)
+
+// EncodeComponentID builds "prefix:expiry:arg...". A ttl <= 0 never expires.
+func EncodeComponentID(prefix string, ttl time.Duration, args ...string) (string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" || strings.Contains(prefix, componentIDSeparator) {
+		return "", fmt.Errorf("%w: prefix %q", errComponentIDInvalid, prefix)
+	}
+	expiry := "0"
+	if ttl > 0 {
+		expiry = strconv.FormatInt(componentNow().Add(ttl).Unix(), 36)
+	}
+
+	parts := make([]string, 0, len(args)+2)
+	parts = append(parts, prefix, expiry)
+	for _, arg := range args {
+		parts = append(parts, componentArgEscaper.Replace(arg))
+	}
+	customID := strings.Join(parts, componentIDSeparator)
+	if len(customID) > componentIDMaxLen {
+		return "", fmt.Errorf("%w: %d characters", ErrComponentIDTooLong, len(customID))
+	}
+	return customID, nil
+}
+
+func ParseComponentID(customID string) (ComponentID, error) {
+	parts := strings.Split(customID, componentIDSeparator)
+	if len(parts) < 2 || strings.TrimSpace(parts[0]) == "" {
+		return ComponentID{}, fmt.Errorf("%w: %q", errComponentIDInvalid, customID)
+	}
+	id := ComponentID{Prefix: parts[0], Args: make([]string, 0, len(parts)-2)}
+	if parts[1] != "0" {
+		unix, err := strconv.ParseInt(parts[1], 36, 64)
+		if err != nil {
+			return ComponentID{}, fmt.Errorf("%w: expiry %q", errComponentIDInvalid, parts[1])
+		}
+		id.Expires = time.Unix(unix, 0).UTC()
+	}
+	for _, arg := range parts[2:] {
+		id.Args = append(id.Args, componentArgUnescaper.Replace(arg))
+	}
+	return id, nil
+}
+
+func (id ComponentID) Expired(now time.Time) bool {
+	return !id.Expires.IsZero() && now.After(id.Expires)
+}
+
+// Arg returns the n-th argument, or "" when the ID has fewer arguments.
+func (id ComponentID) Arg(n int) string {
+	if n < 0 || n >= len(id.Args) {
+		return ""
+	}
+	return id.Args[n]
+}
+
+func (r *Registry) AddComponent(prefix string, handler ComponentHandler) {
+	prefix = strings.TrimSpace(prefix)
+	if r == nil || prefix == "" || handler == nil {
+		return
+	}
+	if r.components == nil {
+		r.components = make(map[string]ComponentHandler)
+	}
+	r.components[prefix] = handler
+}
+
+func (r *Registry) ComponentHandler(prefix string) (ComponentHandler, bool) {
+	if r == nil {
+		return nil, false
+	}
+	h, ok := r.components[prefix]
+	return h, ok
+}
+
+// handleComponent routes message components and modal submits by the prefix of their custom ID.
+func (b *Bot) handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID, interactionType, what := "", "component", "button"
+	if i.Type == discordgo.InteractionModalSubmit {
+		customID, interactionType, what = i.ModalSubmitData().CustomID, "modal", "form"
+	} else {
+		customID = i.MessageComponentData().CustomID
+	}
+	username, userID := InteractionUserID(i)
+	b.logger.Info("Interaction", "component", customID, "type", interactionType, "username", username, "userID", userID, "guildID", i.GuildID)
+
+	id, err := ParseComponentID(customID)
+	if err != nil {
+		respondComponentError(s, i, fmt.Sprintf("This %s is not supported anymore.", what))
+		return
+	}
+	if id.Expired(componentNow()) {
+		respondComponentError(s, i, fmt.Sprintf("This %s has expired.\nRun the command again.", what))
+		return
+	}
+	h, ok := b.registry.ComponentHandler(id.Prefix)
+	if !ok {
+		respondComponentError(s, i, fmt.Sprintf("This %s is not supported anymore.", what))
+		return
+	}
+	h(s, i, id)
+}
+
+// RespondWithModal opens a modal; its custom ID should come from EncodeComponentID so the submit is routed back.
+func RespondWithModal(s *discordgo.Session, i *discordgo.InteractionCreate, customID, title string, components ...discordgo.MessageComponent) error {
+	return interactionRespond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   customID,
+			Title:      title,
+			Components: components,
+		},
+	})
+}
+
+// ModalTextValue returns the trimmed value of a text input in a submitted modal.
+func ModalTextValue(i *discordgo.InteractionCreate, customID string) string {
+	if i == nil || i.Interaction == nil || i.Type != discordgo.InteractionModalSubmit {
+		return ""
+	}
+	for _, component := range i.ModalSubmitData().Components {
+		var row discordgo.ActionsRow
+		switch c := component.(type) {
+		case *discordgo.ActionsRow:
+			row = *c
+		case discordgo.ActionsRow:
+			row = c
+		default:
+			continue
+		}
+		for _, child := range row.Components {
+			var input discordgo.TextInput
+			switch c := child.(type) {
+			case *discordgo.TextInput:
+				input = *c
+			case discordgo.TextInput:
+				input = c
+			default:
+				continue
+			}
+			if input.CustomID == customID {
+				return strings.TrimSpace(input.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// RunDeferredComponentUpdate acknowledges a component click and then replaces the message it belongs to.
+// Errors are sent as an ephemeral follow-up and leave the message untouched.
+func RunDeferredComponentUpdate(s *discordgo.Session, i *discordgo.InteractionCreate, timeout time.Duration, exec DeferredResponseExecutor, mapErr DeferredErrorMapper) error {
+	if s == nil {
+		return fmt.Errorf("discord session is required")
+	}
+	if i == nil || i.Interaction == nil {
+		return fmt.Errorf("interaction is required")
+	}
+	if exec == nil {
+		return fmt.Errorf("deferred executor is required")
+	}
+
+	if err := interactionRespond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cancel := func() {}
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	response, err := exec(ctx)
+	if err != nil {
+		slog.Error("Deferred component execution failed", "error", err)
+		message := "Could not connect to Riot servers.\nPlease try again later."
+		if mapErr != nil {
+			message = mapErr(err)
+		}
+		_, err := followupMessageCreate(s, i.Interaction, false, &discordgo.WebhookParams{
+			Embeds: []*discordgo.MessageEmbed{TemplateError(message, "Oops, something went wrong!")},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		})
+		return err
+	}
+	return editDeferredResponse(s, i, response)
+}
+
+func respondComponentError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
+	if err := respondWithEmbedsEphemeral(s, i, []*discordgo.MessageEmbed{TemplateError(message, "Error")}); err != nil {
+		slog.Error("Failed to respond to component interaction", "error", err)
+	}
+}
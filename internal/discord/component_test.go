@@ -0,0 +1,188 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestComponentIDRoundTrip(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0).UTC()
+	setComponentNow(t, now)
+
+	customID, err := EncodeComponentID("search", time.Hour, "br1", "Ahri:100%", "")
+	if err != nil {
+		t.Fatalf("EncodeComponentID() error = %v", err)
+	}
+	id, err := ParseComponentID(customID)
+	if err != nil {
+		t.Fatalf("ParseComponentID(%q) error = %v", customID, err)
+	}
+	if id.Prefix != "search" || id.Arg(0) != "br1" || id.Arg(1) != "Ahri:100%" || id.Arg(2) != "" || id.Arg(3) != "" {
+		t.Fatalf("decoded = %+v", id)
+	}
+	if !id.Expires.Equal(now.Add(time.Hour)) {
+		t.Fatalf("Expires = %v, want %v", id.Expires, now.Add(time.Hour))
+	}
+	if id.Expired(now) || !id.Expired(now.Add(2*time.Hour)) {
+		t.Fatalf("Expired() does not follow the encoded expiry")
+	}
+
+	forever, err := EncodeComponentID("page", 0, "2")
+	if err != nil || forever != "page:0:2" {
+		t.Fatalf("EncodeComponentID(no ttl) = %q, %v", forever, err)
+	}
+	if id, _ := ParseComponentID(forever); id.Expired(now.Add(1000 * time.Hour)) {
+		t.Fatal("IDs without ttl should never expire")
+	}
+}
+
+func TestComponentIDErrors(t *testing.T) {
+	if _, err := EncodeComponentID("bad:prefix", 0); err == nil {
+		t.Fatal("expected an error for a prefix containing the separator")
+	}
+	if _, err := EncodeComponentID("p", 0, strings.Repeat("x", 100)); !errors.Is(err, ErrComponentIDTooLong) {
+		t.Fatalf("EncodeComponentID(long) error = %v, want ErrComponentIDTooLong", err)
+	}
+	for _, customID := range []string{"", "prefix", ":0", "prefix:not-base36!"} {
+		if _, err := ParseComponentID(customID); err == nil {
+			t.Fatalf("ParseComponentID(%q) expected an error", customID)
+		}
+	}
+}
+
+func TestHandleComponent_Dispatch(t *testing.T) {
+	recorder := withDeferredCommandTestStubs(t)
+	now := time.Unix(1_700_000_000, 0).UTC()
+	setComponentNow(t, now)
+
+	var got []string
+	registry := NewRegistry()
+	registry.Add(&Command{
+		Data:    &discordgo.ApplicationCommand{Name: "leaderboard"},
+		Handler: func(*discordgo.Session, *discordgo.InteractionCreate) {},
+		Components: map[string]ComponentHandler{
+			"page": func(_ *discordgo.Session, _ *discordgo.InteractionCreate, id ComponentID) {
+				got = append(got, id.Arg(0))
+			},
+		},
+	})
+	bot := newTestBot(false, "", nil)
+	bot.registry = registry
+
+	valid, _ := EncodeComponentID("page", time.Minute, "3")
+	expired, _ := EncodeComponentID("page", time.Nanosecond, "4")
+	setComponentNow(t, now.Add(time.Second))
+	for _, customID := range []string{valid, expired, "unknown:0"} {
+		bot.handleInteraction(testSession(), componentInteraction(customID))
+	}
+
+	if len(got) != 1 || got[0] != "3" {
+		t.Fatalf("component handler calls = %v, want [3]", got)
+	}
+	if len(recorder.respondCalls) != 2 {
+		t.Fatalf("len(respondCalls) = %d, want 2 ephemeral errors", len(recorder.respondCalls))
+	}
+	for _, call := range recorder.respondCalls {
+		if call.resp.Data.Flags != discordgo.MessageFlagsEphemeral {
+			t.Fatalf("error response flags = %v, want ephemeral", call.resp.Data.Flags)
+		}
+	}
+}
+
+func TestRunDeferredComponentUpdate(t *testing.T) {
+	recorder := withDeferredCommandTestStubs(t)
+
+	err := RunDeferredComponentUpdate(testSession(), componentInteraction("page:0:2"), time.Second, func(context.Context) (DeferredResponse, error) {
+		return DeferredResponse{Embeds: []*discordgo.MessageEmbed{{Title: "page 2"}}, Components: []discordgo.MessageComponent{}}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("RunDeferredComponentUpdate() error = %v", err)
+	}
+	if len(recorder.respondCalls) != 1 || recorder.respondCalls[0].resp.Type != discordgo.InteractionResponseDeferredMessageUpdate {
+		t.Fatalf("respondCalls = %+v, want one deferred message update", recorder.respondCalls)
+	}
+	if len(recorder.editCalls) != 1 || recorder.editCalls[0].edit.Components == nil {
+		t.Fatalf("editCalls = %+v, want one edit that sets components", recorder.editCalls)
+	}
+
+	err = RunDeferredComponentUpdate(testSession(), componentInteraction("page:0:3"), time.Second, func(context.Context) (DeferredResponse, error) {
+		return DeferredResponse{}, errors.New("riot down")
+	}, func(error) string { return "mapped" })
+	if err != nil {
+		t.Fatalf("RunDeferredComponentUpdate(error) error = %v", err)
+	}
+	if len(recorder.editCalls) != 1 || len(recorder.followupCalls) != 1 {
+		t.Fatalf("edit/followup calls = %d/%d, want 1/1", len(recorder.editCalls), len(recorder.followupCalls))
+	}
+	if followup := recorder.followupCalls[0].data; followup.Flags != discordgo.MessageFlagsEphemeral || followup.Embeds[0].Description != "mapped" {
+		t.Fatalf("followup = %+v, want ephemeral mapped error", followup)
+	}
+}
+
+func TestHandleComponent_ModalSubmit(t *testing.T) {
+	recorder := withDeferredCommandTestStubs(t)
+
+	var got string
+	registry := NewRegistry()
+	registry.AddComponent("bulk", func(_ *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) {
+		got = id.Arg(0) + "|" + ModalTextValue(i, "accounts")
+	})
+	bot := newTestBot(false, "", nil)
+	bot.registry = registry
+
+	bot.handleInteraction(testSession(), modalInteraction("bulk:0:br1", "accounts", "  Ahri#BR1\nLux#BR1  "))
+	if got != "br1|Ahri#BR1\nLux#BR1" {
+		t.Fatalf("modal handler got %q", got)
+	}
+
+	bot.handleInteraction(testSession(), modalInteraction("missing:0", "accounts", "x"))
+	if len(recorder.respondCalls) != 1 || recorder.respondCalls[0].resp.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Fatalf("respondCalls = %+v, want one ephemeral error", recorder.respondCalls)
+	}
+}
+
+func TestModalTextValue(t *testing.T) {
+	i := modalInteraction("bulk:0", "accounts", "value")
+	if got := ModalTextValue(i, "other"); got != "" {
+		t.Fatalf("ModalTextValue(other) = %q, want empty", got)
+	}
+	if got := ModalTextValue(componentInteraction("bulk:0"), "accounts"); got != "" {
+		t.Fatalf("ModalTextValue(component) = %q, want empty", got)
+	}
+
+	// Modals built in code hold values, while decoded submits hold pointers.
+	i.Interaction.Data = discordgo.ModalSubmitInteractionData{CustomID: "bulk:0", Components: []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.TextInput{CustomID: "accounts", Value: "plain"}}},
+	}}
+	if got := ModalTextValue(i, "accounts"); got != "plain" {
+		t.Fatalf("ModalTextValue(values) = %q, want plain", got)
+	}
+}
+
+func setComponentNow(t *testing.T, now time.Time) {
+	t.Helper()
+	previous := componentNow
+	componentNow = func() time.Time { return now }
+	t.Cleanup(func() { componentNow = previous })
+}
+
+func componentInteraction(customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: customID, ComponentType: discordgo.ButtonComponent},
+	}}
+}
+
+func modalInteraction(customID, inputID, value string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionModalSubmit,
+		Data: discordgo.ModalSubmitInteractionData{CustomID: customID, Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{CustomID: inputID, Value: value}}},
+		}},
+	}}
+}
@@ -0,0 +1,212 @@
+package discord
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// emptyFieldText stands in for an empty field name or value, which Discord rejects.
+const emptyFieldText = "\u200b"
+
+// discordTokenPattern matches the markup Discord renders as a single element: custom emojis,
+// user, role and channel mentions, and timestamps. Cutting one in half would show its raw text.
+var discordTokenPattern = regexp.MustCompile(`<(?:a?:\w{2,32}:\d+|@[!&]?\d+|#\d+|t:-?\d+(?::[tTdDfFR])?)>`)
+
+// TruncateText shortens text to at most limit characters, ending it with "…". It never cuts inside a
+// custom emoji, mention or timestamp, nor between a character and the modifiers or joiners that render
+// with it.
+func TruncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	if limit <= 0 {
+		return ""
+	}
+	cut := safeCut(text, runes, limit-1)
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
+
+// SplitText splits text into chunks of at most limit characters, at line breaks where it can and
+// otherwise at the same safe points as TruncateText.
+func SplitText(text string, limit int) []string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+	var chunks []string
+	var current []string
+	currentLen := 0
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, "\n"))
+			current, currentLen = nil, 0
+		}
+	}
+	for line := range strings.SplitSeq(text, "\n") {
+		lineLen := utf8.RuneCountInString(line)
+		if len(current) > 0 && currentLen+1+lineLen > limit {
+			flush()
+		}
+		for lineLen > limit {
+			runes := []rune(line)
+			cut := safeCut(line, runes, limit)
+			if cut == 0 {
+				cut = limit
+			}
+			chunks = append(chunks, string(runes[:cut]))
+			line = string(runes[cut:])
+			lineLen -= cut
+		}
+		if len(current) > 0 {
+			currentLen++
+		}
+		current = append(current, line)
+		currentLen += lineLen
+	}
+	flush()
+	return chunks
+}
+
+// FitEmbed returns the embed as one or more embeds within Discord limits. Text is truncated with
+// TruncateText, a field value too long for one field continues in unnamed fields below it, and fields
+// beyond the count or size of one embed move to continuation embeds of the same color. The footer and
+// timestamp go to the last embed. The embed itself is not modified.
+func FitEmbed(embed *discordgo.MessageEmbed) []*discordgo.MessageEmbed {
+	if embed == nil {
+		return nil
+	}
+	first := *embed
+	first.Title = TruncateText(embed.Title, EmbedTitleLimit)
+	if embed.Author != nil {
+		author := *embed.Author
+		author.Name = TruncateText(author.Name, EmbedAuthorNameLimit)
+		first.Author = &author
+	}
+	var footer *discordgo.MessageEmbedFooter
+	if embed.Footer != nil {
+		footer = new(*embed.Footer)
+		footer.Text = TruncateText(footer.Text, EmbedFooterTextLimit)
+	}
+	footerLen := 0
+	if footer != nil {
+		footerLen = utf8.RuneCountInString(footer.Text)
+	}
+	first.Footer, first.Timestamp, first.Fields, first.Description = nil, "", nil, ""
+	room := EmbedTotalLimit - footerLen - EmbedLength(&first)
+	first.Description = TruncateText(embed.Description, min(EmbedDescriptionLimit, room))
+
+	out := []*discordgo.MessageEmbed{&first}
+	current, currentLen := &first, EmbedLength(&first)+footerLen
+	for _, field := range fitFields(embed.Fields) {
+		fieldLen := utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+		if len(current.Fields) >= EmbedFieldCountLimit || currentLen+fieldLen > EmbedTotalLimit {
+			current = &discordgo.MessageEmbed{Type: embed.Type, Color: embed.Color}
+			currentLen = footerLen
+			out = append(out, current)
+		}
+		current.Fields = append(current.Fields, field)
+		currentLen += fieldLen
+	}
+	last := out[len(out)-1]
+	last.Footer, last.Timestamp = footer, embed.Timestamp
+	return out
+}
+
+// fitFields copies the fields with their names truncated and their values split into continuation
+// fields, and empty names and values replaced by a zero width space.
+func fitFields(fields []*discordgo.MessageEmbedField) []*discordgo.MessageEmbedField {
+	out := make([]*discordgo.MessageEmbedField, 0, len(fields))
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		name := TruncateText(orEmptyFieldText(field.Name), EmbedFieldNameLimit)
+		for idx, value := range SplitText(orEmptyFieldText(field.Value), EmbedFieldValueLimit) {
+			if idx > 0 {
+				name = emptyFieldText
+			}
+			out = append(out, &discordgo.MessageEmbedField{Name: name, Value: orEmptyFieldText(value), Inline: field.Inline})
+		}
+	}
+	return out
+}
+
+func orEmptyFieldText(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return emptyFieldText
+	}
+	return text
+}
+
+// PackEmbeds fits every embed with FitEmbed and groups the results, in order, into as few messages as
+// Discord accepts: at most MaxEmbedsPerMessage embeds and EmbedTotalLimit characters each.
+func PackEmbeds(embeds ...*discordgo.MessageEmbed) [][]*discordgo.MessageEmbed {
+	var messages [][]*discordgo.MessageEmbed
+	var current []*discordgo.MessageEmbed
+	currentLen := 0
+	for _, embed := range embeds {
+		for _, fitted := range FitEmbed(embed) {
+			length := EmbedLength(fitted)
+			if len(current) >= MaxEmbedsPerMessage || (len(current) > 0 && currentLen+length > EmbedTotalLimit) {
+				messages = append(messages, current)
+				current, currentLen = nil, 0
+			}
+			current = append(current, fitted)
+			currentLen += length
+		}
+	}
+	if len(current) > 0 {
+		messages = append(messages, current)
+	}
+	return messages
+}
+
+// safeCut returns the largest rune index at most n where text can be cut without breaking a Discord
+// token or a character sequence that renders as one symbol.
+func safeCut(text string, runes []rune, n int) int {
+	cut := min(max(n, 0), len(runes))
+	for _, loc := range discordTokenPattern.FindAllStringIndex(text, -1) {
+		start := utf8.RuneCountInString(text[:loc[0]])
+		end := start + utf8.RuneCountInString(text[loc[0]:loc[1]])
+		if start < cut && cut < end {
+			cut = start
+			break
+		}
+		if start >= cut {
+			break
+		}
+	}
+	for cut > 0 && cut < len(runes) && joinsPrevious(runes, cut) {
+		cut--
+	}
+	return cut
+}
+
+// joinsPrevious reports whether runes[idx] renders together with runes[idx-1]: combining marks,
+// variation selectors, emoji modifiers and tags, zero width joiners, and the second half of a flag.
+func joinsPrevious(runes []rune, idx int) bool {
+	r, prev := runes[idx], runes[idx-1]
+	switch {
+	case r == '\u200d' || prev == '\u200d':
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Variation_Selector):
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff, r >= 0xe0020 && r <= 0xe007f:
+		return true
+	case isRegionalIndicator(r) && isRegionalIndicator(prev):
+		pairs := 0
+		for i := idx - 1; i >= 0 && isRegionalIndicator(runes[i]); i-- {
+			pairs++
+		}
+		return pairs%2 == 1
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
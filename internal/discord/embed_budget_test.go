@@ -0,0 +1,170 @@
+package discord
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{"fits", "Bekko#Ekko", 10, "Bekko#Ekko"},
+		{"ascii", "Bekko#Ekko", 6, "Bekko…"},
+		{"trailing space", "Mid or Feed", 5, "Mid…"},
+		{"multi-byte runes", "페이커는최고의선수", 4, "페이커…"},
+		{"custom emoji", "Bans: <:Yasuo:123456789> <:LeeSin:987654321>", 20, "Bans:…"},
+		{"animated emoji", "Ekko <a:Ekko:1>", 10, "Ekko…"},
+		{"timestamp", "Last seen <t:1770422047:R>", 20, "Last seen…"},
+		{"zero width joiner", "Team 👨‍👩‍👧 wins", 8, "Team…"},
+		{"skin tone", "GG 👍🏽👍🏽", 6, "GG 👍🏽…"},
+		{"flags", "🇧🇷🇰🇷🇯🇵", 4, "🇧🇷…"},
+		{"zero limit", "Bekko", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateText(tt.text, tt.limit)
+			if got != tt.want {
+				t.Fatalf("TruncateText(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.limit {
+				t.Fatalf("TruncateText(%q, %d) has %d characters", tt.text, tt.limit, n)
+			}
+		})
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	got := SplitText("<:Ekko:1> Bekko#Ekko\n<:Ahri:2> Ahri#BR1\n<:Zed:3> Zed#KR1", 40)
+	want := []string{"<:Ekko:1> Bekko#Ekko\n<:Ahri:2> Ahri#BR1", "<:Zed:3> Zed#KR1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SplitText() by lines = %q, want %q", got, want)
+	}
+
+	got = SplitText("ab <:Ekko:1> cd", 10)
+	want = []string{"ab ", "<:Ekko:1> ", "cd"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SplitText() of a long line = %q, want %q", got, want)
+	}
+
+	got = SplitText("ab <:Ekko:1> cd", 8)
+	want = []string{"ab ", "<:Ekko:1", "> cd"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SplitText() of a token longer than the limit = %q, want %q", got, want)
+	}
+}
+
+func TestFitEmbedKeepsValidEmbeds(t *testing.T) {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Bekko#Ekko",
+		Description: "Won a Ranked Solo/Duo game.",
+		Color:       0x008000,
+		Fields:      []*discordgo.MessageEmbedField{{Name: "KDA", Value: "9/2/7", Inline: true}},
+		Footer:      &discordgo.MessageEmbedFooter{Text: "League API bot"},
+		Timestamp:   "2026-02-07T12:00:00Z",
+	}
+	got := FitEmbed(embed)
+	if len(got) != 1 || !reflect.DeepEqual(got[0], embed) || got[0] == embed {
+		t.Fatalf("FitEmbed() = %+v, want a copy of the embed", got)
+	}
+}
+
+func TestFitEmbedSplitsOversizedEmbeds(t *testing.T) {
+	lines := make([]string, 0, 60)
+	for idx := range 60 {
+		lines = append(lines, fmt.Sprintf("<:Champion:%019d> 페이커는최고의선수입니다#KR%d", idx, idx))
+	}
+	fields := []*discordgo.MessageEmbedField{{Name: "🔵 Team", Value: strings.Join(lines, "\n"), Inline: true}, {Name: "", Value: ""}}
+	for idx := range 30 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("Field %d", idx), Value: "value"})
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       strings.Repeat("T", EmbedTitleLimit+10),
+		Description: strings.Repeat("d", EmbedDescriptionLimit),
+		Color:       0xf9f9f9,
+		Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: "https://example.com/icon.png"},
+		Fields:      fields,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "League API bot"},
+		Timestamp:   "2026-02-07T12:00:00Z",
+	}
+
+	got := FitEmbed(embed)
+	if len(got) < 2 {
+		t.Fatalf("FitEmbed() = %d embeds, want continuation embeds", len(got))
+	}
+	for idx, fitted := range got {
+		if err := CheckEmbedLimits(fitted); err != nil {
+			t.Fatalf("embed %d exceeds limits: %v", idx, err)
+		}
+		if fitted.Color != embed.Color {
+			t.Fatalf("embed %d color = %#x, want %#x", idx, fitted.Color, embed.Color)
+		}
+		last := idx == len(got)-1
+		if (fitted.Footer != nil) != last || (fitted.Timestamp != "") != last {
+			t.Fatalf("embed %d footer = %+v, timestamp = %q, want them only on the last embed", idx, fitted.Footer, fitted.Timestamp)
+		}
+	}
+	if got[0].Thumbnail == nil || got[1].Thumbnail != nil || got[1].Title != "" {
+		t.Fatalf("continuation embed = %+v, want only fields", got[1])
+	}
+	if !strings.HasSuffix(got[0].Title, "…") {
+		t.Fatalf("title = %q, want it truncated", got[0].Title)
+	}
+
+	var values []string
+	var names []string
+	for _, fitted := range got {
+		for _, field := range fitted.Fields {
+			names = append(names, field.Name)
+			if field.Inline {
+				values = append(values, field.Value)
+			}
+		}
+	}
+	if joined := strings.Join(values, "\n"); joined != fields[0].Value {
+		t.Fatalf("split team field does not join back to the original value")
+	}
+	if names[0] != "🔵 Team" || names[1] != emptyFieldText || names[len(values)] != emptyFieldText {
+		t.Fatalf("field names = %q, want continuation and empty fields named with a zero width space", names)
+	}
+	if len(names) != len(values)+31 {
+		t.Fatalf("got %d fields, want %d", len(names), len(values)+31)
+	}
+	if embed.Title != strings.Repeat("T", EmbedTitleLimit+10) || len(embed.Fields) != 32 || embed.Fields[1].Name != "" {
+		t.Fatalf("FitEmbed() modified its input")
+	}
+}
+
+func TestPackEmbeds(t *testing.T) {
+	embeds := make([]*discordgo.MessageEmbed, 0, 12)
+	for idx := range 12 {
+		embeds = append(embeds, &discordgo.MessageEmbed{Title: fmt.Sprintf("Game %d", idx+1)})
+	}
+	messages := PackEmbeds(embeds...)
+	if len(messages) != 2 || len(messages[0]) != MaxEmbedsPerMessage || len(messages[1]) != 2 || messages[1][1].Title != "Game 12" {
+		t.Fatalf("PackEmbeds(12 embeds) = %d messages, want 10 and 2 embeds", len(messages))
+	}
+
+	long := strings.Repeat("a", 2500)
+	messages = PackEmbeds(&discordgo.MessageEmbed{Description: long}, &discordgo.MessageEmbed{Description: long}, &discordgo.MessageEmbed{Description: long})
+	if len(messages) != 2 || len(messages[0]) != 2 || len(messages[1]) != 1 {
+		t.Fatalf("PackEmbeds(3 x 2500 characters) = %d messages, want 2 and 1 embeds", len(messages))
+	}
+	for idx, message := range messages {
+		if err := CheckEmbedLimits(message...); err != nil {
+			t.Fatalf("message %d exceeds limits: %v", idx, err)
+		}
+	}
+
+	if messages := PackEmbeds(nil); len(messages) != 0 {
+		t.Fatalf("PackEmbeds(nil) = %d messages, want none", len(messages))
+	}
+}
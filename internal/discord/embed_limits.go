@@ -0,0 +1,89 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord rejects messages whose embeds exceed these limits. Lengths are counted in characters, and
+// the total applies to all the embeds of one message together.
+const (
+	MaxEmbedsPerMessage   = 10
+	EmbedTitleLimit       = 256
+	EmbedDescriptionLimit = 4096
+	EmbedFieldCountLimit  = 25
+	EmbedFieldNameLimit   = 256
+	EmbedFieldValueLimit  = 1024
+	EmbedFooterTextLimit  = 2048
+	EmbedAuthorNameLimit  = 256
+	EmbedTotalLimit       = 6000
+)
+
+// EmbedLength is the number of characters of the embed that count towards EmbedTotalLimit.
+func EmbedLength(embed *discordgo.MessageEmbed) int {
+	if embed == nil {
+		return 0
+	}
+	n := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	for _, field := range embed.Fields {
+		if field != nil {
+			n += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+		}
+	}
+	if embed.Footer != nil {
+		n += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		n += utf8.RuneCountInString(embed.Author.Name)
+	}
+	return n
+}
+
+// CheckEmbedLimits reports every Discord limit the embeds of one message exceed, or nil when Discord
+// accepts them.
+func CheckEmbedLimits(embeds ...*discordgo.MessageEmbed) error {
+	var errs []error
+	if len(embeds) > MaxEmbedsPerMessage {
+		errs = append(errs, fmt.Errorf("%d embeds, limit %d", len(embeds), MaxEmbedsPerMessage))
+	}
+	total := 0
+	for idx, embed := range embeds {
+		if embed == nil {
+			continue
+		}
+		check := func(what string, value string, limit int) {
+			if n := utf8.RuneCountInString(value); n > limit {
+				errs = append(errs, fmt.Errorf("embed %d: %s has %d characters, limit %d", idx, what, n, limit))
+			}
+		}
+		check("title", embed.Title, EmbedTitleLimit)
+		check("description", embed.Description, EmbedDescriptionLimit)
+		if embed.Author != nil {
+			check("author name", embed.Author.Name, EmbedAuthorNameLimit)
+		}
+		if embed.Footer != nil {
+			check("footer text", embed.Footer.Text, EmbedFooterTextLimit)
+		}
+		if len(embed.Fields) > EmbedFieldCountLimit {
+			errs = append(errs, fmt.Errorf("embed %d: %d fields, limit %d", idx, len(embed.Fields), EmbedFieldCountLimit))
+		}
+		for fieldIdx, field := range embed.Fields {
+			if field == nil {
+				continue
+			}
+			if field.Name == "" || field.Value == "" {
+				errs = append(errs, fmt.Errorf("embed %d: field %d has an empty name or value", idx, fieldIdx))
+			}
+			check(fmt.Sprintf("field %d name", fieldIdx), field.Name, EmbedFieldNameLimit)
+			check(fmt.Sprintf("field %d value", fieldIdx), field.Value, EmbedFieldValueLimit)
+		}
+		total += EmbedLength(embed)
+	}
+	if total > EmbedTotalLimit {
+		errs = append(errs, fmt.Errorf("embeds have %d characters in total, limit %d", total, EmbedTotalLimit))
+	}
+	return errors.Join(errs...)
+}
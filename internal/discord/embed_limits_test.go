@@ -0,0 +1,46 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCheckEmbedLimits(t *testing.T) {
+	valid := &discordgo.MessageEmbed{
+		Title:  strings.Repeat("é", EmbedTitleLimit),
+		Fields: []*discordgo.MessageEmbedField{{Name: "Build", Value: strings.Repeat("🗡", EmbedFieldValueLimit)}},
+		Footer: &discordgo.MessageEmbedFooter{Text: "League API bot"},
+	}
+	if err := CheckEmbedLimits(valid); err != nil {
+		t.Fatalf("CheckEmbedLimits() at the limits error = %v", err)
+	}
+	if got, want := EmbedLength(valid), EmbedTitleLimit+len("Build")+EmbedFieldValueLimit+len("League API bot"); got != want {
+		t.Fatalf("EmbedLength() = %d, want %d characters", got, want)
+	}
+
+	tooMany := make([]*discordgo.MessageEmbedField, EmbedFieldCountLimit+1)
+	for idx := range tooMany {
+		tooMany[idx] = &discordgo.MessageEmbedField{Name: "n", Value: "v"}
+	}
+	tests := []struct {
+		name   string
+		embeds []*discordgo.MessageEmbed
+		want   string
+	}{
+		{"title", []*discordgo.MessageEmbed{{Title: strings.Repeat("a", EmbedTitleLimit+1)}}, "title has 257 characters"},
+		{"field value", []*discordgo.MessageEmbed{{Fields: []*discordgo.MessageEmbedField{{Name: "n", Value: strings.Repeat("a", EmbedFieldValueLimit+1)}}}}, "field 0 value has 1025 characters"},
+		{"empty field", []*discordgo.MessageEmbed{{Fields: []*discordgo.MessageEmbedField{{Name: "n"}}}}, "field 0 has an empty name or value"},
+		{"field count", []*discordgo.MessageEmbed{{Fields: tooMany}}, "26 fields, limit 25"},
+		{"total", []*discordgo.MessageEmbed{{Description: strings.Repeat("a", 4000)}, {Description: strings.Repeat("a", 2001)}}, "6001 characters in total"},
+		{"embed count", make([]*discordgo.MessageEmbed, MaxEmbedsPerMessage+1), "11 embeds, limit 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckEmbedLimits(tt.embeds...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("CheckEmbedLimits() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
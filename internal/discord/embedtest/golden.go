@@ -0,0 +1,179 @@
+// Package embedtest compares Discord embeds with golden files, so layout changes show up as diffs
+// instead of being caught by eye.
+//
+// Golden checks the embeds against Discord limits and against two files in testdata/golden of the
+// test package: <name>.json, the embeds as Discord receives them, and <name>.txt, a rendering meant
+// for reviewing layout changes. Run go test with -update to rewrite the files after an intended
+// change.
+package embedtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of embed tests")
+
+// cdnVersionPattern matches the Data Dragon version in image URLs, which follows the live patch.
+var cdnVersionPattern = regexp.MustCompile(`/cdn/[^/]+/img/`)
+
+// Golden fails the test when the embeds of one message exceed a Discord limit or differ from the
+// golden files called name.
+func Golden(t testing.TB, name string, embeds ...*discordgo.MessageEmbed) {
+	t.Helper()
+	if err := discord.CheckEmbedLimits(embeds...); err != nil {
+		t.Errorf("embeds %s exceed Discord limits:\n%v", name, err)
+	}
+
+	normalized := Normalize(embeds...)
+	data, err := json.MarshalIndent(normalized, "", "  ")
+	if err != nil {
+		t.Fatalf("encode embeds %s: %v", name, err)
+	}
+	files := map[string][]byte{
+		name + ".json": append(data, '\n'),
+		name + ".txt":  []byte(Render(normalized...)),
+	}
+	dir := filepath.Join("testdata", "golden")
+	for _, file := range []string{name + ".json", name + ".txt"} {
+		path := filepath.Join(dir, file)
+		got := files[file]
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatalf("update golden file: %v", err)
+			}
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatalf("update golden file: %v", err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("read golden file %s: %v (run go test -update to create it)", path, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("embeds differ from %s (run go test -update if the change is intended):\n%s", path, firstDifference(string(want), string(got)))
+		}
+	}
+}
+
+// Normalize returns copies of the embeds without what changes between runs: the timestamp and the
+// Data Dragon version of image URLs.
+func Normalize(embeds ...*discordgo.MessageEmbed) []*discordgo.MessageEmbed {
+	data, err := json.Marshal(embeds)
+	if err != nil {
+		return embeds
+	}
+	data = cdnVersionPattern.ReplaceAll(data, []byte("/cdn/{version}/img/"))
+	var out []*discordgo.MessageEmbed
+	if err := json.Unmarshal(data, &out); err != nil {
+		return embeds
+	}
+	for _, embed := range out {
+		if embed != nil {
+			embed.Timestamp = ""
+		}
+	}
+	return out
+}
+
+// Render writes the embeds as text, field by field, with their size against the Discord limits.
+func Render(embeds ...*discordgo.MessageEmbed) string {
+	var b strings.Builder
+	total := 0
+	for idx, embed := range embeds {
+		if idx > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "=== Embed %d ===\n", idx+1)
+		if embed == nil {
+			b.WriteString("(nil)\n")
+			continue
+		}
+		if embed.Author != nil {
+			writeLine(&b, "Author", embed.Author.Name, embed.Author.IconURL)
+		}
+		writeLine(&b, "Title", embed.Title, embed.URL)
+		if embed.Color != 0 {
+			fmt.Fprintf(&b, "Color: #%06x\n", embed.Color)
+		}
+		if embed.Thumbnail != nil {
+			writeLine(&b, "Thumbnail", embed.Thumbnail.URL, "")
+		}
+		if embed.Description != "" {
+			b.WriteString("Description:\n")
+			writeIndented(&b, embed.Description, "  ")
+		}
+		for _, field := range embed.Fields {
+			if field == nil {
+				continue
+			}
+			inline := ""
+			if field.Inline {
+				inline = " (inline)"
+			}
+			fmt.Fprintf(&b, "Field [%s]%s:\n", field.Name, inline)
+			writeIndented(&b, field.Value, "  ")
+		}
+		if embed.Image != nil {
+			writeLine(&b, "Image", embed.Image.URL, "")
+		}
+		if embed.Footer != nil {
+			writeLine(&b, "Footer", embed.Footer.Text, embed.Footer.IconURL)
+		}
+		length := discord.EmbedLength(embed)
+		total += length
+		fmt.Fprintf(&b, "Size: %d/%d characters, %d/%d fields\n", length, discord.EmbedTotalLimit, len(embed.Fields), discord.EmbedFieldCountLimit)
+	}
+	if len(embeds) > 1 {
+		fmt.Fprintf(&b, "\nMessage size: %d/%d characters, %d/%d embeds\n", total, discord.EmbedTotalLimit, len(embeds), discord.MaxEmbedsPerMessage)
+	}
+	return b.String()
+}
+
+func writeLine(b *strings.Builder, label, value, url string) {
+	if value == "" && url == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s: %s", label, value)
+	if url != "" {
+		fmt.Fprintf(b, " <%s>", url)
+	}
+	b.WriteString("\n")
+}
+
+func writeIndented(b *strings.Builder, text, indent string) {
+	for line := range strings.SplitSeq(text, "\n") {
+		b.WriteString(strings.TrimRight(indent+line, " "))
+		b.WriteString("\n")
+	}
+}
+
+// firstDifference describes the first line where got differs from want.
+func firstDifference(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for idx := range max(len(wantLines), len(gotLines)) {
+		var w, g string
+		if idx < len(wantLines) {
+			w = wantLines[idx]
+		}
+		if idx < len(gotLines) {
+			g = gotLines[idx]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %q\n  got:  %q", idx+1, w, g)
+		}
+	}
+	return "files differ only in line endings"
+}
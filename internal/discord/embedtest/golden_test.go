@@ -0,0 +1,58 @@
+package embedtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestNormalize(t *testing.T) {
+	embed := &discordgo.MessageEmbed{
+		Title:     "Bekko#Ekko",
+		Timestamp: "2026-02-07T12:00:00Z",
+		Thumbnail: &discordgo.MessageEmbedThumbnail{URL: "https://ddragon.leagueoflegends.com/cdn/15.1.1/img/profileicon/29.png"},
+	}
+
+	got := Normalize(embed)
+	if len(got) != 1 || got[0].Timestamp != "" || got[0].Title != "Bekko#Ekko" {
+		t.Fatalf("Normalize() = %+v", got)
+	}
+	if want := "https://ddragon.leagueoflegends.com/cdn/{version}/img/profileicon/29.png"; got[0].Thumbnail.URL != want {
+		t.Fatalf("thumbnail url = %q, want %q", got[0].Thumbnail.URL, want)
+	}
+	if embed.Timestamp == "" {
+		t.Fatalf("Normalize() changed the original embed")
+	}
+}
+
+func TestRender(t *testing.T) {
+	got := Render(
+		&discordgo.MessageEmbed{
+			Title:  "Leaderboard",
+			Color:  0xf4b38b,
+			Fields: []*discordgo.MessageEmbedField{{Name: "Nick", Value: "Bekko#Ekko\nAhri#BR1", Inline: true}},
+		},
+		&discordgo.MessageEmbed{Description: "Page 2"},
+	)
+	want := strings.Join([]string{
+		"=== Embed 1 ===",
+		"Title: Leaderboard",
+		"Color: #f4b38b",
+		"Field [Nick] (inline):",
+		"  Bekko#Ekko",
+		"  Ahri#BR1",
+		"Size: 34/6000 characters, 1/25 fields",
+		"",
+		"=== Embed 2 ===",
+		"Description:",
+		"  Page 2",
+		"Size: 6/6000 characters, 0/25 fields",
+		"",
+		"Message size: 40/6000 characters, 2/10 embeds",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("Render() =\n%s\nwant:\n%s", got, want)
+	}
+}
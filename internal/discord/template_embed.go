@@ -12,8 +12,16 @@ import (
 )
 
 type DeferredEmbedExecutor func(ctx context.Context) ([]*discordgo.MessageEmbed, error)
+type DeferredResponseExecutor func(ctx context.Context) (DeferredResponse, error)
 type DeferredErrorMapper func(err error) string
 
+// DeferredResponse is the reply of a deferred command, with optional file attachments and components.
+type DeferredResponse struct {
+	Embeds     []*discordgo.MessageEmbed
+	Files      []*discordgo.File
+	Components []discordgo.MessageComponent
+}
+
 const (
 	defaultInteractionAckWindow                     = 3 * time.Second
 	defaultDeferSafetyMargin                        = 500 * time.Millisecond
@@ -39,15 +47,12 @@ var (
 )
 
 type commandEmbedsResult struct {
-	embeds []*discordgo.MessageEmbed
-	err    error
+	response DeferredResponse
+	err      error
 }
 
 func RespondWithEmbed(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) error {
-	return interactionRespond(s, i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}},
-	})
+	return respondWithEmbeds(s, i, DeferredResponse{Embeds: []*discordgo.MessageEmbed{embed}})
 }
 
 func RespondWithError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
@@ -57,6 +62,18 @@ func RespondWithError(s *discordgo.Session, i *discordgo.InteractionCreate, mess
 }
 
 func RunDeferredEmbedCommand(s *discordgo.Session, i *discordgo.InteractionCreate, timeout time.Duration, exec DeferredEmbedExecutor, mapErr DeferredErrorMapper) error {
+	var run DeferredResponseExecutor
+	if exec != nil {
+		run = func(ctx context.Context) (DeferredResponse, error) {
+			embeds, err := exec(ctx)
+			return DeferredResponse{Embeds: embeds}, err
+		}
+	}
+	return RunDeferredCommand(s, i, timeout, run, mapErr)
+}
+
+// RunDeferredCommand works like RunDeferredEmbedCommand but lets the executor attach files.
+func RunDeferredCommand(s *discordgo.Session, i *discordgo.InteractionCreate, timeout time.Duration, exec DeferredResponseExecutor, mapErr DeferredErrorMapper) error {
 	if s == nil {
 		return fmt.Errorf("discord session is required")
 	}
@@ -76,8 +93,8 @@ func RunDeferredEmbedCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 
 	resultCh := make(chan commandEmbedsResult, 1)
 	go func() {
-		embeds, err := exec(ctx)
-		resultCh <- commandEmbedsResult{embeds: embeds, err: err}
+		response, err := exec(ctx)
+		resultCh <- commandEmbedsResult{response: response, err: err}
 	}()
 
 	trigger := deferTriggerDelay()
@@ -119,25 +136,64 @@ func DeferInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) erro
 }
 
 func EditDeferredEmbeds(s *discordgo.Session, i *discordgo.InteractionCreate, embeds []*discordgo.MessageEmbed) error {
-	_, err := interactionResponseEdit(s, i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
-	return err
+	return editDeferredResponse(s, i, DeferredResponse{Embeds: embeds})
 }
 
-func respondWithEmbeds(s *discordgo.Session, i *discordgo.InteractionCreate, embeds []*discordgo.MessageEmbed) error {
-	return interactionRespond(s, i.Interaction, &discordgo.InteractionResponse{
+func editDeferredResponse(s *discordgo.Session, i *discordgo.InteractionCreate, response DeferredResponse) error {
+	embeds, overflow := fitResponseEmbeds(response.Embeds)
+	edit := &discordgo.WebhookEdit{Embeds: &embeds, Files: response.Files}
+	// An empty slice, unlike nil, removes the buttons of the previous response.
+	if response.Components != nil {
+		edit.Components = &response.Components
+	}
+	if _, err := interactionResponseEdit(s, i.Interaction, edit); err != nil {
+		return err
+	}
+	return sendEmbedFollowups(s, i, overflow, 0)
+}
+
+func respondWithEmbeds(s *discordgo.Session, i *discordgo.InteractionCreate, response DeferredResponse) error {
+	embeds, overflow := fitResponseEmbeds(response.Embeds)
+	if err := interactionRespond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{Embeds: embeds},
-	})
+		Data: &discordgo.InteractionResponseData{Embeds: embeds, Files: response.Files, Components: response.Components},
+	}); err != nil {
+		return err
+	}
+	return sendEmbedFollowups(s, i, overflow, 0)
 }
 
 func respondWithEmbedsEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, embeds []*discordgo.MessageEmbed) error {
-	return interactionRespond(s, i.Interaction, &discordgo.InteractionResponse{
+	embeds, overflow := fitResponseEmbeds(embeds)
+	if err := interactionRespond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: embeds,
 			Flags:  discordgo.MessageFlagsEphemeral,
 		},
-	})
+	}); err != nil {
+		return err
+	}
+	return sendEmbedFollowups(s, i, overflow, discordgo.MessageFlagsEphemeral)
+}
+
+// fitResponseEmbeds packs the embeds of a response into messages within Discord limits. The first
+// message answers the interaction and the others follow it.
+func fitResponseEmbeds(embeds []*discordgo.MessageEmbed) ([]*discordgo.MessageEmbed, [][]*discordgo.MessageEmbed) {
+	messages := PackEmbeds(embeds...)
+	if len(messages) == 0 {
+		return embeds, nil
+	}
+	return messages[0], messages[1:]
+}
+
+func sendEmbedFollowups(s *discordgo.Session, i *discordgo.InteractionCreate, messages [][]*discordgo.MessageEmbed, flags discordgo.MessageFlags) error {
+	for _, embeds := range messages {
+		if _, err := followupMessageCreate(s, i.Interaction, false, &discordgo.WebhookParams{Embeds: embeds, Flags: flags}); err != nil {
+			return fmt.Errorf("send overflow embeds: %w", err)
+		}
+	}
+	return nil
 }
 
 func respondDeferredErrorEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, errEmbed *discordgo.MessageEmbed) error {
@@ -156,8 +212,7 @@ func respondDeferredErrorEphemeral(s *discordgo.Session, i *discordgo.Interactio
 
 func respondCommandResult(s *discordgo.Session, i *discordgo.InteractionCreate, result commandEmbedsResult, mapErr DeferredErrorMapper, deferred bool) error {
 	if result.err != nil {
-		command := i.ApplicationCommandData().Name
-		slog.Error("Deferred command execution failed", "command", command, "error", result.err)
+		slog.Error("Deferred command execution failed", "command", interactionName(i), "error", result.err)
 
 		title := "Oops, something went wrong!"
 		message := "Could not connect to Riot servers.\nPlease try again later."
@@ -172,9 +227,21 @@ func respondCommandResult(s *discordgo.Session, i *discordgo.InteractionCreate,
 	}
 
 	if deferred {
-		return EditDeferredEmbeds(s, i, result.embeds)
+		return editDeferredResponse(s, i, result.response)
+	}
+	return respondWithEmbeds(s, i, result.response)
+}
+
+// interactionName is the command name, or the custom ID of a modal submit answered through RunDeferredCommand.
+func interactionName(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionModalSubmit:
+		return i.ModalSubmitData().CustomID
+	case discordgo.InteractionMessageComponent:
+		return i.MessageComponentData().CustomID
+	default:
+		return i.ApplicationCommandData().Name
 	}
-	return respondWithEmbeds(s, i, result.embeds)
 }
 
 func deferTriggerDelay() time.Duration {
@@ -206,7 +273,7 @@ func TemplateError(message, title string) *discordgo.MessageEmbed {
 			Name:    title,
 			IconURL: cdn.ProfileIconURL(6922),
 		},
-		Description: message,
+		Description: TruncateText(message, EmbedDescriptionLimit),
 		Color:       0xFF0000,
 		URL:         "https://github.com/bingbr/League-API-bot",
 	}
@@ -99,6 +99,29 @@ func TestRunDeferredEmbedCommand_SlowSuccessDefersAndEdits(t *testing.T) {
 	})
 }
 
+func TestRunDeferredEmbedCommand_OverflowingEmbedsFollowUp(t *testing.T) {
+	recorder := withDeferredCommandTestStubs(t)
+	setDeferredCommandTiming(t, 150*time.Millisecond, 50*time.Millisecond)
+
+	err := RunDeferredEmbedCommand(testSession(), testInteraction(), 300*time.Millisecond, func(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
+		embeds := make([]*discordgo.MessageEmbed, 12)
+		for idx := range embeds {
+			embeds[idx] = &discordgo.MessageEmbed{Title: "page"}
+		}
+		return embeds, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("RunDeferredEmbedCommand() error = %v", err)
+	}
+
+	if len(recorder.respondCalls) != 1 || len(recorder.respondCalls[0].resp.Data.Embeds) != MaxEmbedsPerMessage {
+		t.Fatalf("respondCalls = %+v, want one response with %d embeds", recorder.respondCalls, MaxEmbedsPerMessage)
+	}
+	if len(recorder.followupCalls) != 1 || len(recorder.followupCalls[0].data.Embeds) != 2 {
+		t.Fatalf("followupCalls = %+v, want one follow-up with the 2 remaining embeds", recorder.followupCalls)
+	}
+}
+
 func TestRunDeferredEmbedCommand_FastErrorRespondsImmediately(t *testing.T) {
 	recorder := withDeferredCommandTestStubs(t)
 	setDeferredCommandTiming(t, 150*time.Millisecond, 50*time.Millisecond)
@@ -0,0 +1,112 @@
+package httprecord
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSecret = "RGAPI-secret"
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set("X-Riot-Token", testSecret)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do(%s) error = %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body error = %v", err)
+	}
+	return resp, body
+}
+
+func TestRecorderRecordsAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		w.Header().Set("X-App-Rate-Limit", "20:1,100:120")
+		w.Header().Set("X-Riot-Edge-Trace-Id", "changes-every-time")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = io.WriteString(w, `{"path":"`+r.URL.Path+`","token":"`+r.Header.Get("X-Riot-Token")+`"}`)
+	}))
+	dir := t.TempDir()
+	url := server.URL + "/lol/match/v5/matches/by-puuid/p%201/ids?count=2&api_key=" + testSecret
+
+	recorder := New(dir, ModeRecord, WithSecrets(testSecret))
+	resp, body := get(t, recorder.Client(), url)
+	if resp.StatusCode != http.StatusTeapot || !strings.Contains(string(body), testSecret) {
+		t.Fatalf("recorded response = %d %s, want the live response", resp.StatusCode, body)
+	}
+	server.Close()
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*", "lol", "match", "v5", "matches", "by-puuid", "p_1", "ids_*.json"))
+	if len(matches) != 1 {
+		t.Fatalf("fixture files = %v, want one per request", matches)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("read fixture error = %v", err)
+	}
+	if bytes.Contains(data, []byte(testSecret)) || bytes.Contains(data, []byte("api_key")) || bytes.Contains(data, []byte("Trace")) {
+		t.Fatalf("fixture leaks the secret or volatile headers:\n%s", data)
+	}
+
+	replayer := New(dir, ModeReplay, WithSecrets(testSecret))
+	resp, body = get(t, replayer.Client(), url)
+	var payload map[string]string
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("replayed body %s is not JSON: %v", body, err)
+	}
+	if resp.StatusCode != http.StatusTeapot || payload["token"] != redacted || payload["path"] != "/lol/match/v5/matches/by-puuid/p 1/ids" {
+		t.Fatalf("replayed response = %d %v", resp.StatusCode, payload)
+	}
+	if got := resp.Header.Get("X-App-Rate-Limit"); got != "20:1,100:120" {
+		t.Fatalf("replayed X-App-Rate-Limit = %q", got)
+	}
+}
+
+func TestRecorderReplaysBinaryBodies(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nimage")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(png)
+	}))
+	dir := t.TempDir()
+
+	get(t, New(dir, ModeRecord).Client(), server.URL+"/cdn/img/champion/Ekko.png")
+	server.Close()
+	_, body := get(t, New(dir, ModeReplay).Client(), server.URL+"/cdn/img/champion/Ekko.png")
+	if !bytes.Equal(body, png) {
+		t.Fatalf("replayed body = %q, want %q", body, png)
+	}
+}
+
+func TestRecorderReplayRequiresFixture(t *testing.T) {
+	client := New(t.TempDir(), ModeReplay).Client()
+	_, err := client.Get("https://br1.api.riotgames.com/lol/status/v4/platform-data")
+	if err == nil || !strings.Contains(err.Error(), "ModeRecord") {
+		t.Fatalf("Get() without fixture error = %v, want a hint to record", err)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(EnvVar, "record")
+	if ModeFromEnv() != ModeRecord {
+		t.Fatalf("ModeFromEnv() with %s=record is not ModeRecord", EnvVar)
+	}
+	t.Setenv(EnvVar, "")
+	if ModeFromEnv() != ModeReplay {
+		t.Fatalf("ModeFromEnv() without %s is not ModeReplay", EnvVar)
+	}
+}
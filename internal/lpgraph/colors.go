@@ -0,0 +1,60 @@
+package lpgraph
+
+import (
+	"image/color"
+	"image/png"
+	"sync"
+
+	"github.com/bingbr/League-API-bot/data"
+)
+
+var fallbackTierColor = color.RGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xFF}
+
+// tierColors averages the visible pixels of each rank icon in data/ranks,
+// so the chart bands follow the same palette as the emojis.
+var tierColors = sync.OnceValue(func() map[string]color.RGBA {
+	out := make(map[string]color.RGBA, len(tierBands))
+	for _, band := range tierBands {
+		out[band.Tier] = averageIconColor(band.Tier)
+	}
+	return out
+})
+
+func tierColor(tier string) color.RGBA {
+	if c, ok := tierColors()[tier]; ok {
+		return c
+	}
+	return fallbackTierColor
+}
+
+func averageIconColor(tier string) color.RGBA {
+	file, err := data.RankIconsFS.Open("ranks/" + tier + ".png")
+	if err != nil {
+		return fallbackTierColor
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		return fallbackTierColor
+	}
+
+	var r, g, b, n uint64
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pr, pg, pb, pa := img.At(x, y).RGBA()
+			if pa < 0xC000 {
+				continue
+			}
+			r += uint64(pr >> 8)
+			g += uint64(pg >> 8)
+			b += uint64(pb >> 8)
+			n++
+		}
+	}
+	if n == 0 {
+		return fallbackTierColor
+	}
+	return color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 0xFF}
+}
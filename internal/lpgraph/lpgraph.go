@@ -0,0 +1,273 @@
+// Package lpgraph renders LP progression charts as PNG images using only the standard library.
+package lpgraph
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"slices"
+	"time"
+)
+
+const (
+	defaultWidth  = 800
+	defaultHeight = 400
+	marginLeft    = 96
+	marginRight   = 24
+	marginTop     = 20
+	marginBottom  = 36
+	labelScale    = 2
+	divisionSpan  = 100
+	minValueSpan  = 200
+	valuePadding  = 50
+)
+
+var ErrNoPoints = errors.New("no points to plot")
+
+var (
+	backgroundColor = color.RGBA{R: 0x2B, G: 0x2D, B: 0x31, A: 0xFF}
+	axisColor       = color.RGBA{R: 0x80, G: 0x84, B: 0x8E, A: 0xFF}
+	lineColor       = color.RGBA{R: 0xF2, G: 0xF3, B: 0xF5, A: 0xFF}
+)
+
+// Point is one LP sample on the flattened ladder scale used by riot.RankValue:
+// 400 per tier, 100 per division, with every apex tier starting at the Master floor.
+type Point struct {
+	At    time.Time
+	Value int
+}
+
+type Options struct {
+	Width  int
+	Height int
+	From   time.Time
+	To     time.Time
+}
+
+type tierBand struct {
+	Tier  string
+	Label string
+	Floor int
+}
+
+// tierBands lists the ladder from the bottom up. Apex tiers share one open-ended band.
+var tierBands = []tierBand{
+	{Tier: "iron", Label: "IRON", Floor: 0},
+	{Tier: "bronze", Label: "BRONZE", Floor: 400},
+	{Tier: "silver", Label: "SILVER", Floor: 800},
+	{Tier: "gold", Label: "GOLD", Floor: 1200},
+	{Tier: "platinum", Label: "PLAT", Floor: 1600},
+	{Tier: "emerald", Label: "EMERALD", Floor: 2000},
+	{Tier: "diamond", Label: "DIAMOND", Floor: 2400},
+	{Tier: "master", Label: "MASTER+", Floor: 2800},
+}
+
+type chart struct {
+	img         *image.RGBA
+	left, right int
+	top, bottom int
+	low, high   int
+	from, to    time.Time
+}
+
+// Render draws points as a line chart over tier bands and returns the PNG encoding.
+func Render(points []Point, opts Options) ([]byte, error) {
+	if len(points) == 0 {
+		return nil, ErrNoPoints
+	}
+	points = slices.Clone(points)
+	slices.SortFunc(points, func(a, b Point) int { return a.At.Compare(b.At) })
+
+	width, height := opts.Width, opts.Height
+	if width <= 0 {
+		width = defaultWidth
+	}
+	if height <= 0 {
+		height = defaultHeight
+	}
+	from, to := opts.From, opts.To
+	if from.IsZero() {
+		from = points[0].At
+	}
+	if to.IsZero() || !to.After(from) {
+		to = points[len(points)-1].At
+		if !to.After(from) {
+			to = from.Add(time.Hour)
+		}
+	}
+
+	low, high := valueRange(points)
+	c := &chart{
+		img:    image.NewRGBA(image.Rect(0, 0, width, height)),
+		left:   marginLeft,
+		right:  width - marginRight,
+		top:    marginTop,
+		bottom: height - marginBottom,
+		low:    low,
+		high:   high,
+		from:   from,
+		to:     to,
+	}
+	fillRect(c.img, 0, 0, width, height, backgroundColor)
+	c.drawBands()
+	c.drawAxes()
+	c.drawSeries(points)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("encode lp graph: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func valueRange(points []Point) (int, int) {
+	low, high := points[0].Value, points[0].Value
+	for _, point := range points[1:] {
+		low, high = min(low, point.Value), max(high, point.Value)
+	}
+	low, high = low-valuePadding, high+valuePadding
+	if span := high - low; span < minValueSpan {
+		mid := (low + high) / 2
+		low, high = mid-minValueSpan/2, mid+minValueSpan/2
+	}
+	if low < 0 {
+		high -= low
+		low = 0
+	}
+	return low, high
+}
+
+func (c *chart) y(value int) int {
+	ratio := float64(c.high-value) / float64(c.high-c.low)
+	return c.top + int(ratio*float64(c.bottom-c.top))
+}
+
+func (c *chart) x(at time.Time) int {
+	ratio := float64(at.Sub(c.from)) / float64(c.to.Sub(c.from))
+	ratio = min(max(ratio, 0), 1)
+	return c.left + int(ratio*float64(c.right-c.left))
+}
+
+func (c *chart) drawBands() {
+	for idx, band := range tierBands {
+		ceiling := c.high
+		if idx+1 < len(tierBands) {
+			ceiling = tierBands[idx+1].Floor
+		}
+		bandLow, bandHigh := max(band.Floor, c.low), min(ceiling, c.high)
+		if bandLow >= bandHigh {
+			continue
+		}
+
+		base := tierColor(band.Tier)
+		yTop, yBottom := c.y(bandHigh), c.y(bandLow)
+		fillRect(c.img, c.left, yTop, c.right-c.left, yBottom-yTop, mix(backgroundColor, base, 0.22))
+
+		// Division lines only make sense below the apex band.
+		if idx+1 < len(tierBands) {
+			for value := band.Floor + divisionSpan; value < ceiling; value += divisionSpan {
+				if value > c.low && value < c.high {
+					drawHLine(c.img, c.left, c.right, c.y(value), mix(backgroundColor, base, 0.4))
+				}
+			}
+		}
+		if band.Floor > c.low && band.Floor < c.high {
+			drawHLine(c.img, c.left, c.right, c.y(band.Floor), mix(backgroundColor, base, 0.85))
+		}
+
+		labelY := (yTop+yBottom)/2 - glyphHeight*labelScale/2
+		if yBottom-yTop >= glyphHeight*labelScale+4 {
+			drawText(c.img, c.left-8-textWidth(band.Label, labelScale), labelY, band.Label, labelScale, base)
+		}
+	}
+}
+
+func (c *chart) drawAxes() {
+	drawHLine(c.img, c.left, c.right, c.bottom, axisColor)
+	drawVLine(c.img, c.left, c.top, c.bottom, axisColor)
+
+	fromLabel, toLabel := c.from.Format("01/02"), c.to.Format("01/02")
+	labelY := c.bottom + 10
+	drawText(c.img, c.left, labelY, fromLabel, labelScale, axisColor)
+	drawText(c.img, c.right-textWidth(toLabel, labelScale), labelY, toLabel, labelScale, axisColor)
+}
+
+func (c *chart) drawSeries(points []Point) {
+	for idx := 1; idx < len(points); idx++ {
+		prev, cur := points[idx-1], points[idx]
+		drawLine(c.img, c.x(prev.At), c.y(prev.Value), c.x(cur.At), c.y(cur.Value), lineColor)
+	}
+	for _, point := range points {
+		fillRect(c.img, c.x(point.At)-3, c.y(point.Value)-3, 7, 7, tierColor(bandForValue(point.Value).Tier))
+	}
+}
+
+func bandForValue(value int) tierBand {
+	band := tierBands[0]
+	for _, candidate := range tierBands {
+		if value >= candidate.Floor {
+			band = candidate
+		}
+	}
+	return band
+}
+
+func mix(a, b color.RGBA, t float64) color.RGBA {
+	blend := func(x, y uint8) uint8 { return uint8(float64(x)*(1-t) + float64(y)*t) }
+	return color.RGBA{R: blend(a.R, b.R), G: blend(a.G, b.G), B: blend(a.B, b.B), A: 0xFF}
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	rect := image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+	for py := rect.Min.Y; py < rect.Max.Y; py++ {
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
+
+func drawHLine(img *image.RGBA, x0, x1, y int, c color.RGBA) {
+	fillRect(img, x0, y, x1-x0+1, 1, c)
+}
+
+func drawVLine(img *image.RGBA, x, y0, y1 int, c color.RGBA) {
+	fillRect(img, x, y0, 1, y1-y0+1, c)
+}
+
+// drawLine is a 2px Bresenham line.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		fillRect(img, x0, y0, 2, 2, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,58 @@
+package lpgraph
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	points := []Point{
+		{At: start.Add(48 * time.Hour), Value: 1454},
+		{At: start, Value: 1380},
+		{At: start.Add(96 * time.Hour), Value: 1610},
+	}
+
+	encoded, err := Render(points, Options{Width: 640, Height: 320, From: start, To: start.Add(7 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if got := img.Bounds(); got.Dx() != 640 || got.Dy() != 320 {
+		t.Fatalf("image bounds = %v, want 640x320", got)
+	}
+}
+
+func TestRenderWithoutPoints(t *testing.T) {
+	if _, err := Render(nil, Options{}); !errors.Is(err, ErrNoPoints) {
+		t.Fatalf("Render(nil) error = %v, want %v", err, ErrNoPoints)
+	}
+}
+
+func TestValueRange(t *testing.T) {
+	low, high := valueRange([]Point{{Value: 20}})
+	if low != 0 || high != minValueSpan {
+		t.Fatalf("valueRange() = %d, %d; want 0, %d", low, high, minValueSpan)
+	}
+	low, high = valueRange([]Point{{Value: 1200}, {Value: 1800}})
+	if low != 1150 || high != 1850 {
+		t.Fatalf("valueRange() = %d, %d; want 1150, 1850", low, high)
+	}
+}
+
+func TestTierColorsComeFromRankIcons(t *testing.T) {
+	for _, band := range tierBands {
+		if tierColor(band.Tier) == fallbackTierColor {
+			t.Fatalf("tierColor(%q) fell back to default; rank icon missing or unreadable", band.Tier)
+		}
+	}
+	if bandForValue(2950).Tier != "master" || bandForValue(1454).Tier != "gold" {
+		t.Fatalf("bandForValue() mapped values to the wrong tier")
+	}
+}
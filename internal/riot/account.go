@@ -9,8 +9,6 @@ import (
 	"strings"
 )
 
-const defaultRiotUserAgent = "League-API-bot/2.0"
-
 var validPlatformRegions = map[string]struct{}{
 	"br1": {}, "eun1": {}, "euw1": {}, "jp1": {}, "kr": {},
 	"la1": {}, "la2": {}, "me1": {}, "na1": {}, "oc1": {}, "pbe1": {},
@@ -86,7 +84,7 @@ func PlatformContinent(region string) string {
 	}
 }
 
-func FetchAccountByRiotID(ctx context.Context, platformRegion, gameName, tagLine, apiKey string) (RiotAccount, error) {
+func (c *Client) FetchAccountByRiotID(ctx context.Context, platformRegion, gameName, tagLine string) (RiotAccount, error) {
 	gameName = strings.TrimSpace(gameName)
 	tagLine = strings.TrimPrefix(strings.TrimSpace(tagLine), "#")
 	if gameName == "" || tagLine == "" {
@@ -97,16 +95,33 @@ func FetchAccountByRiotID(ctx context.Context, platformRegion, gameName, tagLine
 		return RiotAccount{}, fmt.Errorf("unsupported platform region %q", platformRegion)
 	}
 
-	endpoint := fmt.Sprintf("https://%s.api.riotgames.com/riot/account/v1/accounts/by-riot-id/%s/%s",
-		continent, url.PathEscape(gameName), url.PathEscape(tagLine))
+	path := fmt.Sprintf("/riot/account/v1/accounts/by-riot-id/%s/%s", url.PathEscape(gameName), url.PathEscape(tagLine))
 	var account RiotAccount
-	if err := doRiotJSONWithRetry(ctx, endpoint, apiKey, &account); err != nil {
+	if err := c.getJSON(ctx, continent, path, &account); err != nil {
 		return RiotAccount{}, fmt.Errorf("fetch account by riot id: %w", err)
 	}
 	return account, nil
 }
 
-func FetchSummonerByPUUID(ctx context.Context, platformRegion, puuid, apiKey string) (SummonerProfile, error) {
+func (c *Client) FetchAccountByPUUID(ctx context.Context, platformRegion, puuid string) (RiotAccount, error) {
+	puuid, err := requireNonEmpty("puuid", puuid)
+	if err != nil {
+		return RiotAccount{}, err
+	}
+	continent := PlatformContinent(NormalizePlatformRegion(platformRegion))
+	if continent == "" {
+		return RiotAccount{}, fmt.Errorf("unsupported platform region %q", platformRegion)
+	}
+
+	path := "/riot/account/v1/accounts/by-puuid/" + url.PathEscape(puuid)
+	var account RiotAccount
+	if err := c.getJSON(ctx, continent, path, &account); err != nil {
+		return RiotAccount{}, fmt.Errorf("fetch account by puuid: %w", err)
+	}
+	return account, nil
+}
+
+func (c *Client) FetchSummonerByPUUID(ctx context.Context, platformRegion, puuid string) (SummonerProfile, error) {
 	region, err := requirePlatformRegion(platformRegion)
 	if err != nil {
 		return SummonerProfile{}, err
@@ -116,15 +131,15 @@ func FetchSummonerByPUUID(ctx context.Context, platformRegion, puuid, apiKey str
 		return SummonerProfile{}, err
 	}
 
-	endpoint := fmt.Sprintf("https://%s.api.riotgames.com/lol/summoner/v4/summoners/by-puuid/%s", region, url.PathEscape(puuid))
+	path := "/lol/summoner/v4/summoners/by-puuid/" + url.PathEscape(puuid)
 	var profile SummonerProfile
-	if err := doRiotJSONWithRetry(ctx, endpoint, apiKey, &profile); err != nil {
+	if err := c.getJSON(ctx, region, path, &profile); err != nil {
 		return SummonerProfile{}, fmt.Errorf("fetch summoner by puuid: %w", err)
 	}
 	return profile, nil
 }
 
-func FetchLeagueEntriesByPUUID(ctx context.Context, platformRegion, puuid, apiKey string) ([]LeagueEntry, error) {
+func (c *Client) FetchLeagueEntriesByPUUID(ctx context.Context, platformRegion, puuid string) ([]LeagueEntry, error) {
 	region, err := requirePlatformRegion(platformRegion)
 	if err != nil {
 		return nil, err
@@ -134,23 +149,22 @@ func FetchLeagueEntriesByPUUID(ctx context.Context, platformRegion, puuid, apiKe
 		return nil, err
 	}
 
-	endpoint := fmt.Sprintf("https://%s.api.riotgames.com/lol/league/v4/entries/by-puuid/%s", region, url.PathEscape(puuid))
+	path := "/lol/league/v4/entries/by-puuid/" + url.PathEscape(puuid)
 	var entries []LeagueEntry
-	if err := doRiotJSONWithRetry(ctx, endpoint, apiKey, &entries); err != nil {
+	if err := c.getJSON(ctx, region, path, &entries); err != nil {
 		return nil, fmt.Errorf("fetch league entries by puuid: %w", err)
 	}
 	return entries, nil
 }
 
-func FetchChampionRotation(ctx context.Context, platformRegion, apiKey string) (ChampionRotation, error) {
+func (c *Client) FetchChampionRotation(ctx context.Context, platformRegion string) (ChampionRotation, error) {
 	region, err := requirePlatformRegion(platformRegion)
 	if err != nil {
 		return ChampionRotation{}, err
 	}
 
-	endpoint := fmt.Sprintf("https://%s.api.riotgames.com/lol/platform/v3/champion-rotations", region)
 	var rotation ChampionRotation
-	if err := doRiotJSONWithRetry(ctx, endpoint, apiKey, &rotation); err != nil {
+	if err := c.getJSON(ctx, region, "/lol/platform/v3/champion-rotations", &rotation); err != nil {
 		return ChampionRotation{}, fmt.Errorf("fetch champion rotations: %w", err)
 	}
 	return rotation, nil
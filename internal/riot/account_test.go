@@ -116,3 +116,28 @@ func TestIsAccountByRiotIDNotFound(t *testing.T) {
 		}
 	}
 }
+
+func TestRankValue(t *testing.T) {
+	tests := []struct {
+		entry LeagueEntry
+		want  int
+		label string
+	}{
+		{entry: LeagueEntry{Tier: "IRON", Rank: "IV", LeaguePoints: 0}, want: 0, label: "Iron IV"},
+		{entry: LeagueEntry{Tier: "GOLD", Rank: "II", LeaguePoints: 54}, want: 1454, label: "Gold II"},
+		{entry: LeagueEntry{Tier: "MASTER", Rank: "I", LeaguePoints: 120}, want: 2920, label: "Master"},
+		{entry: LeagueEntry{Tier: "CHALLENGER", Rank: "I", LeaguePoints: 1389}, want: 4189, label: "Challenger"},
+	}
+	for _, tt := range tests {
+		got, ok := RankValue(tt.entry)
+		if !ok || got != tt.want {
+			t.Fatalf("RankValue(%+v) = %d, %v; want %d, true", tt.entry, got, ok, tt.want)
+		}
+		if label := RankLabel(tt.entry); label != tt.label {
+			t.Fatalf("RankLabel(%+v) = %q, want %q", tt.entry, label, tt.label)
+		}
+	}
+	if _, ok := RankValue(LeagueEntry{}); ok {
+		t.Fatalf("RankValue(unranked) ok = true, want false")
+	}
+}
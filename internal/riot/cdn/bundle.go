@@ -0,0 +1,289 @@
+package cdn
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// A bundle is a copy of the Data Dragon and CommunityDragon files the bot reads, laid out like their
+// URLs: "ddragon/api/versions.json", "ddragon/cdn/<version>/data/en_US/champion.json",
+// "ddragon/cdn/img/perk-images/...", "cdragon/latest/plugins/...". Served by any static file server,
+// a bundle directory is also a mirror for WithDataDragonURL and WithCommunityDragonURL.
+const (
+	bundleDataDragonDir      = "ddragon"
+	bundleCommunityDragonDir = "cdragon"
+	bundleURLPrefix          = "bundle:///"
+	bundleFetchConcurrency   = 10
+)
+
+var bundleDataFiles = []string{"champion.json", "item.json", "summoner.json", "runesReforged.json"}
+
+// Bundle serves the files of a bundle directory or tarball to a Client.
+type Bundle struct {
+	readFile func(name string) ([]byte, error)
+}
+
+// OpenBundle opens a bundle directory, or a .tar, .tar.gz or .tgz bundle which is read into memory.
+func OpenBundle(bundlePath string) (*Bundle, error) {
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("open cdn bundle: %w", err)
+	}
+	var bundle *Bundle
+	if info.IsDir() {
+		fsys := os.DirFS(bundlePath)
+		bundle = &Bundle{readFile: func(name string) ([]byte, error) { return fs.ReadFile(fsys, name) }}
+	} else {
+		files, err := readBundleTarball(bundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("open cdn bundle: %w", err)
+		}
+		bundle = &Bundle{readFile: func(name string) ([]byte, error) {
+			if data, ok := files[name]; ok {
+				return data, nil
+			}
+			return nil, fs.ErrNotExist
+		}}
+	}
+	if _, err := bundle.readFile(bundleDataDragonDir + dataDragonVersionsPath); err != nil {
+		return nil, fmt.Errorf("open cdn bundle: missing %s: %w", bundleDataDragonDir+dataDragonVersionsPath, err)
+	}
+	return bundle, nil
+}
+
+// WithBundle makes the client read every file from the bundle instead of the network.
+func WithBundle(bundle *Bundle) Option {
+	return func(c *Client) {
+		if bundle == nil {
+			return
+		}
+		c.httpClient = &http.Client{Transport: bundle, Timeout: defaultTimeout}
+		c.dataDragonURL = bundleURLPrefix + bundleDataDragonDir
+		c.communityDragonURL = bundleURLPrefix + bundleCommunityDragonDir
+	}
+}
+
+// RoundTrip answers a request for a bundle URL with the file at its path, or 404.
+func (b *Bundle) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := strings.TrimPrefix(path.Clean("/"+req.URL.Path), "/")
+	data, err := b.readFile(name)
+	status := http.StatusOK
+	switch {
+	case errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid):
+		status, data = http.StatusNotFound, []byte("not found in cdn bundle: "+name)
+	case err != nil:
+		return nil, fmt.Errorf("read cdn bundle %s: %w", name, err)
+	}
+	header := http.Header{}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" || status != http.StatusOK {
+		contentType = http.DetectContentType(data)
+	}
+	header.Set("Content-Type", contentType)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+func readBundleTarball(bundlePath string) (files map[string][]byte, err error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	var r io.Reader = f
+	if isGzipBundle(bundlePath) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	files = map[string][]byte{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", header.Name, err)
+		}
+		files[name] = data
+	}
+}
+
+func isGzipBundle(bundlePath string) bool {
+	lower := strings.ToLower(bundlePath)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// BuildBundle downloads the files the bot reads for version (the latest one when empty) and writes
+// them to dst: a .tar.gz, .tgz or .tar file, otherwise a directory. Without images, the bundle holds
+// the game data but emoji sync cannot upload the champion, spell and rune icons. It returns the
+// bundled version.
+func (c *Client) BuildBundle(ctx context.Context, dst, version string, images bool) (string, error) {
+	if version = strings.TrimSpace(version); version == "" {
+		versions, err := c.FetchVersions(ctx)
+		if err != nil {
+			return "", fmt.Errorf("fetch versions: %w", err)
+		}
+		version = versions[0]
+	}
+
+	// The bundle only holds one version, so it is also the only one it lists.
+	versions, err := json.Marshal([]string{version})
+	if err != nil {
+		return "", err
+	}
+	files := map[string][]byte{bundleDataDragonDir + dataDragonVersionsPath: versions}
+	urls := []string{c.communityDragonURL + communityDragonQueuesPath, c.communityDragonURL + communityDragonMapsPath}
+	for _, file := range bundleDataFiles {
+		urls = append(urls, c.dataURL(version, defaultDataLanguage, file))
+	}
+	if images {
+		assets, err := buildNonRankEmojiAssets(ctx, c, version)
+		if err != nil {
+			return "", err
+		}
+		for _, asset := range assets {
+			urls = append(urls, asset.URL)
+		}
+	}
+
+	names := make(map[string]string, len(urls))
+	for _, url := range urls {
+		name, err := c.bundleName(url)
+		if err != nil {
+			return "", err
+		}
+		names[url] = name
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(bundleFetchConcurrency)
+	for url, name := range names {
+		g.Go(func() error {
+			data, err := fetchRaw(gctx, c.httpClient, url)
+			if err != nil {
+				return fmt.Errorf("fetch %s: %w", url, err)
+			}
+			mu.Lock()
+			files[name] = data
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return "", err
+	}
+
+	if err := writeBundle(dst, files); err != nil {
+		return "", fmt.Errorf("write cdn bundle: %w", err)
+	}
+	return version, nil
+}
+
+// bundleName maps a Data Dragon or CommunityDragon URL of the client to its path in a bundle.
+func (c *Client) bundleName(url string) (string, error) {
+	if rest, ok := strings.CutPrefix(url, c.dataDragonURL+"/"); ok {
+		return path.Join(bundleDataDragonDir, rest), nil
+	}
+	if rest, ok := strings.CutPrefix(url, c.communityDragonURL+"/"); ok {
+		return path.Join(bundleCommunityDragonDir, rest), nil
+	}
+	return "", fmt.Errorf("url %s is not served by the cdn", url)
+}
+
+func writeBundle(dst string, files map[string][]byte) error {
+	names := slices.Sorted(maps.Keys(files))
+	if !isGzipBundle(dst) && !strings.HasSuffix(strings.ToLower(dst), ".tar") {
+		for _, name := range names {
+			target := filepath.Join(dst, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, files[name], 0o644); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if isGzipBundle(dst) {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	modTime := time.Now().UTC().Truncate(time.Second)
+	for _, name := range names {
+		data := files[name]
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	}
+	if dir := filepath.Dir(dst); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(dst, buf.Bytes(), 0o644)
+}
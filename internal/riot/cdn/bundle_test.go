@@ -0,0 +1,154 @@
+package cdn
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var bundleTestPNG = []byte("\x89PNG\r\n\x1a\nbundle-test-image")
+
+// newCDNTestServer serves a tiny Data Dragon under /ddragon and CommunityDragon under /cdragon.
+func newCDNTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	files := map[string]string{
+		"/ddragon/api/versions.json":                                                 `["15.1.1","15.0.1"]`,
+		"/ddragon/cdn/15.1.1/data/en_US/champion.json":                               `{"version":"15.1.1","data":{"Ekko":{"id":"Ekko","key":"245","name":"Ekko","image":{"full":"Ekko.png"}}}}`,
+		"/ddragon/cdn/15.1.1/data/en_US/item.json":                                   `{"version":"15.1.1","data":{"1001":{"name":"Boots"}}}`,
+		"/ddragon/cdn/15.1.1/data/en_US/summoner.json":                               `{"version":"15.1.1","data":{"SummonerFlash":{"id":"SummonerFlash","key":"4","name":"Flash","image":{"full":"SummonerFlash.png"}}}}`,
+		"/ddragon/cdn/15.1.1/data/en_US/runesReforged.json":                          `[{"id":8100,"key":"Domination","icon":"perk-images/Styles/7200_Domination.png","name":"Domination","slots":[{"runes":[{"id":8112,"key":"Electrocute","icon":"perk-images/Styles/Domination/Electrocute/Electrocute.png","name":"Electrocute"}]}]}]`,
+		"/ddragon/cdn/15.1.1/img/champion/Ekko.png":                                  string(bundleTestPNG),
+		"/ddragon/cdn/15.1.1/img/spell/SummonerFlash.png":                            string(bundleTestPNG),
+		"/ddragon/cdn/img/perk-images/Styles/7200_Domination.png":                    string(bundleTestPNG),
+		"/ddragon/cdn/img/perk-images/Styles/Domination/Electrocute/Electrocute.png": string(bundleTestPNG),
+		"/cdragon" + communityDragonQueuesPath:                                       `[{"id":420,"name":"5v5 Ranked Solo games","gameSelectCategory":"kPvP"}]`,
+		"/cdragon" + communityDragonMapsPath:                                         `[{"id":11,"name":"Summoner's Rift"}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBuildBundleServesSyncOffline(t *testing.T) {
+	for _, name := range []string{"bundle", "bundle.tar.gz", "bundle.tar"} {
+		t.Run(name, func(t *testing.T) {
+			server := newCDNTestServer(t)
+			mirror := NewClient(WithDataDragonURL(server.URL+"/ddragon/"), WithCommunityDragonURL(server.URL+"/cdragon"))
+			dst := filepath.Join(t.TempDir(), name)
+
+			version, err := mirror.BuildBundle(t.Context(), dst, "", true)
+			if err != nil || version != "15.1.1" {
+				t.Fatalf("BuildBundle() = %q, %v, want the latest version", version, err)
+			}
+			server.Close()
+
+			bundle, err := OpenBundle(dst)
+			if err != nil {
+				t.Fatalf("OpenBundle() error = %v", err)
+			}
+			client := NewClient(WithBundle(bundle))
+			versions, err := client.FetchVersions(t.Context())
+			if err != nil || !slices.Equal(versions, []string{"15.1.1"}) {
+				t.Fatalf("FetchVersions() = %v, %v, want only the bundled version", versions, err)
+			}
+
+			db := &cdnSyncTestDB{}
+			if err := SyncBasicData(t.Context(), client, db, version); err != nil {
+				t.Fatalf("SyncBasicData() error = %v", err)
+			}
+			if db.queues != 1 || db.maps != 1 || db.champions != 1 || db.items != 1 || db.spells != 1 || db.runeTrees != 1 {
+				t.Fatalf("synced = %+v, want one of each", *db)
+			}
+
+			assets, err := buildNonRankEmojiAssets(t.Context(), client, version)
+			if err != nil || len(assets) != 4 {
+				t.Fatalf("buildNonRankEmojiAssets() = %d assets, %v, want 4", len(assets), err)
+			}
+			wantHash := sha256.Sum256(bundleTestPNG)
+			for _, asset := range assets {
+				img, err := fetchEmojiImage(t.Context(), client.httpClient, asset.URL, EmojiEntry{})
+				if err != nil || img.ContentHash != hex.EncodeToString(wantHash[:]) || !strings.HasPrefix(img.DataURI, "data:image/png;base64,") {
+					t.Fatalf("fetchEmojiImage(%s) = %+v, %v", asset.URL, img, err)
+				}
+			}
+			if _, err := client.FetchChampions(t.Context(), "14.1.1", defaultDataLanguage); err == nil || !strings.Contains(err.Error(), "status 404") {
+				t.Fatalf("FetchChampions() of a version missing from the bundle error = %v, want 404", err)
+			}
+		})
+	}
+}
+
+func TestBuildBundleWithoutImages(t *testing.T) {
+	server := newCDNTestServer(t)
+	mirror := NewClient(WithDataDragonURL(server.URL+"/ddragon"), WithCommunityDragonURL(server.URL+"/cdragon"))
+	dst := filepath.Join(t.TempDir(), "bundle")
+
+	if _, err := mirror.BuildBundle(t.Context(), dst, "15.1.1", false); err != nil {
+		t.Fatalf("BuildBundle() error = %v", err)
+	}
+	bundle, err := OpenBundle(dst)
+	if err != nil {
+		t.Fatalf("OpenBundle() error = %v", err)
+	}
+	client := NewClient(WithBundle(bundle))
+	if _, err := fetchEmojiImage(t.Context(), client.httpClient, client.dataDragonURL+"/cdn/15.1.1/img/champion/Ekko.png", EmojiEntry{}); err == nil {
+		t.Fatalf("fetchEmojiImage() from a bundle without images error = nil")
+	}
+}
+
+func TestOpenBundleRequiresVersions(t *testing.T) {
+	if _, err := OpenBundle(t.TempDir()); err == nil || !strings.Contains(err.Error(), "versions.json") {
+		t.Fatalf("OpenBundle() of an empty directory error = %v, want missing versions.json", err)
+	}
+	if _, err := OpenBundle(filepath.Join(t.TempDir(), "missing.tar.gz")); err == nil {
+		t.Fatalf("OpenBundle() of a missing file error = nil")
+	}
+}
+
+type cdnSyncTestDB struct {
+	queues, maps, champions, items, spells, runeTrees int
+}
+
+func (d *cdnSyncTestDB) UpsertQueues(_ context.Context, queues []Queue, _ time.Time) error {
+	d.queues = len(queues)
+	return nil
+}
+
+func (d *cdnSyncTestDB) UpsertMaps(_ context.Context, maps []GameMap, _ time.Time) error {
+	d.maps = len(maps)
+	return nil
+}
+
+func (d *cdnSyncTestDB) UpsertChampions(_ context.Context, _ string, champs map[string]Champion, _ time.Time) error {
+	d.champions = len(champs)
+	return nil
+}
+
+func (d *cdnSyncTestDB) UpsertItems(_ context.Context, _ string, items map[string]Item, _ time.Time) error {
+	d.items = len(items)
+	return nil
+}
+
+func (d *cdnSyncTestDB) UpsertSummonerSpells(_ context.Context, _ string, spells map[string]SummonerSpell, _ time.Time) error {
+	d.spells = len(spells)
+	return nil
+}
+
+func (d *cdnSyncTestDB) UpsertRunes(_ context.Context, trees []RuneTree, _ time.Time) error {
+	d.runeTrees = len(trees)
+	return nil
+}
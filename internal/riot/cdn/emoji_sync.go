@@ -22,7 +22,6 @@ import (
 const (
 	emojiLocale           = "en_US"
 	maxEmojiImageBytes    = 256 * 1024
-	runeImageBaseURL      = "https://ddragon.leagueoflegends.com/cdn/img"
 	localRankSourcePrefix = "local-rank://"
 	emojiSyncConcurrency  = 20 // Limit concurrent Discord interactions
 	emojiMaxRetries       = 2
@@ -94,13 +93,16 @@ func (a emojiAsset) manifestKey() string {
 	return fmt.Sprintf("%s:%d", a.Kind, a.ID)
 }
 
-func StartApplicationEmojiSync(ctx context.Context, session *discordgo.Session, version string, syncNonRank bool, db DiscordIconDB, logger *slog.Logger) (<-chan error, error) {
+func StartApplicationEmojiSync(ctx context.Context, session *discordgo.Session, client *Client, version string, syncNonRank bool, db DiscordIconDB, logger *slog.Logger) (<-chan error, error) {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	if session == nil {
 		return nil, fmt.Errorf("discord session is required")
 	}
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
 	appID, err := resolveApplicationID(session)
 	if err != nil {
 		return nil, fmt.Errorf("fetch discord app identity: %w", err)
@@ -112,7 +114,6 @@ func StartApplicationEmojiSync(ctx context.Context, session *discordgo.Session,
 		return nil, fmt.Errorf("list application emojis: %w", err)
 	}
 	idx := newEmojiIndex(existingEmojis)
-	client := NewClient()
 	fetchedAt := time.Now().UTC()
 	L.Update(version, fetchedAt)
 
@@ -634,17 +635,17 @@ func buildNonRankEmojiAssets(ctx context.Context, client *Client, ver string) ([
 	assets := make([]emojiAsset, 0, 300)
 	for _, c := range bundle.Champions.Data {
 		id, _ := strconv.Atoi(c.Key)
-		assets = append(assets, emojiAsset{emojiKindChampion, id, "", c.ID, fmt.Sprintf("%s/%s/img/champion/%s", BaseURL, ver, c.Image.Full)})
+		assets = append(assets, emojiAsset{emojiKindChampion, id, "", c.ID, fmt.Sprintf("%s/cdn/%s/img/champion/%s", client.dataDragonURL, ver, c.Image.Full)})
 	}
 	for _, s := range bundle.SummonerSpells.Data {
 		id, _ := strconv.Atoi(s.Key)
-		assets = append(assets, emojiAsset{emojiKindSummonerSpell, id, "", s.ID, fmt.Sprintf("%s/%s/img/spell/%s", BaseURL, ver, s.Image.Full)})
+		assets = append(assets, emojiAsset{emojiKindSummonerSpell, id, "", s.ID, fmt.Sprintf("%s/cdn/%s/img/spell/%s", client.dataDragonURL, ver, s.Image.Full)})
 	}
 	for _, t := range bundle.Runes {
-		assets = append(assets, emojiAsset{emojiKindRuneTree, t.ID, "", t.Key, fmt.Sprintf("%s/%s", runeImageBaseURL, t.Icon)})
+		assets = append(assets, emojiAsset{emojiKindRuneTree, t.ID, "", t.Key, fmt.Sprintf("%s/cdn/img/%s", client.dataDragonURL, t.Icon)})
 		for _, s := range t.Slots {
 			for _, r := range s.Runes {
-				assets = append(assets, emojiAsset{emojiKindRune, r.ID, "", r.Key, fmt.Sprintf("%s/%s", runeImageBaseURL, r.Icon)})
+				assets = append(assets, emojiAsset{emojiKindRune, r.ID, "", r.Key, fmt.Sprintf("%s/cdn/img/%s", client.dataDragonURL, r.Icon)})
 			}
 		}
 	}
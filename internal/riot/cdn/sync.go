@@ -17,27 +17,73 @@ import (
 const (
 	defaultTimeout = 20 * time.Second
 
-	BaseURL         = "https://ddragon.leagueoflegends.com/cdn"
+	DefaultDataDragonURL      = "https://ddragon.leagueoflegends.com"
+	DefaultCommunityDragonURL = "https://raw.communitydragon.org"
+
+	// BaseURL is the public Data Dragon CDN. Image URLs sent to Discord always point here, since
+	// Discord cannot reach a mirror or bundle.
+	BaseURL         = DefaultDataDragonURL + "/cdn"
 	DefaultVersion  = "latest"
 	DefaultInterval = 24 * time.Hour
 
 	versionFetchTimeout = 10 * time.Second
 
-	communityDragonQueuesURL = "https://raw.communitydragon.org/latest/plugins/rcp-be-lol-game-data/global/default/v1/queues.json"
-	communityDragonMapsURL   = "https://raw.communitydragon.org/latest/plugins/rcp-be-lol-game-data/global/default/v1/maps.json"
-	defaultDataLanguage      = "en_US"
+	communityDragonQueuesPath = "/latest/plugins/rcp-be-lol-game-data/global/default/v1/queues.json"
+	communityDragonMapsPath   = "/latest/plugins/rcp-be-lol-game-data/global/default/v1/maps.json"
+	dataDragonVersionsPath    = "/api/versions.json"
+	defaultDataLanguage       = "en_US"
 )
 
+// Client fetches game data from Data Dragon and CommunityDragon, or from a mirror or bundle of them.
 type Client struct {
-	httpClient *http.Client
+	httpClient         *http.Client
+	dataDragonURL      string
+	communityDragonURL string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for every request.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
 }
 
-func NewClient() *Client {
-	return &Client{httpClient: &http.Client{Timeout: defaultTimeout}}
+// WithDataDragonURL fetches Data Dragon files from a mirror of https://ddragon.leagueoflegends.com.
+func WithDataDragonURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/"); baseURL != "" {
+			c.dataDragonURL = baseURL
+		}
+	}
+}
+
+// WithCommunityDragonURL fetches CommunityDragon files from a mirror of https://raw.communitydragon.org.
+func WithCommunityDragonURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/"); baseURL != "" {
+			c.communityDragonURL = baseURL
+		}
+	}
+}
+
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient:         &http.Client{Timeout: defaultTimeout},
+		dataDragonURL:      DefaultDataDragonURL,
+		communityDragonURL: DefaultCommunityDragonURL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *Client) FetchVersions(ctx context.Context) ([]string, error) {
-	v, err := fetch[[]string](ctx, c.httpClient, "https://ddragon.leagueoflegends.com/api/versions.json")
+	v, err := fetch[[]string](ctx, c.httpClient, c.dataDragonURL+dataDragonVersionsPath)
 	if err == nil && len(v) == 0 {
 		return nil, fmt.Errorf("no versions found")
 	}
@@ -45,41 +91,52 @@ func (c *Client) FetchVersions(ctx context.Context) ([]string, error) {
 }
 
 func (c *Client) FetchQueues(ctx context.Context) ([]Queue, error) {
-	return fetch[[]Queue](ctx, c.httpClient, communityDragonQueuesURL)
+	return fetch[[]Queue](ctx, c.httpClient, c.communityDragonURL+communityDragonQueuesPath)
 }
 
 func (c *Client) FetchMaps(ctx context.Context) ([]GameMap, error) {
-	return fetch[[]GameMap](ctx, c.httpClient, communityDragonMapsURL)
+	return fetch[[]GameMap](ctx, c.httpClient, c.communityDragonURL+communityDragonMapsPath)
 }
 
-func (c *Client) FetchChampions(ctx context.Context, cdn, ver, loc string) (ChampionList, error) {
-	return fetch[ChampionList](ctx, c.httpClient, ddragonURL(cdn, ver, loc, "champion.json"))
+func (c *Client) FetchChampions(ctx context.Context, ver, loc string) (ChampionList, error) {
+	return fetch[ChampionList](ctx, c.httpClient, c.dataURL(ver, loc, "champion.json"))
 }
 
-func (c *Client) FetchItems(ctx context.Context, cdn, ver, loc string) (ItemList, error) {
-	return fetch[ItemList](ctx, c.httpClient, ddragonURL(cdn, ver, loc, "item.json"))
+func (c *Client) FetchItems(ctx context.Context, ver, loc string) (ItemList, error) {
+	return fetch[ItemList](ctx, c.httpClient, c.dataURL(ver, loc, "item.json"))
 }
 
-func (c *Client) FetchSummonerSpells(ctx context.Context, cdn, ver, loc string) (SummonerSpellList, error) {
-	return fetch[SummonerSpellList](ctx, c.httpClient, ddragonURL(cdn, ver, loc, "summoner.json"))
+func (c *Client) FetchSummonerSpells(ctx context.Context, ver, loc string) (SummonerSpellList, error) {
+	return fetch[SummonerSpellList](ctx, c.httpClient, c.dataURL(ver, loc, "summoner.json"))
 }
 
-func (c *Client) FetchRunes(ctx context.Context, cdn, ver, loc string) ([]RuneTree, error) {
-	return fetch[[]RuneTree](ctx, c.httpClient, ddragonURL(cdn, ver, loc, "runesReforged.json"))
+func (c *Client) FetchRunes(ctx context.Context, ver, loc string) ([]RuneTree, error) {
+	return fetch[[]RuneTree](ctx, c.httpClient, c.dataURL(ver, loc, "runesReforged.json"))
 }
 
-func ddragonURL(cdn, ver, loc, file string) string {
-	return fmt.Sprintf("%s/%s/data/%s/%s", cdn, ver, loc, file)
+func (c *Client) dataURL(ver, loc, file string) string {
+	return fmt.Sprintf("%s/cdn/%s/data/%s/%s", c.dataDragonURL, ver, loc, file)
 }
 
 func fetch[T any](ctx context.Context, client *http.Client, url string) (target T, err error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	body, err := fetchRaw(ctx, client, url)
 	if err != nil {
 		return target, err
 	}
+	if err = json.Unmarshal(body, &target); err != nil {
+		return target, fmt.Errorf("decode %s: %w", url, err)
+	}
+	return target, nil
+}
+
+func fetchRaw(ctx context.Context, client *http.Client, url string) (body []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return target, err
+		return nil, err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
@@ -88,12 +145,12 @@ func fetch[T any](ctx context.Context, client *http.Client, url string) (target
 	}()
 	if resp.StatusCode >= http.StatusMultipleChoices {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return target, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
 	}
-	if err = json.NewDecoder(resp.Body).Decode(&target); err != nil {
-		return target, fmt.Errorf("decode %s: %w", url, err)
+	if body, err = io.ReadAll(resp.Body); err != nil {
+		return nil, fmt.Errorf("read %s: %w", url, err)
 	}
-	return target, nil
+	return body, nil
 }
 
 type LiveSnapshot struct {
@@ -156,10 +213,13 @@ func ProfileIconURL(id int) string {
 }
 
 // StartAutoUpdate performs an initial fetch and then starts a background refresher.
-func StartAutoUpdate(ctx context.Context, interval time.Duration, log *slog.Logger) error {
+func StartAutoUpdate(ctx context.Context, client *Client, interval time.Duration, log *slog.Logger) error {
 	if log == nil {
 		log = slog.Default()
 	}
+	if client == nil {
+		return fmt.Errorf("client is required")
+	}
 	if interval <= 0 {
 		interval = DefaultInterval
 	}
@@ -168,7 +228,7 @@ func StartAutoUpdate(ctx context.Context, interval time.Duration, log *slog.Logg
 		fetchCtx, cancel := context.WithTimeout(ctx, versionFetchTimeout)
 		defer cancel()
 
-		versions, err := NewClient().FetchVersions(fetchCtx)
+		versions, err := client.FetchVersions(fetchCtx)
 		if err != nil {
 			return err
 		}
@@ -248,20 +308,20 @@ func fetchCoreAssets(ctx context.Context, c *Client, ver, loc string, includeIte
 	}
 
 	run("champions", func() (err error) {
-		a.Champions, err = c.FetchChampions(gctx, BaseURL, ver, loc)
+		a.Champions, err = c.FetchChampions(gctx, ver, loc)
 		return err
 	})
 	run("summoner spells", func() (err error) {
-		a.SummonerSpells, err = c.FetchSummonerSpells(gctx, BaseURL, ver, loc)
+		a.SummonerSpells, err = c.FetchSummonerSpells(gctx, ver, loc)
 		return err
 	})
 	run("runes", func() (err error) {
-		a.Runes, err = c.FetchRunes(gctx, BaseURL, ver, loc)
+		a.Runes, err = c.FetchRunes(gctx, ver, loc)
 		return err
 	})
 	if includeItems {
 		run("items", func() (err error) {
-			a.Items, err = c.FetchItems(gctx, BaseURL, ver, loc)
+			a.Items, err = c.FetchItems(gctx, ver, loc)
 			return err
 		})
 	}
@@ -272,10 +332,13 @@ func fetchCoreAssets(ctx context.Context, c *Client, ver, loc string, includeIte
 	return a, nil
 }
 
-func SyncBasicData(ctx context.Context, db Database, version string) error {
+func SyncBasicData(ctx context.Context, client *Client, db Database, version string) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	if client == nil {
+		return fmt.Errorf("client is required")
+	}
 	if db == nil {
 		return fmt.Errorf("database is nil")
 	}
@@ -283,7 +346,6 @@ func SyncBasicData(ctx context.Context, db Database, version string) error {
 		return fmt.Errorf("version is required")
 	}
 
-	client := NewClient()
 	fetchedAt := time.Now().UTC()
 	L.Update(version, fetchedAt)
 
@@ -0,0 +1,31 @@
+package cdn
+
+import (
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/httprecord"
+)
+
+func TestClientReplaysRecordedResponses(t *testing.T) {
+	server := newCDNTestServer(t)
+	dir := t.TempDir()
+	recorder := httprecord.New(dir, httprecord.ModeRecord)
+	live := NewClient(WithHTTPClient(recorder.Client()), WithDataDragonURL(server.URL+"/ddragon"), WithCommunityDragonURL(server.URL+"/cdragon"))
+	if err := SyncBasicData(t.Context(), live, &cdnSyncTestDB{}, "15.1.1"); err != nil {
+		t.Fatalf("SyncBasicData() while recording error = %v", err)
+	}
+	server.Close()
+
+	replayer := httprecord.New(dir, httprecord.ModeReplay)
+	replayed := NewClient(WithHTTPClient(replayer.Client()), WithDataDragonURL(server.URL+"/ddragon"), WithCommunityDragonURL(server.URL+"/cdragon"))
+	db := &cdnSyncTestDB{}
+	if err := SyncBasicData(t.Context(), replayed, db, "15.1.1"); err != nil {
+		t.Fatalf("SyncBasicData() from recordings error = %v", err)
+	}
+	if db.queues != 1 || db.champions != 1 || db.runeTrees != 1 {
+		t.Fatalf("synced from recordings = %+v", *db)
+	}
+	if _, err := replayed.FetchVersions(t.Context()); err == nil {
+		t.Fatalf("FetchVersions() without a recording error = nil")
+	}
+}
@@ -0,0 +1,243 @@
+package riot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRiotUserAgent = "League-API-bot/2.0"
+	// defaultBaseURLTemplate is where requests go unless a base URL is configured for their routing value.
+	defaultBaseURLTemplate = "https://" + routingPlaceholder + ".api.riotgames.com"
+	routingPlaceholder     = "{routing}"
+)
+
+// API is the part of the Riot API the bot uses. *Client implements it; tests and local setups can
+// substitute their own implementation.
+type API interface {
+	FetchAccountByRiotID(ctx context.Context, platformRegion, gameName, tagLine string) (RiotAccount, error)
+	FetchAccountByPUUID(ctx context.Context, platformRegion, puuid string) (RiotAccount, error)
+	FetchSummonerByPUUID(ctx context.Context, platformRegion, puuid string) (SummonerProfile, error)
+	FetchLeagueEntriesByPUUID(ctx context.Context, platformRegion, puuid string) ([]LeagueEntry, error)
+	FetchChampionRotation(ctx context.Context, platformRegion string) (ChampionRotation, error)
+	FetchActiveGameBySummoner(ctx context.Context, platformRegion, puuid string) (LiveGame, error)
+	FetchMatchByID(ctx context.Context, continent, matchID string) (MatchDetail, error)
+	FetchMatchIDsByPUUID(ctx context.Context, continent, puuid string, filter MatchIDsFilter) ([]string, error)
+	FetchTopChampionMasteries(ctx context.Context, platformRegion, puuid string, count int) ([]ChampionMastery, error)
+	FetchChampionMasteryScore(ctx context.Context, platformRegion, puuid string) (int, error)
+	// RequestRate reports how many requests per second the rate limits sustain for the endpoint.
+	RequestRate(endpoint string) float64
+}
+
+var _ API = (*Client)(nil)
+
+// APIKeyProvider returns the API key sent with each request, so a rotated key is picked up without
+// rebuilding the client.
+type APIKeyProvider func(ctx context.Context) (string, error)
+
+// Client calls the Riot API. The zero value is not usable; use NewClient.
+type Client struct {
+	apiKey          APIKeyProvider
+	httpClient      *http.Client
+	baseURLs        map[string]string
+	baseURLTemplate string
+	limiter         *RateLimiter
+	userAgent       string
+	logger          *slog.Logger
+}
+
+type ClientOption func(*Client)
+
+// WithAPIKey sends the same API key with every request.
+func WithAPIKey(apiKey string) ClientOption {
+	apiKey = strings.TrimSpace(apiKey)
+	return WithAPIKeyProvider(func(context.Context) (string, error) { return apiKey, nil })
+}
+
+func WithAPIKeyProvider(provider APIKeyProvider) ClientOption {
+	return func(c *Client) { c.apiKey = provider }
+}
+
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithBaseURL sends the requests of one routing value, e.g. "br1" or "americas", to baseURL instead of
+// the Riot API host of that routing value.
+func WithBaseURL(routing, baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURLs[strings.ToLower(strings.TrimSpace(routing))] = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	}
+}
+
+// WithBaseURLTemplate sends the requests of every routing value without its own base URL to template,
+// where "{routing}" is replaced by the routing value, e.g. "http://127.0.0.1:8080/{routing}".
+func WithBaseURLTemplate(template string) ClientOption {
+	return func(c *Client) {
+		if template = strings.TrimRight(strings.TrimSpace(template), "/"); template != "" {
+			c.baseURLTemplate = template
+		}
+	}
+}
+
+// WithRateLimiter paces the requests with limiter, which clients using the same API key should share.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		if limiter != nil {
+			c.limiter = limiter
+		}
+	}
+}
+
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		if userAgent = strings.TrimSpace(userAgent); userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:          func(context.Context) (string, error) { return "", nil },
+		httpClient:      http.DefaultClient,
+		baseURLs:        map[string]string{},
+		baseURLTemplate: defaultBaseURLTemplate,
+		limiter:         NewRateLimiter(),
+		userAgent:       defaultRiotUserAgent,
+		logger:          slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RateLimiter returns the limiter pacing the client's requests.
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.limiter
+}
+
+func (c *Client) RequestRate(endpoint string) float64 {
+	return c.limiter.RequestRate(endpoint)
+}
+
+// RateLimitEndpoint returns the Riot API URL of path on the routing value, which is what rate limits
+// are tracked against, wherever requests are sent. Pass it to RequestRate for the limits of one host.
+func RateLimitEndpoint(routing, path string) string {
+	return strings.ReplaceAll(defaultBaseURLTemplate, routingPlaceholder, strings.ToLower(routing)) + path
+}
+
+func (c *Client) baseURL(routing string) string {
+	if baseURL, ok := c.baseURLs[routing]; ok {
+		return baseURL
+	}
+	return strings.ReplaceAll(c.baseURLTemplate, routingPlaceholder, routing)
+}
+
+// getJSON decodes the response of a GET request into target, retrying rate limited and failed requests.
+// Rate limits are always tracked against the Riot API host of the routing value, wherever the request
+// is sent.
+func (c *Client) getJSON(ctx context.Context, routing, path string, target any) error {
+	apiKey, err := c.apiKey(ctx)
+	if err != nil {
+		return fmt.Errorf("riot api key: %w", err)
+	}
+	apiKey, err = requireNonEmpty("riot api key", apiKey)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return fmt.Errorf("target is nil")
+	}
+	routing = strings.ToLower(routing)
+	endpoint := RateLimitEndpoint(routing, path)
+	requestURL := c.baseURL(routing) + path
+
+	var lastErr error
+	for attempt := range maxRetryAttempts {
+		if attempt > 0 {
+			backoff := min(retryBaseDelay*time.Duration(1<<uint(attempt)), retryMaxDelay)
+			c.logger.Debug("Retrying Riot request", "url", requestURL, "attempt", attempt+1, "error", lastErr)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		if err := c.limiter.wait(ctx, endpoint, apiKey); err != nil {
+			return err
+		}
+
+		statusErr, err := c.do(ctx, endpoint, requestURL, apiKey, target)
+		if err == nil && statusErr == nil {
+			return nil
+		}
+		if err != nil {
+			if isRetryableRequestError(err) {
+				lastErr = err
+				continue
+			}
+			return err
+		}
+		if !isRetryable(statusErr.StatusCode) {
+			return statusErr
+		}
+		lastErr = statusErr
+	}
+	return lastErr
+}
+
+func (c *Client) do(ctx context.Context, endpoint, requestURL, apiKey string, target any) (*HTTPStatusError, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("X-Riot-Token", apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request %s: %w", requestURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	c.limiter.observeHeaders(endpoint, resp.Header, time.Now())
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", requestURL, err)
+		}
+		return nil, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	statusErr := &HTTPStatusError{
+		URL:        requestURL,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.limiter.backoff(endpoint, resp.Header, parseRetryAfter(resp), time.Now())
+	}
+	return statusErr, nil
+}
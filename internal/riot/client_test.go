@@ -0,0 +1,53 @@
+package riot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientRoutesRequestsToBaseURLs(t *testing.T) {
+	var gotPath, gotKey, gotAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotKey, gotAgent = r.URL.Path, r.Header.Get("X-Riot-Token"), r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte(`{"puuid":"p1","gameName":"Name","tagLine":"BR1"}`))
+	}))
+	t.Cleanup(server.Close)
+	platform := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = "platform:" + r.URL.Path
+		_, _ = w.Write([]byte(`42`))
+	}))
+	t.Cleanup(platform.Close)
+
+	client := NewClient(
+		WithAPIKey(" key "),
+		WithUserAgent("test-agent"),
+		WithBaseURLTemplate(server.URL+"/{routing}/"),
+		WithBaseURL("br1", platform.URL),
+	)
+	account, err := client.FetchAccountByPUUID(t.Context(), "br1", "p1")
+	if err != nil || account.PUUID != "p1" {
+		t.Fatalf("FetchAccountByPUUID() = %+v, %v", account, err)
+	}
+	if gotPath != "/americas/riot/account/v1/accounts/by-puuid/p1" || gotKey != "key" || gotAgent != "test-agent" {
+		t.Fatalf("request path=%q key=%q agent=%q", gotPath, gotKey, gotAgent)
+	}
+
+	score, err := client.FetchChampionMasteryScore(t.Context(), "br1", "p1")
+	if err != nil || score != 42 || gotPath != "platform:/lol/champion-mastery/v4/scores/by-puuid/p1" {
+		t.Fatalf("FetchChampionMasteryScore() = %d, %v via %q", score, err, gotPath)
+	}
+}
+
+func TestClientRequiresAPIKey(t *testing.T) {
+	if _, err := NewClient().FetchChampionRotation(t.Context(), "br1"); err == nil {
+		t.Fatalf("FetchChampionRotation() without API key error = nil")
+	}
+	providerErr := errors.New("vault sealed")
+	client := NewClient(WithAPIKeyProvider(func(context.Context) (string, error) { return "", providerErr }))
+	if _, err := client.FetchChampionRotation(t.Context(), "br1"); !errors.Is(err, providerErr) {
+		t.Fatalf("FetchChampionRotation() error = %v, want the provider error", err)
+	}
+}
@@ -106,7 +106,7 @@ type MatchBan struct {
 	PickTurn   int `json:"pickTurn"`
 }
 
-func FetchActiveGameBySummoner(ctx context.Context, platformRegion, puuid, apiKey string) (LiveGame, error) {
+func (c *Client) FetchActiveGameBySummoner(ctx context.Context, platformRegion, puuid string) (LiveGame, error) {
 	region, err := requirePlatformRegion(platformRegion)
 	if err != nil {
 		return LiveGame{}, err
@@ -116,16 +116,15 @@ func FetchActiveGameBySummoner(ctx context.Context, platformRegion, puuid, apiKe
 		return LiveGame{}, err
 	}
 
-	endpoint := fmt.Sprintf("https://%s.api.riotgames.com/lol/spectator/v5/active-games/by-summoner/%s",
-		strings.ToLower(region), url.PathEscape(puuid))
+	path := "/lol/spectator/v5/active-games/by-summoner/" + url.PathEscape(puuid)
 	var game LiveGame
-	if err := doRiotJSONWithRetry(ctx, endpoint, apiKey, &game); err != nil {
+	if err := c.getJSON(ctx, region, path, &game); err != nil {
 		return LiveGame{}, fmt.Errorf("fetch active game by summoner: %w", err)
 	}
 	return game, nil
 }
 
-func FetchMatchByID(ctx context.Context, continent, matchID, apiKey string) (MatchDetail, error) {
+func (c *Client) FetchMatchByID(ctx context.Context, continent, matchID string) (MatchDetail, error) {
 	continent, err := requireNonEmpty("continent", continent)
 	if err != nil {
 		return MatchDetail{}, err
@@ -135,10 +134,9 @@ func FetchMatchByID(ctx context.Context, continent, matchID, apiKey string) (Mat
 		return MatchDetail{}, err
 	}
 
-	endpoint := fmt.Sprintf("https://%s.api.riotgames.com/lol/match/v5/matches/%s",
-		strings.ToLower(continent), url.PathEscape(matchID))
+	path := "/lol/match/v5/matches/" + url.PathEscape(matchID)
 	var match MatchDetail
-	if err := doRiotJSONWithRetry(ctx, endpoint, apiKey, &match); err != nil {
+	if err := c.getJSON(ctx, continent, path, &match); err != nil {
 		return MatchDetail{}, fmt.Errorf("fetch match by id: %w", err)
 	}
 	return match, nil
@@ -151,3 +149,16 @@ func BuildMatchID(platformID string, gameID int64) string {
 	}
 	return fmt.Sprintf("%s_%d", platformID, gameID)
 }
+
+// ParseMatchID splits a match-v5 ID such as "BR1_123" into its platform and game ID.
+func ParseMatchID(matchID string) (string, int64, bool) {
+	platformID, gameID, found := strings.Cut(strings.TrimSpace(matchID), "_")
+	if !found || platformID == "" {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(gameID, 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+	return strings.ToUpper(platformID), id, true
+}
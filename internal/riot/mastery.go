@@ -0,0 +1,58 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const maxTopMasteriesCount = 25
+
+type ChampionMastery struct {
+	PUUID          string `json:"puuid"`
+	ChampionID     int    `json:"championId"`
+	ChampionLevel  int    `json:"championLevel"`
+	ChampionPoints int    `json:"championPoints"`
+	LastPlayTime   int64  `json:"lastPlayTime"`
+}
+
+func (c *Client) FetchTopChampionMasteries(ctx context.Context, platformRegion, puuid string, count int) ([]ChampionMastery, error) {
+	region, err := requirePlatformRegion(platformRegion)
+	if err != nil {
+		return nil, err
+	}
+	puuid, err = requireNonEmpty("puuid", puuid)
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		count = 3
+	}
+
+	path := fmt.Sprintf("/lol/champion-mastery/v4/champion-masteries/by-puuid/%s/top?count=%s",
+		url.PathEscape(puuid), strconv.Itoa(min(count, maxTopMasteriesCount)))
+	var masteries []ChampionMastery
+	if err := c.getJSON(ctx, region, path, &masteries); err != nil {
+		return nil, fmt.Errorf("fetch top champion masteries by puuid: %w", err)
+	}
+	return masteries, nil
+}
+
+func (c *Client) FetchChampionMasteryScore(ctx context.Context, platformRegion, puuid string) (int, error) {
+	region, err := requirePlatformRegion(platformRegion)
+	if err != nil {
+		return 0, err
+	}
+	puuid, err = requireNonEmpty("puuid", puuid)
+	if err != nil {
+		return 0, err
+	}
+
+	path := "/lol/champion-mastery/v4/scores/by-puuid/" + url.PathEscape(puuid)
+	var score int
+	if err := c.getJSON(ctx, region, path, &score); err != nil {
+		return 0, fmt.Errorf("fetch champion mastery score by puuid: %w", err)
+	}
+	return score, nil
+}
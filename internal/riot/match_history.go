@@ -0,0 +1,66 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxMatchIDsCount = 100
+
+// MatchIDsFilter narrows a match-v5 by-puuid lookup. Zero values are omitted from the query.
+type MatchIDsFilter struct {
+	Start     int
+	Count     int
+	QueueID   int
+	Type      string
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+func (f MatchIDsFilter) query() url.Values {
+	values := url.Values{}
+	if f.Start > 0 {
+		values.Set("start", strconv.Itoa(f.Start))
+	}
+	if f.Count > 0 {
+		values.Set("count", strconv.Itoa(min(f.Count, maxMatchIDsCount)))
+	}
+	if f.QueueID > 0 {
+		values.Set("queue", strconv.Itoa(f.QueueID))
+	}
+	if matchType := strings.ToLower(strings.TrimSpace(f.Type)); matchType != "" {
+		values.Set("type", matchType)
+	}
+	if !f.StartTime.IsZero() {
+		values.Set("startTime", strconv.FormatInt(f.StartTime.Unix(), 10))
+	}
+	if !f.EndTime.IsZero() {
+		values.Set("endTime", strconv.FormatInt(f.EndTime.Unix(), 10))
+	}
+	return values
+}
+
+func (c *Client) FetchMatchIDsByPUUID(ctx context.Context, continent, puuid string, filter MatchIDsFilter) ([]string, error) {
+	continent, err := requireNonEmpty("continent", continent)
+	if err != nil {
+		return nil, err
+	}
+	puuid, err = requireNonEmpty("puuid", puuid)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/lol/match/v5/matches/by-puuid/%s/ids", url.PathEscape(puuid))
+	if query := filter.query().Encode(); query != "" {
+		path += "?" + query
+	}
+	var matchIDs []string
+	if err := c.getJSON(ctx, continent, path, &matchIDs); err != nil {
+		return nil, fmt.Errorf("fetch match ids by puuid: %w", err)
+	}
+	return matchIDs, nil
+}
@@ -0,0 +1,67 @@
+package riot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchIDsFilterQuery(t *testing.T) {
+	filter := MatchIDsFilter{
+		Start:     20,
+		Count:     250,
+		QueueID:   420,
+		Type:      " Ranked ",
+		StartTime: time.Unix(1700000000, 0),
+	}
+	got := filter.query().Encode()
+	want := "count=100&queue=420&start=20&startTime=1700000000&type=ranked"
+	if got != want {
+		t.Fatalf("query() = %q, want %q", got, want)
+	}
+
+	if got := (MatchIDsFilter{}).query().Encode(); got != "" {
+		t.Fatalf("empty filter query() = %q, want empty", got)
+	}
+}
+
+func TestFormatKDA(t *testing.T) {
+	if got := FormatKDA(MatchPlayer{Kills: 10, Deaths: 2, Assists: 8}); got != "10/2/8 9.00:1" {
+		t.Fatalf("FormatKDA() = %q", got)
+	}
+	if got := FormatKDA(MatchPlayer{Kills: 3, Assists: 4}); got != "3/0/4 7.00:1" {
+		t.Fatalf("FormatKDA(deathless) = %q", got)
+	}
+}
+
+func TestMatchDurationSeconds(t *testing.T) {
+	if got := MatchDurationSeconds(MatchInfo{GameDuration: 1872}); got != 1872 {
+		t.Fatalf("MatchDurationSeconds(duration) = %d", got)
+	}
+	if got := MatchDurationSeconds(MatchInfo{GameStartTimestamp: 1000, GameEndTimestamp: 61000}); got != 60 {
+		t.Fatalf("MatchDurationSeconds(timestamps) = %d", got)
+	}
+	if got := FormatDuration(1872); got != "31m12s" {
+		t.Fatalf("FormatDuration() = %q", got)
+	}
+}
+
+func TestParseMatchID(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		platform string
+		gameID   int64
+		ok       bool
+	}{
+		{" br1_3001234567 ", "BR1", 3001234567, true},
+		{BuildMatchID("euw1", 42), "EUW1", 42, true},
+		{"3001234567", "", 0, false},
+		{"_12", "", 0, false},
+		{"NA1_abc", "", 0, false},
+		{"NA1_0", "", 0, false},
+	} {
+		platform, gameID, ok := ParseMatchID(tc.in)
+		if platform != tc.platform || gameID != tc.gameID || ok != tc.ok {
+			t.Fatalf("ParseMatchID(%q) = %q, %d, %v; want %q, %d, %v", tc.in, platform, gameID, ok, tc.platform, tc.gameID, tc.ok)
+		}
+	}
+}
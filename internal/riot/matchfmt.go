@@ -0,0 +1,46 @@
+package riot
+
+import (
+	"fmt"
+	"strings"
+)
+
+func MatchPlayerByPUUID(players []MatchPlayer, puuid string) *MatchPlayer {
+	puuid = strings.TrimSpace(puuid)
+	if puuid == "" {
+		return nil
+	}
+	for idx := range players {
+		if strings.TrimSpace(players[idx].PUUID) == puuid {
+			return &players[idx]
+		}
+	}
+	return nil
+}
+
+func FormatKDA(player MatchPlayer) string {
+	ratio := float64(player.Kills + player.Assists)
+	if player.Deaths > 0 {
+		ratio /= float64(player.Deaths)
+	}
+	return fmt.Sprintf("%d/%d/%d %.2f:1", player.Kills, player.Deaths, player.Assists, ratio)
+}
+
+func MatchDurationSeconds(info MatchInfo) int64 {
+	if info.GameDuration > 0 {
+		return info.GameDuration
+	}
+	if info.GameStartTimestamp > 0 && info.GameEndTimestamp > info.GameStartTimestamp {
+		return (info.GameEndTimestamp - info.GameStartTimestamp) / 1000
+	}
+	return 0
+}
+
+func FormatDuration(seconds int64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	minutes := seconds / 60
+	rem := seconds % 60
+	return fmt.Sprintf("%dm%ds", minutes, rem)
+}
@@ -0,0 +1,74 @@
+package riot
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Priority orders Riot requests competing for the same rate limits. Requests without a priority are
+// treated as interactive, since they come from a user waiting on a command.
+type Priority int
+
+const (
+	PriorityInteractive Priority = iota
+	PriorityPostGame
+	PriorityLiveProbe
+	PriorityBackground
+)
+
+const (
+	// priorityStarvationLimit is how long a request holds back for higher priorities before it queues
+	// like any other request.
+	priorityStarvationLimit = 10 * time.Second
+	priorityMinPoll         = 5 * time.Millisecond
+	priorityMaxPoll         = 250 * time.Millisecond
+)
+
+// priorityReserve is the share of each limiter's burst a priority leaves for the priorities above it.
+var priorityReserve = map[Priority]float64{
+	PriorityInteractive: 0,
+	PriorityPostGame:    0.1,
+	PriorityLiveProbe:   0.2,
+	PriorityBackground:  0.3,
+}
+
+type priorityKey struct{}
+
+// WithPriority marks the Riot requests made with ctx with the given priority.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+func priorityFrom(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityInteractive
+}
+
+// waitForLimiter waits for a token of the limiter. Lower priorities first wait until the limiter has
+// more tokens than their reserve, so queued interactive requests (which drive the tokens negative) go
+// first; after priorityStarvationLimit they stop holding back.
+func waitForLimiter(ctx context.Context, limiter *rate.Limiter, priority Priority) error {
+	required := min(1+priorityReserve[priority]*float64(limiter.Burst()), float64(limiter.Burst()))
+	if priority != PriorityInteractive && limiter.Limit() > 0 && limiter.Limit() != rate.Inf {
+		deadline := time.Now().Add(priorityStarvationLimit)
+		for {
+			now := time.Now()
+			missing := required - limiter.TokensAt(now)
+			if missing <= 0 || !now.Before(deadline) {
+				break
+			}
+			delay := time.Duration(missing / float64(limiter.Limit()) * float64(time.Second))
+			delay = min(max(delay, priorityMinPoll), priorityMaxPoll, deadline.Sub(now))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+	return limiter.Wait(ctx)
+}
@@ -0,0 +1,49 @@
+package riot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestPriorityFrom(t *testing.T) {
+	if got := priorityFrom(context.Background()); got != PriorityInteractive {
+		t.Fatalf("priorityFrom(background) = %v, want interactive", got)
+	}
+	if got := priorityFrom(WithPriority(context.Background(), PriorityLiveProbe)); got != PriorityLiveProbe {
+		t.Fatalf("priorityFrom() = %v, want live probe", got)
+	}
+}
+
+func TestWaitForLimiterHoldsBackLowPriority(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Limit(100), 10)
+	limiter.ReserveN(time.Now(), 9)
+
+	start := time.Now()
+	if err := waitForLimiter(t.Context(), limiter, PriorityInteractive); err != nil {
+		t.Fatalf("waitForLimiter(interactive) error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Fatalf("interactive waited %v, want no hold back", elapsed)
+	}
+
+	start = time.Now()
+	if err := waitForLimiter(t.Context(), limiter, PriorityBackground); err != nil {
+		t.Fatalf("waitForLimiter(background) error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Fatalf("background waited %v, want it to leave the reserve to higher priorities", elapsed)
+	}
+}
+
+func TestWaitForLimiterStopsOnCancel(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Limit(0.1), 10)
+	limiter.ReserveN(time.Now(), 10)
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	if err := waitForLimiter(ctx, limiter, PriorityBackground); err == nil {
+		t.Fatalf("waitForLimiter() error = nil, want the context error")
+	}
+}
@@ -135,3 +135,57 @@ func WithRankIcon(rankIcons map[string]string, tier, value string) string {
 	}
 	return fmt.Sprintf("%s %s", icon, value)
 }
+
+var rankTierOrder = []string{"iron", "bronze", "silver", "gold", "platinum", "emerald", "diamond", "master", "grandmaster", "challenger"}
+
+var rankDivisionOrder = map[string]int{"IV": 0, "III": 1, "II": 2, "I": 3}
+
+// RankTierIndex returns the position of tier on the ranked ladder, or -1 when unranked.
+func RankTierIndex(tier string) int {
+	tier = NormalizeRankTier(tier)
+	for idx, candidate := range rankTierOrder {
+		if candidate == tier {
+			return idx
+		}
+	}
+	return -1
+}
+
+// RankStep orders entries by tier and division, ignoring LP. Apex tiers have a single step.
+func RankStep(entry LeagueEntry) int {
+	tierIdx := RankTierIndex(entry.Tier)
+	if tierIdx < 0 {
+		return -1
+	}
+	if HideDivisionForTier(entry.Tier) {
+		return tierIdx * 4
+	}
+	return tierIdx*4 + rankDivisionOrder[strings.ToUpper(strings.TrimSpace(entry.Rank))]
+}
+
+// RankValue flattens an entry onto a single LP axis (100 LP per division).
+// Apex tiers share one LP pool, so they all start from the Master floor.
+func RankValue(entry LeagueEntry) (int, bool) {
+	tierIdx := RankTierIndex(entry.Tier)
+	if tierIdx < 0 {
+		return 0, false
+	}
+	if HideDivisionForTier(entry.Tier) {
+		return RankTierIndex("master")*400 + entry.LeaguePoints, true
+	}
+	return RankStep(entry)*100 + entry.LeaguePoints, true
+}
+
+// RankLabel renders "Gold II" or "Master", without LP.
+func RankLabel(entry LeagueEntry) string {
+	tier := NormalizeRankTier(entry.Tier)
+	if RankTierIndex(tier) < 0 {
+		return TierTitle("", "Unranked")
+	}
+	title := TierTitle(tier, "")
+	rank := strings.ToUpper(strings.TrimSpace(entry.Rank))
+	if HideDivisionForTier(tier) || rank == "" {
+		return title
+	}
+	return fmt.Sprintf("%s %s", title, rank)
+}
@@ -2,10 +2,8 @@ package riot
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -28,72 +26,52 @@ const (
 	retryMaxDelay            = 30 * time.Second
 )
 
-var (
-	limiterStateMu  = sync.RWMutex{}
-	defaultLimiters = []*rate.Limiter{
-		newRateLimiter(defaultRateLimitRequests, defaultRateLimitWindow, defaultRateLimitBurst),
-	}
+// RateLimiter paces Riot requests with the configured limits, the limits learned from Riot response
+// headers and, when set, a backend shared with other replicas. Clients sharing one API key should share
+// one RateLimiter.
+type RateLimiter struct {
+	mu               sync.RWMutex
+	defaultLimiters  []*rate.Limiter
+	defaultWindows   []rateLimitWindow
 	endpointLimiters []endpointLimiter
 
-	rateLimitMu    sync.RWMutex
-	rateLimitUntil time.Time
-)
+	learnedMu    sync.Mutex
+	learnedHosts map[string]*hostRateLimits
 
-func doRiotJSONWithRetry(ctx context.Context, endpoint, apiKey string, target any) error {
-	var lastErr error
-	for attempt := range maxRetryAttempts {
-		if attempt > 0 {
-			backoff := min(retryBaseDelay*time.Duration(1<<uint(attempt)), retryMaxDelay)
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		if err := waitForRateLimit(ctx, endpoint); err != nil {
-			return err
-		}
+	backendMu sync.RWMutex
+	backend   LimiterBackend
+}
 
-		statusErr, err := doRiotRequest(ctx, endpoint, apiKey, target)
-		if err == nil && statusErr == nil {
-			return nil
-		}
-		if err != nil {
-			if isRetryableRequestError(err) {
-				lastErr = err
-				continue
-			}
-			return err
-		}
-		if !isRetryable(statusErr.StatusCode) {
-			return statusErr
-		}
-		lastErr = statusErr
+// NewRateLimiter returns a RateLimiter with the default application limit and no endpoint limits.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		defaultLimiters: []*rate.Limiter{newRateLimiter(defaultRateLimitRequests, defaultRateLimitWindow, defaultRateLimitBurst)},
+		defaultWindows:  []rateLimitWindow{{Requests: defaultRateLimitRequests, Window: defaultRateLimitWindow, Burst: defaultRateLimitBurst}},
+		learnedHosts:    map[string]*hostRateLimits{},
 	}
-	return lastErr
 }
 
-func waitForRateLimit(ctx context.Context, endpoint string) error {
-	rateLimitMu.RLock()
-	until := rateLimitUntil
-	rateLimitMu.RUnlock()
-
-	if now := time.Now(); now.Before(until) {
+func (l *RateLimiter) wait(ctx context.Context, endpoint, apiKey string) error {
+	host, method := rateLimitScope(endpoint)
+	if until := l.learnedUntil(host, method); time.Now().Before(until) {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(until.Sub(now)):
+		case <-time.After(time.Until(until)):
 		}
 	}
 
-	limiters := limitersForEndpoint(endpoint)
+	priority := priorityFrom(ctx)
+	limiters := l.limitersForEndpoint(endpoint)
 	for _, limiter := range limiters {
-		if err := limiter.Wait(ctx); err != nil {
+		if err := waitForLimiter(ctx, limiter, priority); err != nil {
 			return err
 		}
 	}
-	return nil
+	if err := l.waitForLearned(ctx, host, method, priority); err != nil {
+		return err
+	}
+	return l.waitForShared(ctx, endpoint, apiKey)
 }
 
 func isRetryable(statusCode int) bool {
@@ -115,54 +93,6 @@ func isRetryableRequestError(err error) bool {
 	return ok
 }
 
-func doRiotRequest(ctx context.Context, endpoint, apiKey string, target any) (*HTTPStatusError, error) {
-	apiKey, err := requireNonEmpty("riot api key", apiKey)
-	if err != nil {
-		return nil, err
-	}
-	if target == nil {
-		return nil, fmt.Errorf("target is nil")
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("User-Agent", defaultRiotUserAgent)
-	req.Header.Set("X-Riot-Token", apiKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request %s: %w", endpoint, err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
-		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
-			return nil, fmt.Errorf("decode %s: %w", endpoint, err)
-		}
-		return nil, nil
-	}
-
-	body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-	statusErr := &HTTPStatusError{
-		URL:        endpoint,
-		StatusCode: resp.StatusCode,
-		Body:       string(body),
-	}
-
-	if resp.StatusCode == http.StatusTooManyRequests {
-		if retryAfter := parseRetryAfter(resp); retryAfter > 0 {
-			rateLimitMu.Lock()
-			if newUntil := time.Now().Add(retryAfter); newUntil.After(rateLimitUntil) {
-				rateLimitUntil = newUntil
-			}
-			rateLimitMu.Unlock()
-		}
-	}
-	return statusErr, nil
-}
-
 func parseRetryAfter(resp *http.Response) time.Duration {
 	val := resp.Header.Get("Retry-After")
 	if val == "" {
@@ -179,6 +109,7 @@ func parseRetryAfter(resp *http.Response) time.Duration {
 
 type endpointLimiter struct {
 	prefix   string
+	windows  []rateLimitWindow
 	limiters []*rate.Limiter
 }
 
@@ -188,13 +119,14 @@ type rateLimitWindow struct {
 	Burst    int
 }
 
-func applyRateLimitWindows(defaultWindows []rateLimitWindow, endpoints map[string][]rateLimitWindow) error {
-	compiledDefaults, err := compileLimiters(defaultWindows)
+func (l *RateLimiter) applyWindows(defaults []rateLimitWindow, endpoints map[string][]rateLimitWindow) error {
+	compiledDefaults, err := compileLimiters(defaults)
 	if err != nil {
 		return fmt.Errorf("compile default limiters: %w", err)
 	}
 	if len(compiledDefaults) == 0 {
 		compiledDefaults = []*rate.Limiter{newRateLimiter(defaultRateLimitRequests, defaultRateLimitWindow, defaultRateLimitBurst)}
+		defaults = []rateLimitWindow{{Requests: defaultRateLimitRequests, Window: defaultRateLimitWindow, Burst: defaultRateLimitBurst}}
 	}
 
 	compiledEndpoints := make([]endpointLimiter, 0, len(endpoints))
@@ -208,6 +140,7 @@ func applyRateLimitWindows(defaultWindows []rateLimitWindow, endpoints map[strin
 		}
 		compiledEndpoints = append(compiledEndpoints, endpointLimiter{
 			prefix:   prefix,
+			windows:  windows,
 			limiters: compiled,
 		})
 	}
@@ -215,10 +148,11 @@ func applyRateLimitWindows(defaultWindows []rateLimitWindow, endpoints map[strin
 		return len(compiledEndpoints[i].prefix) > len(compiledEndpoints[j].prefix)
 	})
 
-	limiterStateMu.Lock()
-	defaultLimiters = compiledDefaults
-	endpointLimiters = compiledEndpoints
-	limiterStateMu.Unlock()
+	l.mu.Lock()
+	l.defaultLimiters = compiledDefaults
+	l.defaultWindows = defaults
+	l.endpointLimiters = compiledEndpoints
+	l.mu.Unlock()
 	return nil
 }
 
@@ -249,17 +183,26 @@ func newRateLimiter(requests int, window time.Duration, burst int) *rate.Limiter
 	return rate.NewLimiter(rate.Every(window/time.Duration(requests)), burst)
 }
 
-func limitersForEndpoint(endpoint string) []*rate.Limiter {
+// limitersForEndpoint returns the configured limiters a request has to wait for. A scope with limits
+// learned from Riot headers is counted by its learned windows instead, see waitForLearned.
+func (l *RateLimiter) limitersForEndpoint(endpoint string) []*rate.Limiter {
 	path := endpointPath(endpoint)
+	learnedApp, learnedMethod := l.learnedWindows(rateLimitScope(endpoint))
 
-	limiterStateMu.RLock()
-	defer limiterStateMu.RUnlock()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 
-	selected := slices.Clone(defaultLimiters)
-	if path == "" || len(endpointLimiters) == 0 {
+	var selected []*rate.Limiter
+	if len(learnedApp) == 0 {
+		selected = slices.Clone(l.defaultLimiters)
+	}
+	if len(learnedMethod) > 0 {
 		return selected
 	}
-	for _, entry := range endpointLimiters {
+	if path == "" || len(l.endpointLimiters) == 0 {
+		return selected
+	}
+	for _, entry := range l.endpointLimiters {
 		if pathMatchesPrefix(path, entry.prefix) {
 			selected = append(selected, entry.limiters...)
 			break
@@ -268,6 +211,35 @@ func limitersForEndpoint(endpoint string) []*rate.Limiter {
 	return selected
 }
 
+// RequestRate reports how many requests per second the limits sustain for the endpoint, which is the
+// slowest of the application-wide and endpoint-specific limits. For an endpoint given as a bare path,
+// the slowest host the limits were learned for is used, or the configured limits before any were.
+func (l *RateLimiter) RequestRate(endpoint string) float64 {
+	endpoints := []string{endpoint}
+	if host, _ := rateLimitScope(endpoint); host == "" {
+		for _, host := range l.learnedHostNames() {
+			endpoints = append(endpoints, "https://"+host+endpoint)
+		}
+		if len(endpoints) > 1 {
+			endpoints = endpoints[1:]
+		}
+	}
+	limit := rate.Inf
+	for _, endpoint := range endpoints {
+		for _, limiter := range l.limitersForEndpoint(endpoint) {
+			limit = min(limit, limiter.Limit())
+		}
+		app, methodWindows := l.learnedWindows(rateLimitScope(endpoint))
+		for _, window := range slices.Concat(app, methodWindows) {
+			limit = min(limit, rate.Limit(float64(window.Requests)/window.Window.Seconds()))
+		}
+	}
+	if limit == rate.Inf {
+		return 0
+	}
+	return float64(limit)
+}
+
 func endpointPath(rawURL string) string {
 	u, err := url.Parse(rawURL)
 	if err != nil {
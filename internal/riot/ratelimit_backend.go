@@ -0,0 +1,128 @@
+package riot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// sharedLimitMaxWait caps a single wait on the shared backend, so a request re-checks its buckets
+// instead of oversleeping when another replica frees capacity.
+const sharedLimitMaxWait = 2 * time.Second
+
+// RateLimitWindow allows at most Requests per Window.
+type RateLimitWindow struct {
+	Requests int
+	Window   time.Duration
+}
+
+// RateLimitBucket is a set of windows counted together, e.g. the application limit of one region
+// or one method on that region, for one API key.
+type RateLimitBucket struct {
+	Key     string
+	Windows []RateLimitWindow
+}
+
+// LimiterBackend shares rate limit state between bot replicas. ReserveRiotRequest counts one request
+// in every window of the buckets at once, or counts nothing and reports how long to wait when any
+// window is full.
+type LimiterBackend interface {
+	ReserveRiotRequest(ctx context.Context, buckets []RateLimitBucket) (time.Duration, error)
+}
+
+// SetBackend makes every request also reserve its slot in the backend, on top of the in-process
+// limiters. Pass nil to go back to in-process limiting only.
+func (l *RateLimiter) SetBackend(backend LimiterBackend) {
+	l.backendMu.Lock()
+	l.backend = backend
+	l.backendMu.Unlock()
+}
+
+func (l *RateLimiter) currentBackend() LimiterBackend {
+	l.backendMu.RLock()
+	defer l.backendMu.RUnlock()
+	return l.backend
+}
+
+// waitForShared waits until the backend has room for the request. A failing backend does not block
+// requests: the in-process limiters still apply.
+func (l *RateLimiter) waitForShared(ctx context.Context, endpoint, apiKey string) error {
+	backend := l.currentBackend()
+	if backend == nil {
+		return nil
+	}
+	buckets := l.sharedBuckets(endpoint, apiKey)
+	if len(buckets) == 0 {
+		return nil
+	}
+	for {
+		wait, err := backend.ReserveRiotRequest(ctx, buckets)
+		if err != nil {
+			return ctx.Err()
+		}
+		if wait <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(wait, sharedLimitMaxWait)):
+		}
+	}
+}
+
+// sharedBuckets returns the application and method buckets of the endpoint, using the limits
+// learned from Riot and falling back to the configured ones.
+func (l *RateLimiter) sharedBuckets(endpoint, apiKey string) []RateLimitBucket {
+	host, method := rateLimitScope(endpoint)
+	if host == "" {
+		return nil
+	}
+	app, methodWindows := l.learnedWindows(host, method)
+	if len(app) == 0 || len(methodWindows) == 0 {
+		configuredApp, configuredMethod := l.configuredWindows(endpointPath(endpoint))
+		if len(app) == 0 {
+			app = configuredApp
+		}
+		if len(methodWindows) == 0 {
+			methodWindows = configuredMethod
+		}
+	}
+
+	keyID := apiKeyID(apiKey)
+	buckets := make([]RateLimitBucket, 0, 2)
+	if len(app) > 0 {
+		buckets = append(buckets, RateLimitBucket{Key: keyID + ":app:" + host, Windows: exportWindows(app)})
+	}
+	if len(methodWindows) > 0 {
+		buckets = append(buckets, RateLimitBucket{Key: keyID + ":method:" + host + method, Windows: exportWindows(methodWindows)})
+	}
+	return buckets
+}
+
+func (l *RateLimiter) configuredWindows(path string) ([]rateLimitWindow, []rateLimitWindow) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	app := l.defaultWindows
+	for _, entry := range l.endpointLimiters {
+		if pathMatchesPrefix(path, entry.prefix) {
+			return app, entry.windows
+		}
+	}
+	return app, nil
+}
+
+func exportWindows(windows []rateLimitWindow) []RateLimitWindow {
+	out := make([]RateLimitWindow, 0, len(windows))
+	for _, window := range windows {
+		out = append(out, RateLimitWindow{Requests: window.Requests, Window: window.Window})
+	}
+	return out
+}
+
+// apiKeyID identifies an API key in shared state without storing the key itself.
+func apiKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:6])
+}
@@ -35,7 +35,9 @@ type rateLimitEndpointTOML struct {
 	Limits []rateLimitWindowTOML `toml:"limits"`
 }
 
-func ConfigureRateLimitsFromFile(path string) (bool, error) {
+// ConfigureFromFile replaces the configured limits with the ones of a TOML file. A missing file keeps
+// the current limits and reports false.
+func (l *RateLimiter) ConfigureFromFile(path string) (bool, error) {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		return false, nil
@@ -75,7 +77,7 @@ func ConfigureRateLimitsFromFile(path string) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("compile rate limit config %q: %w", path, err)
 	}
-	if err := applyRateLimitWindows(defaults, endpoints); err != nil {
+	if err := l.applyWindows(defaults, endpoints); err != nil {
 		return false, fmt.Errorf("apply rate limit config %q: %w", path, err)
 	}
 	return true, nil
@@ -0,0 +1,350 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	headerAppRateLimit         = "X-App-Rate-Limit"
+	headerAppRateLimitCount    = "X-App-Rate-Limit-Count"
+	headerMethodRateLimit      = "X-Method-Rate-Limit"
+	headerMethodRateLimitCount = "X-Method-Rate-Limit-Count"
+	headerRateLimitType        = "X-Rate-Limit-Type"
+	rateLimitTypeApplication   = "application"
+)
+
+// riotMethodPaths identifies the Riot API methods the bot calls. Riot counts method limits per method,
+// so every path under one of these prefixes shares a limiter regardless of its path parameters.
+// More specific prefixes come first.
+var riotMethodPaths = []string{
+	"/riot/account/v1/accounts/by-riot-id/",
+	"/riot/account/v1/accounts/by-puuid/",
+	"/lol/summoner/v4/summoners/by-puuid/",
+	"/lol/league/v4/entries/by-puuid/",
+	"/lol/platform/v3/champion-rotations",
+	"/lol/spectator/v5/active-games/by-summoner/",
+	"/lol/match/v5/matches/by-puuid/",
+	"/lol/match/v5/matches/",
+	"/lol/champion-mastery/v4/champion-masteries/by-puuid/",
+	"/lol/champion-mastery/v4/scores/by-puuid/",
+}
+
+// hostRateLimits holds the limits Riot reported for one routing host, e.g. br1.api.riotgames.com.
+// Application limits are shared by every method of the host; method limits are kept per method.
+type hostRateLimits struct {
+	app     scopeRateLimit
+	methods map[string]*scopeRateLimit
+}
+
+// scopeRateLimit is the window set built from one rate limit header, and how long the scope is
+// blocked after a 429.
+type scopeRateLimit struct {
+	spec     string
+	windows  []rateLimitWindow
+	counters []*windowCounter
+	until    time.Time
+}
+
+// learn rebuilds the windows when Riot reports a new limit and brings the counters up to the requests
+// Riot counted, which include those of other processes using the key. Counts are applied on every
+// response, not only when the limit changes.
+func (s *scopeRateLimit) learn(spec, counts string, now time.Time) {
+	if spec = strings.TrimSpace(spec); spec != "" && spec != s.spec {
+		windows, err := parseRateLimitHeader(spec)
+		if err != nil {
+			return
+		}
+		counters := make([]*windowCounter, 0, len(windows))
+		for _, window := range windows {
+			counters = append(counters, &windowCounter{requests: window.Requests, window: window.Window})
+		}
+		s.spec, s.windows, s.counters = spec, windows, counters
+	}
+	used := parseRateLimitCounts(counts)
+	for _, counter := range s.counters {
+		if n, ok := used[counter.window]; ok {
+			counter.sync(n, now)
+		}
+	}
+}
+
+// windowCounter counts the requests of one Riot window the way Riot does: the window opens with its
+// first request and admits up to requests until it closes.
+type windowCounter struct {
+	requests int
+	window   time.Duration
+	start    time.Time
+	used     int
+}
+
+func (c *windowCounter) roll(now time.Time) {
+	if !c.start.IsZero() && !now.Before(c.start.Add(c.window)) {
+		c.start, c.used = time.Time{}, 0
+	}
+}
+
+// room returns how many more requests the window admits at now.
+func (c *windowCounter) room(now time.Time) int {
+	c.roll(now)
+	return c.requests - c.used
+}
+
+// reopensIn returns how long until the window closes and its requests are free again.
+func (c *windowCounter) reopensIn(now time.Time) time.Duration {
+	if c.start.IsZero() {
+		return 0
+	}
+	return c.start.Add(c.window).Sub(now)
+}
+
+func (c *windowCounter) take(now time.Time) {
+	c.roll(now)
+	if c.start.IsZero() {
+		c.start = now
+	}
+	c.used++
+}
+
+// sync raises the count to the one Riot reported. A lower count is ignored, since responses of
+// concurrent requests arrive out of order.
+func (c *windowCounter) sync(count int, now time.Time) {
+	c.roll(now)
+	if count <= c.used {
+		return
+	}
+	if c.start.IsZero() {
+		c.start = now
+	}
+	c.used = count
+}
+
+// rateLimitScope splits an endpoint into its routing host and the Riot method it calls.
+func rateLimitScope(endpoint string) (string, string) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", ""
+	}
+	return strings.ToLower(u.Host), riotMethod(u.Path)
+}
+
+func riotMethod(path string) string {
+	for _, prefix := range riotMethodPaths {
+		if strings.HasPrefix(path, prefix) || path == strings.TrimSuffix(prefix, "/") {
+			return prefix
+		}
+	}
+	return path
+}
+
+// observeHeaders adjusts the windows of the endpoint host and method to the limits and counts Riot
+// reports, so development and production keys get their own budget without configuration.
+func (l *RateLimiter) observeHeaders(endpoint string, header http.Header, now time.Time) {
+	appSpec, methodSpec := header.Get(headerAppRateLimit), header.Get(headerMethodRateLimit)
+	if appSpec == "" && methodSpec == "" {
+		return
+	}
+	host, method := rateLimitScope(endpoint)
+	if host == "" {
+		return
+	}
+
+	l.learnedMu.Lock()
+	defer l.learnedMu.Unlock()
+	limits := l.learnedHostLimits(host)
+	limits.app.learn(appSpec, header.Get(headerAppRateLimitCount), now)
+	if methodSpec != "" && method != "" {
+		limits.methodLimit(method).learn(methodSpec, header.Get(headerMethodRateLimitCount), now)
+	}
+}
+
+// backoff blocks the scope a 429 was returned for: the whole host when the application limit
+// was hit, otherwise only the method on that host.
+func (l *RateLimiter) backoff(endpoint string, header http.Header, retryAfter time.Duration, now time.Time) {
+	host, method := rateLimitScope(endpoint)
+	if host == "" || retryAfter <= 0 {
+		return
+	}
+	until := now.Add(retryAfter)
+
+	l.learnedMu.Lock()
+	defer l.learnedMu.Unlock()
+	limits := l.learnedHostLimits(host)
+	scope := &limits.app
+	if !strings.EqualFold(strings.TrimSpace(header.Get(headerRateLimitType)), rateLimitTypeApplication) {
+		scope = limits.methodLimit(method)
+	}
+	if until.After(scope.until) {
+		scope.until = until
+	}
+}
+
+// learnedUntil returns the time until which a 429 blocks the host and method.
+func (l *RateLimiter) learnedUntil(host, method string) time.Time {
+	l.learnedMu.Lock()
+	defer l.learnedMu.Unlock()
+	limits, ok := l.learnedHosts[host]
+	if !ok {
+		return time.Time{}
+	}
+	until := limits.app.until
+	if scope, ok := limits.methods[method]; ok && scope.until.After(until) {
+		until = scope.until
+	}
+	return until
+}
+
+// waitForLearned takes one request from every window learned for the host and method, waiting while
+// one of them is full. Lower priorities leave part of each window to the priorities above them until
+// they have held back for priorityStarvationLimit.
+func (l *RateLimiter) waitForLearned(ctx context.Context, host, method string, priority Priority) error {
+	deadline := time.Now().Add(priorityStarvationLimit)
+	for {
+		now := time.Now()
+		reserve := priorityReserve[priority]
+		if !now.Before(deadline) {
+			reserve = 0
+		}
+		wait, ok := l.takeLearned(host, method, reserve, now)
+		if ok {
+			return nil
+		}
+		if reserve > 0 {
+			wait = min(wait, max(deadline.Sub(now), priorityMinPoll))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// takeLearned counts the request in every learned window of the host and method when each has more room
+// than the reserved share, or returns how long until the fullest one reopens.
+func (l *RateLimiter) takeLearned(host, method string, reserve float64, now time.Time) (time.Duration, bool) {
+	l.learnedMu.Lock()
+	defer l.learnedMu.Unlock()
+	limits, ok := l.learnedHosts[host]
+	if !ok {
+		return 0, true
+	}
+	counters := slices.Clone(limits.app.counters)
+	if scope, ok := limits.methods[method]; ok {
+		counters = append(counters, scope.counters...)
+	}
+	var wait time.Duration
+	full := false
+	for _, counter := range counters {
+		if counter.room(now) <= int(reserve*float64(counter.requests)) {
+			full = true
+			wait = max(wait, counter.reopensIn(now))
+		}
+	}
+	if full {
+		return max(wait, priorityMinPoll), false
+	}
+	for _, counter := range counters {
+		counter.take(now)
+	}
+	return 0, true
+}
+
+// learnedWindows returns the windows learned for the host and method, nil when nothing was learned yet.
+func (l *RateLimiter) learnedWindows(host, method string) ([]rateLimitWindow, []rateLimitWindow) {
+	l.learnedMu.Lock()
+	defer l.learnedMu.Unlock()
+	limits, ok := l.learnedHosts[host]
+	if !ok {
+		return nil, nil
+	}
+	var methodWindows []rateLimitWindow
+	if scope, ok := limits.methods[method]; ok {
+		methodWindows = slices.Clone(scope.windows)
+	}
+	return slices.Clone(limits.app.windows), methodWindows
+}
+
+func (l *RateLimiter) learnedHostNames() []string {
+	l.learnedMu.Lock()
+	defer l.learnedMu.Unlock()
+	hosts := make([]string, 0, len(l.learnedHosts))
+	for host, limits := range l.learnedHosts {
+		if len(limits.app.windows) > 0 {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func (l *RateLimiter) learnedHostLimits(host string) *hostRateLimits {
+	limits, ok := l.learnedHosts[host]
+	if !ok {
+		limits = &hostRateLimits{methods: map[string]*scopeRateLimit{}}
+		l.learnedHosts[host] = limits
+	}
+	return limits
+}
+
+func (h *hostRateLimits) methodLimit(method string) *scopeRateLimit {
+	scope, ok := h.methods[method]
+	if !ok {
+		scope = &scopeRateLimit{}
+		h.methods[method] = scope
+	}
+	return scope
+}
+
+// parseRateLimitHeader parses a Riot limit header such as "20:1,100:120" (requests:seconds).
+func parseRateLimitHeader(value string) ([]rateLimitWindow, error) {
+	windows := make([]rateLimitWindow, 0, 2)
+	for part := range strings.SplitSeq(value, ",") {
+		requests, seconds, err := parseRateLimitPair(part)
+		if err != nil {
+			return nil, err
+		}
+		if requests <= 0 || seconds <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q", part)
+		}
+		windows = append(windows, rateLimitWindow{Requests: requests, Window: time.Duration(seconds) * time.Second})
+	}
+	return windows, nil
+}
+
+// parseRateLimitCounts parses a Riot count header such as "7:1,58:120" into the requests used per window.
+func parseRateLimitCounts(value string) map[time.Duration]int {
+	counts := map[time.Duration]int{}
+	if strings.TrimSpace(value) == "" {
+		return counts
+	}
+	for part := range strings.SplitSeq(value, ",") {
+		count, seconds, err := parseRateLimitPair(part)
+		if err != nil || seconds <= 0 {
+			continue
+		}
+		counts[time.Duration(seconds)*time.Second] = count
+	}
+	return counts
+}
+
+func parseRateLimitPair(part string) (int, int, error) {
+	left, right, found := strings.Cut(strings.TrimSpace(part), ":")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid rate limit %q", part)
+	}
+	first, err := strconv.Atoi(strings.TrimSpace(left))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rate limit %q: %w", part, err)
+	}
+	second, err := strconv.Atoi(strings.TrimSpace(right))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rate limit %q: %w", part, err)
+	}
+	return first, second, nil
+}
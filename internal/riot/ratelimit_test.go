@@ -0,0 +1,251 @@
+package riot
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestRate(t *testing.T) {
+	l := NewRateLimiter()
+	if err := l.applyWindows(
+		[]rateLimitWindow{{Requests: 100, Window: time.Second}},
+		map[string][]rateLimitWindow{"/lol/spectator/v5/active-games/by-summoner/": {{Requests: 600, Window: time.Minute}}},
+	); err != nil {
+		t.Fatalf("applyRateLimitWindows() error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		endpoint string
+		want     float64
+	}{
+		{"https://br1.api.riotgames.com/lol/spectator/v5/active-games/by-summoner/abc", 10},
+		{spectatorPath, 10},
+		{"https://br1.api.riotgames.com/lol/summoner/v4/summoners/by-puuid/abc", 100},
+	} {
+		if got := l.RequestRate(tc.endpoint); math.Abs(got-tc.want) > 0.001 {
+			t.Fatalf("RequestRate(%q) = %v, want %v", tc.endpoint, got, tc.want)
+		}
+	}
+}
+
+func TestParseRateLimitHeader(t *testing.T) {
+	windows, err := parseRateLimitHeader("20:1, 100:120")
+	if err != nil || len(windows) != 2 || windows[0] != (rateLimitWindow{Requests: 20, Window: time.Second}) || windows[1] != (rateLimitWindow{Requests: 100, Window: 2 * time.Minute}) {
+		t.Fatalf("parseRateLimitHeader() = %+v, %v", windows, err)
+	}
+	for _, value := range []string{"", "20", "a:1", "20:0"} {
+		if _, err := parseRateLimitHeader(value); err == nil {
+			t.Fatalf("parseRateLimitHeader(%q) error = nil, want error", value)
+		}
+	}
+	if counts := parseRateLimitCounts("7:1,58:120,x"); counts[time.Second] != 7 || counts[2*time.Minute] != 58 || len(counts) != 2 {
+		t.Fatalf("parseRateLimitCounts() = %v", counts)
+	}
+}
+
+func TestRiotMethod(t *testing.T) {
+	for path, want := range map[string]string{
+		"/lol/match/v5/matches/BR1_1":              "/lol/match/v5/matches/",
+		"/lol/match/v5/matches/by-puuid/abc/ids":   "/lol/match/v5/matches/by-puuid/",
+		"/lol/platform/v3/champion-rotations":      "/lol/platform/v3/champion-rotations",
+		"/riot/account/v1/accounts/by-riot-id/a/b": "/riot/account/v1/accounts/by-riot-id/",
+		"/lol/status/v4/platform-data":             "/lol/status/v4/platform-data",
+	} {
+		if got := riotMethod(path); got != want {
+			t.Fatalf("riotMethod(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestLearnedRateLimitsReplaceConfiguredOnes(t *testing.T) {
+	l := NewRateLimiter()
+	now := time.Now()
+	endpoint := "https://br1.api.riotgames.com/lol/spectator/v5/active-games/by-summoner/abc"
+	header := http.Header{}
+	header.Set(headerAppRateLimit, "20:1,100:120")
+	header.Set(headerAppRateLimitCount, "20:1,3:120")
+	header.Set(headerMethodRateLimit, "50:10")
+	l.observeHeaders(endpoint, header, now)
+
+	if limiters := l.limitersForEndpoint(endpoint); len(limiters) != 0 {
+		t.Fatalf("configured limiters = %d, want none once both scopes are learned", len(limiters))
+	}
+	if app, methodWindows := l.learnedWindows(rateLimitScope(endpoint)); len(app) != 2 || len(methodWindows) != 1 {
+		t.Fatalf("learned windows = %d app, %d method; want 2, 1", len(app), len(methodWindows))
+	}
+	if wait, ok := l.takeLearned("br1.api.riotgames.com", spectatorPath, 0, now); ok || wait <= 0 || wait > time.Second {
+		t.Fatalf("takeLearned() = %v, %v; want the counted 1s window full", wait, ok)
+	}
+	if got := l.RequestRate(spectatorPath); math.Abs(got-100.0/120) > 0.001 {
+		t.Fatalf("RequestRate(path) = %v, want the learned 100:120 window", got)
+	}
+	if got := len(l.limitersForEndpoint("https://na1.api.riotgames.com/lol/spectator/v5/active-games/by-summoner/abc")); got != 1 {
+		t.Fatalf("other host limiters = %d, want the configured default only", got)
+	}
+}
+
+func TestLearnedWindowAdmitsAtMostItsRequests(t *testing.T) {
+	endpoint := "https://br1.api.riotgames.com/lol/summoner/v4/summoners/by-puuid/abc"
+	admitted := func(l *RateLimiter) int {
+		ctx, cancel := context.WithTimeout(t.Context(), 900*time.Millisecond)
+		defer cancel()
+		n := 0
+		for l.wait(ctx, endpoint, "key") == nil {
+			n++
+		}
+		return n
+	}
+	header := http.Header{}
+	header.Set(headerAppRateLimit, "5:1")
+
+	l := NewRateLimiter()
+	l.observeHeaders(endpoint, header, time.Now())
+	if got := admitted(l); got != 5 {
+		t.Fatalf("admitted in one window = %d, want 5", got)
+	}
+
+	// A count reported after the limit was learned still applies, e.g. requests of another replica.
+	l = NewRateLimiter()
+	l.observeHeaders(endpoint, header, time.Now())
+	header.Set(headerAppRateLimitCount, "4:1")
+	l.observeHeaders(endpoint, header, time.Now())
+	if got := admitted(l); got != 1 {
+		t.Fatalf("admitted after Riot counted 4 = %d, want 1", got)
+	}
+}
+
+func TestBackoffRateLimitScopes(t *testing.T) {
+	l := NewRateLimiter()
+	now := time.Now()
+	spectator := "https://br1.api.riotgames.com/lol/spectator/v5/active-games/by-summoner/abc"
+	summoner := "https://br1.api.riotgames.com/lol/summoner/v4/summoners/by-puuid/abc"
+	otherRegion := "https://na1.api.riotgames.com/lol/spectator/v5/active-games/by-summoner/abc"
+	until := func(endpoint string) time.Time {
+		return l.learnedUntil(rateLimitScope(endpoint))
+	}
+
+	header := http.Header{}
+	header.Set(headerRateLimitType, "method")
+	l.backoff(spectator, header, time.Minute, now)
+	if !until(spectator).Equal(now.Add(time.Minute)) || !until(summoner).IsZero() || !until(otherRegion).IsZero() {
+		t.Fatalf("method backoff leaked: spectator=%v summoner=%v other=%v", until(spectator), until(summoner), until(otherRegion))
+	}
+
+	header.Set(headerRateLimitType, "application")
+	l.backoff(summoner, header, 2*time.Minute, now)
+	if !until(spectator).Equal(now.Add(2*time.Minute)) || !until(otherRegion).IsZero() {
+		t.Fatalf("application backoff: spectator=%v other=%v", until(spectator), until(otherRegion))
+	}
+}
+
+func TestClientLearnsLimitsAndScopesBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerAppRateLimit, "20:1")
+		w.Header().Set(headerMethodRateLimit, "5:10")
+		if strings.HasSuffix(r.URL.Path, "/limited") {
+			w.Header().Set("Retry-After", "30")
+			w.Header().Set(headerRateLimitType, "method")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(WithAPIKey("key"), WithBaseURLTemplate(server.URL))
+	l := client.RateLimiter()
+
+	var target map[string]any
+	endpoint := "https://americas.api.riotgames.com/lol/match/v5/matches/BR1_1"
+	if statusErr, err := client.do(t.Context(), endpoint, server.URL+"/lol/match/v5/matches/BR1_1", "key", &target); statusErr != nil || err != nil {
+		t.Fatalf("do() = %v, %v", statusErr, err)
+	}
+	host, method := rateLimitScope(endpoint)
+	if app, methodWindows := l.learnedWindows(host, method); len(app) != 1 || len(methodWindows) != 1 {
+		t.Fatalf("learned windows = %d app, %d method; want 1, 1", len(app), len(methodWindows))
+	}
+
+	limited := "https://americas.api.riotgames.com/lol/status/limited"
+	if statusErr, _ := client.do(t.Context(), limited, server.URL+"/lol/status/limited", "key", &target); statusErr == nil || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("do(limited) = %v, want 429", statusErr)
+	}
+	if until := l.learnedUntil(rateLimitScope(limited)); time.Until(until) < 20*time.Second {
+		t.Fatalf("limited method blocked until %v, want about 30s", until)
+	}
+	if until := l.learnedUntil(host, method); !until.IsZero() {
+		t.Fatalf("other method blocked until %v, want no backoff", until)
+	}
+}
+
+const spectatorPath = "/lol/spectator/v5/active-games/by-summoner/"
+
+func TestSharedRateLimitBuckets(t *testing.T) {
+	l := NewRateLimiter()
+	if err := l.applyWindows(
+		[]rateLimitWindow{{Requests: 100, Window: time.Second}},
+		map[string][]rateLimitWindow{spectatorPath: {{Requests: 600, Window: time.Minute}}},
+	); err != nil {
+		t.Fatalf("applyRateLimitWindows() error = %v", err)
+	}
+	endpoint := "https://br1.api.riotgames.com/lol/spectator/v5/active-games/by-summoner/abc"
+
+	buckets := l.sharedBuckets(endpoint, "key")
+	keyID := apiKeyID("key")
+	if len(buckets) != 2 || buckets[0].Key != keyID+":app:br1.api.riotgames.com" || buckets[1].Key != keyID+":method:br1.api.riotgames.com"+spectatorPath {
+		t.Fatalf("buckets = %+v", buckets)
+	}
+	if buckets[0].Windows[0] != (RateLimitWindow{Requests: 100, Window: time.Second}) || buckets[1].Windows[0] != (RateLimitWindow{Requests: 600, Window: time.Minute}) {
+		t.Fatalf("configured windows = %+v", buckets)
+	}
+
+	header := http.Header{}
+	header.Set(headerAppRateLimit, "20:1,100:120")
+	l.observeHeaders(endpoint, header, time.Now())
+	buckets = l.sharedBuckets(endpoint, "key")
+	if len(buckets[0].Windows) != 2 || buckets[0].Windows[1] != (RateLimitWindow{Requests: 100, Window: 2 * time.Minute}) {
+		t.Fatalf("learned app windows = %+v", buckets[0].Windows)
+	}
+	if other := l.sharedBuckets(endpoint, "other-key"); other[0].Key == buckets[0].Key {
+		t.Fatalf("buckets of different API keys share the key %q", other[0].Key)
+	}
+}
+
+func TestWaitForSharedRateLimit(t *testing.T) {
+	backend := &fakeLimiterBackend{waits: []time.Duration{10 * time.Millisecond, 0}}
+	l := NewRateLimiter()
+	l.SetBackend(backend)
+
+	endpoint := "https://br1.api.riotgames.com/lol/match/v5/matches/BR1_1"
+	if err := l.waitForShared(t.Context(), endpoint, "key"); err != nil || backend.calls != 2 {
+		t.Fatalf("waitForShared() = %v after %d calls, want nil after 2", err, backend.calls)
+	}
+
+	backend.err = errors.New("database down")
+	if err := l.waitForShared(t.Context(), endpoint, "key"); err != nil {
+		t.Fatalf("waitForShared() with failing backend = %v, want requests to go on", err)
+	}
+}
+
+type fakeLimiterBackend struct {
+	waits []time.Duration
+	err   error
+	calls int
+}
+
+func (f *fakeLimiterBackend) ReserveRiotRequest(context.Context, []RateLimitBucket) (time.Duration, error) {
+	f.calls++
+	if f.err != nil {
+		return 0, f.err
+	}
+	if len(f.waits) == 0 {
+		return 0, nil
+	}
+	wait := f.waits[0]
+	f.waits = f.waits[1:]
+	return wait, nil
+}
@@ -0,0 +1,81 @@
+package riotfake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+)
+
+// Fixtures is the data a Server starts with. Every field is optional.
+type Fixtures struct {
+	Accounts  []riot.RiotAccount
+	Summoners []riot.SummonerProfile
+	// LeagueEntries maps a PUUID to its ranked entries.
+	LeagueEntries    map[string][]riot.LeagueEntry
+	ChampionRotation riot.ChampionRotation
+	// ActiveGames are the games in progress; their participants are in game.
+	ActiveGames []riot.LiveGame
+	Matches     []riot.MatchDetail
+}
+
+// LoadFixtures reads a fixture directory laid out as:
+//
+//	accounts.json          []account-v1 account
+//	summoners.json         []summoner-v4 summoner
+//	league_entries.json    {puuid: []league-v4 entry}
+//	champion_rotation.json champion-v3 rotation
+//	active_games.json      []spectator-v5 game
+//	matches/*.json         one match-v5 match per file
+//
+// Missing files are skipped, so a directory only needs the data a scenario uses.
+func LoadFixtures(dir string) (Fixtures, error) {
+	var fixtures Fixtures
+	files := []struct {
+		name   string
+		target any
+	}{
+		{"accounts.json", &fixtures.Accounts},
+		{"summoners.json", &fixtures.Summoners},
+		{"league_entries.json", &fixtures.LeagueEntries},
+		{"champion_rotation.json", &fixtures.ChampionRotation},
+		{"active_games.json", &fixtures.ActiveGames},
+	}
+	for _, file := range files {
+		if err := readFixture(filepath.Join(dir, file.name), file.target); err != nil {
+			return Fixtures{}, err
+		}
+	}
+
+	matchFiles, err := filepath.Glob(filepath.Join(dir, "matches", "*.json"))
+	if err != nil {
+		return Fixtures{}, fmt.Errorf("list match fixtures: %w", err)
+	}
+	slices.Sort(matchFiles)
+	for _, path := range matchFiles {
+		var match riot.MatchDetail
+		if err := readFixture(path, &match); err != nil {
+			return Fixtures{}, err
+		}
+		fixtures.Matches = append(fixtures.Matches, match)
+	}
+	return fixtures, nil
+}
+
+func readFixture(path string, target any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read fixture %q: %w", path, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("parse fixture %q: %w", path, err)
+	}
+	return nil
+}
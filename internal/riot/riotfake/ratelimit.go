@@ -0,0 +1,68 @@
+package riotfake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// limitWindow counts requests in a fixed window, the way Riot reports them in the count headers.
+type limitWindow struct {
+	requests int
+	length   time.Duration
+	start    time.Time
+	count    int
+}
+
+// limitCounter enforces one rate limit header value such as "20:1,100:120" (requests:seconds).
+type limitCounter struct {
+	spec    string
+	windows []*limitWindow
+}
+
+func newLimitCounter(spec string) (*limitCounter, error) {
+	counter := &limitCounter{spec: strings.TrimSpace(spec)}
+	if counter.spec == "" {
+		return counter, nil
+	}
+	for part := range strings.SplitSeq(counter.spec, ",") {
+		left, right, found := strings.Cut(strings.TrimSpace(part), ":")
+		requests, reqErr := strconv.Atoi(strings.TrimSpace(left))
+		seconds, secErr := strconv.Atoi(strings.TrimSpace(right))
+		if !found || reqErr != nil || secErr != nil || requests <= 0 || seconds <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q", part)
+		}
+		counter.windows = append(counter.windows, &limitWindow{requests: requests, length: time.Duration(seconds) * time.Second})
+	}
+	return counter, nil
+}
+
+// wait reports how long until every window has room, zero when the request can go now.
+func (c *limitCounter) wait(now time.Time) time.Duration {
+	var wait time.Duration
+	for _, window := range c.windows {
+		if now.Sub(window.start) >= window.length {
+			window.start, window.count = now, 0
+		}
+		if window.count >= window.requests {
+			wait = max(wait, window.start.Add(window.length).Sub(now))
+		}
+	}
+	return wait
+}
+
+func (c *limitCounter) take() {
+	for _, window := range c.windows {
+		window.count++
+	}
+}
+
+// counts formats the requests used per window, e.g. "3:1,12:120".
+func (c *limitCounter) counts() string {
+	parts := make([]string, 0, len(c.windows))
+	for _, window := range c.windows {
+		parts = append(parts, fmt.Sprintf("%d:%d", window.count, int(window.length.Seconds())))
+	}
+	return strings.Join(parts, ",")
+}
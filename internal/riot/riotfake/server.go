@@ -0,0 +1,471 @@
+// Package riotfake serves a local stand-in for the Riot API endpoints the bot calls, backed by fixtures
+// and scriptable at runtime, so the bot and its tests run without a Riot API key or network access.
+//
+// Requests carry their routing value as the first path segment, which is what a riot.Client built with
+// riot.WithBaseURLTemplate("http://<addr>/{routing}") sends.
+package riotfake
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+)
+
+const (
+	// DefaultAppRateLimit is the application limit of a Riot development key.
+	DefaultAppRateLimit    = "20:1,100:120"
+	DefaultMethodRateLimit = "2000:60"
+
+	LimitTypeApplication = "application"
+	LimitTypeMethod      = "method"
+	// LimitTypeService answers 429 without X-Rate-Limit-Type, like Riot does when a service is overloaded.
+	LimitTypeService = "service"
+
+	defaultMatchIDsCount = 20
+)
+
+type Option func(*Server) error
+
+// WithAPIKey rejects requests with another API key with 403. Any non-empty key is accepted by default.
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) error {
+		s.apiKey = strings.TrimSpace(apiKey)
+		return nil
+	}
+}
+
+// WithRateLimits sets the application and method limits the server reports and enforces per routing
+// value, in the X-App-Rate-Limit format. An empty value disables that limit.
+func WithRateLimits(app, method string) Option {
+	return func(s *Server) error {
+		for _, spec := range []string{app, method} {
+			if _, err := newLimitCounter(spec); err != nil {
+				return err
+			}
+		}
+		s.appSpec, s.methodSpec = app, method
+		return nil
+	}
+}
+
+// Server is an http.Handler imitating the Riot API. It is safe for concurrent use.
+type Server struct {
+	mux        *http.ServeMux
+	apiKey     string
+	appSpec    string
+	methodSpec string
+
+	mu            sync.Mutex
+	accounts      []riot.RiotAccount
+	summoners     map[string]riot.SummonerProfile
+	leagueEntries map[string][]riot.LeagueEntry
+	rotation      riot.ChampionRotation
+	games         map[int64]riot.LiveGame
+	matches       map[string]riot.MatchDetail
+	appLimits     map[string]*limitCounter
+	methodLimits  map[string]*limitCounter
+	forcedLimits  []forcedLimit
+	requests      int
+}
+
+type forcedLimit struct {
+	retryAfter time.Duration
+	limitType  string
+}
+
+func NewServer(fixtures Fixtures, opts ...Option) (*Server, error) {
+	s := &Server{
+		mux:           http.NewServeMux(),
+		appSpec:       DefaultAppRateLimit,
+		methodSpec:    DefaultMethodRateLimit,
+		accounts:      slices.Clone(fixtures.Accounts),
+		summoners:     make(map[string]riot.SummonerProfile, len(fixtures.Summoners)),
+		leagueEntries: make(map[string][]riot.LeagueEntry, len(fixtures.LeagueEntries)),
+		rotation:      fixtures.ChampionRotation,
+		games:         make(map[int64]riot.LiveGame, len(fixtures.ActiveGames)),
+		matches:       make(map[string]riot.MatchDetail, len(fixtures.Matches)),
+		appLimits:     map[string]*limitCounter{},
+		methodLimits:  map[string]*limitCounter{},
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	for _, summoner := range fixtures.Summoners {
+		s.summoners[summoner.PUUID] = summoner
+	}
+	for puuid, entries := range fixtures.LeagueEntries {
+		s.leagueEntries[puuid] = entries
+	}
+	for _, game := range fixtures.ActiveGames {
+		s.games[game.GameID] = game
+	}
+	for _, match := range fixtures.Matches {
+		s.matches[match.Metadata.MatchID] = match
+	}
+	s.routes()
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Requests reports how many Riot API requests the server received, including rejected ones.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// StartGame puts the participants of the game in game until EndGame. A zero GameStartTime starts the
+// game now.
+func (s *Server) StartGame(game riot.LiveGame) {
+	if game.GameStartTime == 0 {
+		game.GameStartTime = time.Now().UnixMilli()
+	}
+	s.mu.Lock()
+	s.games[game.GameID] = game
+	s.mu.Unlock()
+}
+
+// EndGame ends a game started with StartGame or loaded from the fixtures and stores match as its
+// result. A match without an ID is built from the live game, with the blue team winning. It reports
+// false when no game has that ID.
+func (s *Server) EndGame(gameID int64, match riot.MatchDetail) (riot.MatchDetail, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	game, ok := s.games[gameID]
+	if !ok {
+		return riot.MatchDetail{}, false
+	}
+	delete(s.games, gameID)
+	if match.Metadata.MatchID == "" {
+		match = matchFromLiveGame(game, time.Now())
+	}
+	s.matches[match.Metadata.MatchID] = match
+	return match, true
+}
+
+// SetLeagueEntries replaces the ranked entries of an account, e.g. to move its LP after a game.
+func (s *Server) SetLeagueEntries(puuid string, entries []riot.LeagueEntry) {
+	s.mu.Lock()
+	s.leagueEntries[puuid] = entries
+	s.mu.Unlock()
+}
+
+// LimitNext answers the next n Riot API requests with 429 and the given Retry-After, as the limit
+// type would.
+func (s *Server) LimitNext(n int, retryAfter time.Duration, limitType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for range n {
+		s.forcedLimits = append(s.forcedLimits, forcedLimit{retryAfter: retryAfter, limitType: limitType})
+	}
+}
+
+func (s *Server) routes() {
+	s.riotRoute("/riot/account/v1/accounts/by-riot-id/{gameName}/{tagLine}", func(r *http.Request) (any, bool) {
+		gameName, tagLine := r.PathValue("gameName"), r.PathValue("tagLine")
+		for _, account := range s.accounts {
+			if strings.EqualFold(account.GameName, gameName) && strings.EqualFold(account.TagLine, tagLine) {
+				return account, true
+			}
+		}
+		return nil, false
+	})
+	s.riotRoute("/riot/account/v1/accounts/by-puuid/{puuid}", func(r *http.Request) (any, bool) {
+		for _, account := range s.accounts {
+			if account.PUUID == r.PathValue("puuid") {
+				return account, true
+			}
+		}
+		return nil, false
+	})
+	s.riotRoute("/lol/summoner/v4/summoners/by-puuid/{puuid}", func(r *http.Request) (any, bool) {
+		summoner, ok := s.summoners[r.PathValue("puuid")]
+		return summoner, ok
+	})
+	s.riotRoute("/lol/league/v4/entries/by-puuid/{puuid}", func(r *http.Request) (any, bool) {
+		entries := s.leagueEntries[r.PathValue("puuid")]
+		if entries == nil {
+			entries = []riot.LeagueEntry{}
+		}
+		return entries, true
+	})
+	s.riotRoute("/lol/platform/v3/champion-rotations", func(*http.Request) (any, bool) {
+		return s.rotation, true
+	})
+	s.riotRoute("/lol/spectator/v5/active-games/by-summoner/{puuid}", func(r *http.Request) (any, bool) {
+		return s.activeGame(r.PathValue("routing"), r.PathValue("puuid"), time.Now())
+	})
+	s.riotRoute("/lol/match/v5/matches/{matchID}", func(r *http.Request) (any, bool) {
+		match, ok := s.matches[r.PathValue("matchID")]
+		return match, ok
+	})
+	s.riotRoute("/lol/match/v5/matches/by-puuid/{puuid}/ids", func(r *http.Request) (any, bool) {
+		return s.matchIDs(r.PathValue("puuid"), r), true
+	})
+
+	s.mux.HandleFunc("POST /riotfake/games", s.handleStartGame)
+	s.mux.HandleFunc("POST /riotfake/games/{gameID}/end", s.handleEndGame)
+	s.mux.HandleFunc("PUT /riotfake/league-entries/{puuid}", s.handleSetLeagueEntries)
+	s.mux.HandleFunc("POST /riotfake/rate-limit", s.handleLimitNext)
+}
+
+// riotRoute registers a Riot API method. lookup runs with the server locked and reports false for 404.
+func (s *Server) riotRoute(path string, lookup func(r *http.Request) (any, bool)) {
+	s.mux.HandleFunc("GET /{routing}"+path, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		if !s.authorize(w, r) || !s.admit(w, r.PathValue("routing"), path, time.Now()) {
+			return
+		}
+		value, ok := lookup(r)
+		if !ok {
+			writeStatus(w, http.StatusNotFound, "Data not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, value)
+	})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) bool {
+	apiKey := strings.TrimSpace(r.Header.Get("X-Riot-Token"))
+	switch {
+	case apiKey == "":
+		writeStatus(w, http.StatusUnauthorized, "Unauthorized")
+		return false
+	case s.apiKey != "" && apiKey != s.apiKey:
+		writeStatus(w, http.StatusForbidden, "Forbidden")
+		return false
+	}
+	return true
+}
+
+// admit counts the request against the application limit of the routing value and the method limit,
+// sets the rate limit headers and answers 429 when a limit is exhausted or a 429 was scripted.
+func (s *Server) admit(w http.ResponseWriter, routing, method string, now time.Time) bool {
+	app := limitCounterFor(s.appLimits, routing, s.appSpec)
+	methodLimit := limitCounterFor(s.methodLimits, routing+method, s.methodSpec)
+
+	if len(s.forcedLimits) > 0 {
+		forced := s.forcedLimits[0]
+		s.forcedLimits = s.forcedLimits[1:]
+		setRateLimitHeaders(w.Header(), app, methodLimit)
+		writeRateLimited(w, forced.retryAfter, forced.limitType)
+		return false
+	}
+	if wait := app.wait(now); wait > 0 {
+		setRateLimitHeaders(w.Header(), app, methodLimit)
+		writeRateLimited(w, wait, LimitTypeApplication)
+		return false
+	}
+	if wait := methodLimit.wait(now); wait > 0 {
+		setRateLimitHeaders(w.Header(), app, methodLimit)
+		writeRateLimited(w, wait, LimitTypeMethod)
+		return false
+	}
+	app.take()
+	methodLimit.take()
+	setRateLimitHeaders(w.Header(), app, methodLimit)
+	return true
+}
+
+func limitCounterFor(counters map[string]*limitCounter, key, spec string) *limitCounter {
+	counter, ok := counters[key]
+	if !ok {
+		// The spec was validated by WithRateLimits.
+		counter, _ = newLimitCounter(spec)
+		counters[key] = counter
+	}
+	return counter
+}
+
+func setRateLimitHeaders(header http.Header, app, method *limitCounter) {
+	if app.spec != "" {
+		header.Set("X-App-Rate-Limit", app.spec)
+		header.Set("X-App-Rate-Limit-Count", app.counts())
+	}
+	if method.spec != "" {
+		header.Set("X-Method-Rate-Limit", method.spec)
+		header.Set("X-Method-Rate-Limit-Count", method.counts())
+	}
+}
+
+func writeRateLimited(w http.ResponseWriter, retryAfter time.Duration, limitType string) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+	if limitType != LimitTypeService {
+		w.Header().Set("X-Rate-Limit-Type", limitType)
+	}
+	writeStatus(w, http.StatusTooManyRequests, "Rate limit exceeded")
+}
+
+func (s *Server) activeGame(routing, puuid string, now time.Time) (riot.LiveGame, bool) {
+	for _, game := range s.games {
+		if game.PlatformID != "" && !strings.EqualFold(game.PlatformID, routing) {
+			continue
+		}
+		if slices.ContainsFunc(game.Players, func(p riot.LiveGamePlayer) bool { return p.PUUID == puuid }) {
+			if game.GameStartTime > 0 {
+				game.GameLength = max(0, now.Sub(time.UnixMilli(game.GameStartTime)).Milliseconds()/1000)
+			}
+			return game, true
+		}
+	}
+	return riot.LiveGame{}, false
+}
+
+// matchIDs lists the matches of an account newest first, with the match-v5 start, count, queue,
+// startTime and endTime filters.
+func (s *Server) matchIDs(puuid string, r *http.Request) []string {
+	query := r.URL.Query()
+	queryInt := func(name string, fallback int64) int64 {
+		if value, err := strconv.ParseInt(query.Get(name), 10, 64); err == nil {
+			return value
+		}
+		return fallback
+	}
+	queueID, startTime, endTime := queryInt("queue", 0), queryInt("startTime", 0), queryInt("endTime", 0)
+
+	matches := make([]riot.MatchDetail, 0)
+	for _, match := range s.matches {
+		started := match.Info.GameStartTimestamp / 1000
+		switch {
+		case !slices.Contains(match.Metadata.Players, puuid) && riot.MatchPlayerByPUUID(match.Info.Players, puuid) == nil,
+			queueID > 0 && int64(match.Info.QueueID) != queueID,
+			startTime > 0 && started < startTime,
+			endTime > 0 && started > endTime:
+			continue
+		}
+		matches = append(matches, match)
+	}
+	slices.SortFunc(matches, func(a, b riot.MatchDetail) int {
+		if c := cmp.Compare(b.Info.GameStartTimestamp, a.Info.GameStartTimestamp); c != 0 {
+			return c
+		}
+		return strings.Compare(b.Metadata.MatchID, a.Metadata.MatchID)
+	})
+
+	start := int(min(max(queryInt("start", 0), 0), int64(len(matches))))
+	count := int(min(max(queryInt("count", defaultMatchIDsCount), 0), int64(len(matches)-start)))
+	ids := make([]string, 0, count)
+	for _, match := range matches[start : start+count] {
+		ids = append(ids, match.Metadata.MatchID)
+	}
+	return ids
+}
+
+// matchFromLiveGame builds the match-v5 result of a live game ending at end.
+func matchFromLiveGame(game riot.LiveGame, end time.Time) riot.MatchDetail {
+	start := game.GameStartTime
+	if start == 0 {
+		start = end.UnixMilli()
+	}
+	match := riot.MatchDetail{
+		Metadata: riot.MatchMetadata{MatchID: riot.BuildMatchID(game.PlatformID, game.GameID)},
+		Info: riot.MatchInfo{
+			GameDuration:       max(0, (end.UnixMilli()-start)/1000),
+			GameStartTimestamp: start,
+			GameEndTimestamp:   end.UnixMilli(),
+			QueueID:            game.GameQueueConfigID,
+			Teams:              []riot.MatchTeam{{TeamID: 100, Win: true}, {TeamID: 200}},
+		},
+	}
+	for _, player := range game.Players {
+		gameName, tagLine, _ := strings.Cut(player.RiotID, "#")
+		match.Metadata.Players = append(match.Metadata.Players, player.PUUID)
+		match.Info.Players = append(match.Info.Players, riot.MatchPlayer{
+			PUUID:          player.PUUID,
+			RiotIDGameName: gameName,
+			RiotIDTagline:  tagLine,
+			ProfileIconID:  player.ProfileIconID,
+			TeamID:         player.TeamID,
+			Win:            player.TeamID == 100,
+			ChampionID:     player.ChampionID,
+			Summoner1ID:    player.Spell1ID,
+			Summoner2ID:    player.Spell2ID,
+		})
+	}
+	return match
+}
+
+func (s *Server) handleStartGame(w http.ResponseWriter, r *http.Request) {
+	var game riot.LiveGame
+	if err := json.NewDecoder(r.Body).Decode(&game); err != nil || game.GameID <= 0 {
+		writeStatus(w, http.StatusBadRequest, "Body must be a spectator-v5 game with a gameId")
+		return
+	}
+	s.StartGame(game)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleEndGame(w http.ResponseWriter, r *http.Request) {
+	gameID, err := strconv.ParseInt(r.PathValue("gameID"), 10, 64)
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+	var match riot.MatchDetail
+	if err := json.NewDecoder(r.Body).Decode(&match); err != nil && !errors.Is(err, io.EOF) {
+		writeStatus(w, http.StatusBadRequest, "Body must be empty or a match-v5 match")
+		return
+	}
+	match, ok := s.EndGame(gameID, match)
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "Game not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, match)
+}
+
+func (s *Server) handleSetLeagueEntries(w http.ResponseWriter, r *http.Request) {
+	var entries []riot.LeagueEntry
+	if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		writeStatus(w, http.StatusBadRequest, "Body must be a list of league-v4 entries")
+		return
+	}
+	s.SetLeagueEntries(r.PathValue("puuid"), entries)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleLimitNext(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Count             int    `json:"count"`
+		RetryAfterSeconds int    `json:"retryAfterSeconds"`
+		Type              string `json:"type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Count <= 0 {
+		writeStatus(w, http.StatusBadRequest, "Body must have a positive count")
+		return
+	}
+	limitType := body.Type
+	if limitType == "" {
+		limitType = LimitTypeMethod
+	}
+	s.LimitNext(body.Count, time.Duration(body.RetryAfterSeconds)*time.Second, limitType)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeStatus writes an error body shaped like Riot's.
+func writeStatus(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"status": map[string]any{"message": message, "status_code": status}})
+}
@@ -0,0 +1,162 @@
+package riotfake
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+)
+
+func newTestClient(t *testing.T, opts ...Option) (*Server, *riot.Client) {
+	t.Helper()
+	fixtures, err := LoadFixtures("testdata")
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	server, err := NewServer(fixtures, opts...)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return server, riot.NewClient(riot.WithAPIKey("test-key"), riot.WithBaseURLTemplate(httpServer.URL+"/{routing}"))
+}
+
+func TestServerServesFixtures(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := t.Context()
+
+	account, err := client.FetchAccountByRiotID(ctx, "br1", "bekko", "EKKO")
+	if err != nil || account.PUUID != "puuid-bekko" {
+		t.Fatalf("FetchAccountByRiotID() = %+v, %v", account, err)
+	}
+	if _, err := client.FetchAccountByRiotID(ctx, "br1", "Nobody", "BR1"); !riot.IsAccountByRiotIDNotFound(err) {
+		t.Fatalf("FetchAccountByRiotID(unknown) error = %v, want not found", err)
+	}
+	if summoner, err := client.FetchSummonerByPUUID(ctx, "br1", "puuid-ahri"); err != nil || summoner.SummonerLevel != 87 {
+		t.Fatalf("FetchSummonerByPUUID() = %+v, %v", summoner, err)
+	}
+	if entries, err := client.FetchLeagueEntriesByPUUID(ctx, "br1", "puuid-bekko"); err != nil || len(entries) != 1 || entries[0].LeaguePoints != 36 {
+		t.Fatalf("FetchLeagueEntriesByPUUID() = %+v, %v", entries, err)
+	}
+	if rotation, err := client.FetchChampionRotation(ctx, "br1"); err != nil || len(rotation.FreeChampionIDs) != 4 {
+		t.Fatalf("FetchChampionRotation() = %+v, %v", rotation, err)
+	}
+	if ids, err := client.FetchMatchIDsByPUUID(ctx, "americas", "puuid-ahri", riot.MatchIDsFilter{QueueID: 420}); err != nil || len(ids) != 1 || ids[0] != "BR1_3001" {
+		t.Fatalf("FetchMatchIDsByPUUID() = %v, %v", ids, err)
+	}
+	if match, err := client.FetchMatchByID(ctx, "americas", "BR1_3001"); err != nil || len(match.Info.Players) != 2 {
+		t.Fatalf("FetchMatchByID() = %+v, %v", match, err)
+	}
+}
+
+func TestServerScriptsGameLifecycle(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := t.Context()
+
+	_, err := client.FetchActiveGameBySummoner(ctx, "br1", "puuid-bekko")
+	if statusErr, ok := errors.AsType[*riot.HTTPStatusError](err); !ok || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("FetchActiveGameBySummoner() before the game error = %v, want 404", err)
+	}
+
+	server.StartGame(riot.LiveGame{
+		GameID:            4001,
+		PlatformID:        "BR1",
+		GameQueueConfigID: 420,
+		GameStartTime:     time.Now().Add(-10 * time.Minute).UnixMilli(),
+		Players: []riot.LiveGamePlayer{
+			{PUUID: "puuid-bekko", TeamID: 200, ChampionID: 245, RiotID: "Bekko#Ekko"},
+			{PUUID: "puuid-ahri", TeamID: 100, ChampionID: 103, RiotID: "Ahri#BR1"},
+		},
+	})
+	game, err := client.FetchActiveGameBySummoner(ctx, "br1", "puuid-bekko")
+	if err != nil || game.GameID != 4001 || game.GameLength < 590 {
+		t.Fatalf("FetchActiveGameBySummoner() = %+v, %v", game, err)
+	}
+	if _, err := client.FetchActiveGameBySummoner(ctx, "na1", "puuid-bekko"); err == nil {
+		t.Fatalf("FetchActiveGameBySummoner() on another platform error = nil")
+	}
+
+	ended, ok := server.EndGame(4001, riot.MatchDetail{})
+	if !ok || ended.Metadata.MatchID != "BR1_4001" {
+		t.Fatalf("EndGame() = %+v, %v", ended, ok)
+	}
+	if _, err := client.FetchActiveGameBySummoner(ctx, "br1", "puuid-bekko"); err == nil {
+		t.Fatalf("FetchActiveGameBySummoner() after the game error = nil")
+	}
+	ids, err := client.FetchMatchIDsByPUUID(ctx, "americas", "puuid-bekko", riot.MatchIDsFilter{Count: 1})
+	if err != nil || len(ids) != 1 || ids[0] != "BR1_4001" {
+		t.Fatalf("FetchMatchIDsByPUUID() = %v, %v, want the ended game first", ids, err)
+	}
+	match, err := client.FetchMatchByID(ctx, "americas", "BR1_4001")
+	if err != nil || match.Info.QueueID != 420 || match.Info.GameDuration < 590 {
+		t.Fatalf("FetchMatchByID() = %+v, %v", match.Info, err)
+	}
+	if player := riot.MatchPlayerByPUUID(match.Info.Players, "puuid-bekko"); player == nil || player.Win || player.RiotIDGameName != "Bekko" {
+		t.Fatalf("ended match player = %+v", player)
+	}
+}
+
+func TestServerRateLimits(t *testing.T) {
+	server, client := newTestClient(t, WithRateLimits("2:1", ""))
+	ctx := t.Context()
+
+	server.LimitNext(1, time.Second, LimitTypeMethod)
+	start := time.Now()
+	if _, err := client.FetchSummonerByPUUID(ctx, "br1", "puuid-bekko"); err != nil {
+		t.Fatalf("FetchSummonerByPUUID() after a scripted 429 error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("client retried after %v, want it to honor Retry-After", elapsed)
+	}
+	if got := server.Requests(); got != 2 {
+		t.Fatalf("requests = %d, want the 429 and the retry", got)
+	}
+	if got := client.RequestRate("https://br1.api.riotgames.com/lol/summoner/v4/summoners/by-puuid/x"); got != 2 {
+		t.Fatalf("RequestRate() = %v, want the 2:1 limit learned from the headers", got)
+	}
+}
+
+func TestServerRejectsWrongAPIKey(t *testing.T) {
+	_, client := newTestClient(t, WithAPIKey("another-key"))
+	_, err := client.FetchChampionRotation(t.Context(), "br1")
+	if statusErr, ok := errors.AsType[*riot.HTTPStatusError](err); !ok || statusErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("FetchChampionRotation() error = %v, want 403", err)
+	}
+}
+
+func TestServerAdminEndpoints(t *testing.T) {
+	server, err := NewServer(Fixtures{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	do := func(method, path, body string) int {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), method, path, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	if code := do(http.MethodPost, "/riotfake/games", `{"gameId": 7, "platformId": "BR1", "participants": [{"puuid": "p1", "teamId": 100}]}`); code != http.StatusNoContent {
+		t.Fatalf("start game status = %d", code)
+	}
+	if code := do(http.MethodPost, "/riotfake/games/7/end", ""); code != http.StatusOK {
+		t.Fatalf("end game status = %d", code)
+	}
+	if code := do(http.MethodPost, "/riotfake/games/7/end", ""); code != http.StatusNotFound {
+		t.Fatalf("end unknown game status = %d, want 404", code)
+	}
+	if code := do(http.MethodPost, "/riotfake/rate-limit", `{"count": 1, "retryAfterSeconds": 5}`); code != http.StatusNoContent {
+		t.Fatalf("rate limit status = %d", code)
+	}
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/americas/lol/match/v5/matches/BR1_7", nil)
+	req.Header.Set("X-Riot-Token", "key")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "5" || rec.Header().Get("X-Rate-Limit-Type") != LimitTypeMethod {
+		t.Fatalf("scripted 429 = %d %v", rec.Code, rec.Header())
+	}
+}
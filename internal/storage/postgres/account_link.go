@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/jackc/pgx/v5"
+)
+
+// AccountLink maps a Discord user to the Riot account they proved they own.
+type AccountLink struct {
+	UserID         string
+	PlatformRegion string
+	PUUID          string
+	GameName       string
+	TagLine        string
+	VerifiedAt     time.Time
+}
+
+func (l AccountLink) RiotID() string {
+	return riot.FormatRiotID(l.GameName, l.TagLine)
+}
+
+// AccountLinkVerification is a pending /link request: the user must switch to ProfileIconID before ExpiresAt.
+type AccountLinkVerification struct {
+	UserID         string
+	PlatformRegion string
+	PUUID          string
+	GameName       string
+	TagLine        string
+	ProfileIconID  int
+	ExpiresAt      time.Time
+}
+
+func (v AccountLinkVerification) RiotID() string {
+	return riot.FormatRiotID(v.GameName, v.TagLine)
+}
+
+func (db *Database) CreateAccountLinkTable(ctx context.Context) error {
+	return db.withTx(ctx, func(tx pgx.Tx) error {
+		b := &pgx.Batch{}
+		b.Queue(createAccountLinksSQL)
+		b.Queue(createAccountLinksPUUIDIdxSQL)
+		b.Queue(createAccountLinkVerificationsSQL)
+		b.Queue(createAccountProfilesSQL)
+		if err := executeBatch(ctx, tx, b); err != nil {
+			return fmt.Errorf("create account link schema: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpsertAccountLinkVerification starts or restarts the verification of a user, leaving any existing link untouched.
+func (db *Database) UpsertAccountLinkVerification(ctx context.Context, v AccountLinkVerification) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	v.UserID = strings.TrimSpace(v.UserID)
+	v.PlatformRegion = riot.NormalizePlatformRegion(v.PlatformRegion)
+	v.PUUID = strings.TrimSpace(v.PUUID)
+	v.GameName = strings.TrimSpace(v.GameName)
+	v.TagLine = strings.TrimPrefix(strings.TrimSpace(v.TagLine), "#")
+	query := `
+	INSERT INTO account_link_verifications (user_id, platform_region, puuid, game_name, tag_line, profile_icon_id, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (user_id) DO UPDATE
+	SET platform_region = excluded.platform_region,
+		puuid = excluded.puuid,
+		game_name = excluded.game_name,
+		tag_line = excluded.tag_line,
+		profile_icon_id = excluded.profile_icon_id,
+		expires_at = excluded.expires_at`
+	if _, err := db.pool.Exec(ctx, query, v.UserID, v.PlatformRegion, v.PUUID, v.GameName, v.TagLine, v.ProfileIconID, v.ExpiresAt.UTC()); err != nil {
+		return fmt.Errorf("upsert account link verification %s: %w", v.UserID, err)
+	}
+	return nil
+}
+
+func (db *Database) AccountLinkVerification(ctx context.Context, userID string) (AccountLinkVerification, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return AccountLinkVerification{}, false, err
+	}
+
+	userID = strings.TrimSpace(userID)
+	query := `
+	SELECT user_id, platform_region, puuid, game_name, tag_line, profile_icon_id, expires_at
+	FROM account_link_verifications
+	WHERE user_id = $1`
+	var v AccountLinkVerification
+	err := db.pool.QueryRow(ctx, query, userID).Scan(&v.UserID, &v.PlatformRegion, &v.PUUID, &v.GameName, &v.TagLine, &v.ProfileIconID, &v.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return AccountLinkVerification{}, false, nil
+	}
+	if err != nil {
+		return AccountLinkVerification{}, false, fmt.Errorf("get account link verification %s: %w", userID, err)
+	}
+	v.ExpiresAt = v.ExpiresAt.UTC()
+	return v, true, nil
+}
+
+// CompleteAccountLink turns the pending verification of a user into their link.
+// A Riot account can only be linked to one Discord user, so a previous owner loses the link.
+func (db *Database) CompleteAccountLink(ctx context.Context, userID string, verifiedAt time.Time) (AccountLink, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return AccountLink{}, false, err
+	}
+
+	userID = strings.TrimSpace(userID)
+	verifiedAt = utcNowIfZero(verifiedAt)
+	var link AccountLink
+	err := db.withTx(ctx, func(tx pgx.Tx) error {
+		query := `
+		DELETE FROM account_link_verifications
+		WHERE user_id = $1
+		RETURNING user_id, platform_region, puuid, game_name, tag_line`
+		if err := tx.QueryRow(ctx, query, userID).Scan(&link.UserID, &link.PlatformRegion, &link.PUUID, &link.GameName, &link.TagLine); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM account_links WHERE platform_region = $1 AND puuid = $2 AND user_id <> $3`, link.PlatformRegion, link.PUUID, userID); err != nil {
+			return err
+		}
+		link.VerifiedAt = verifiedAt
+		query = `
+		INSERT INTO account_links (user_id, platform_region, puuid, game_name, tag_line, verified_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET platform_region = excluded.platform_region,
+			puuid = excluded.puuid,
+			game_name = excluded.game_name,
+			tag_line = excluded.tag_line,
+			verified_at = excluded.verified_at`
+		_, err := tx.Exec(ctx, query, link.UserID, link.PlatformRegion, link.PUUID, link.GameName, link.TagLine, link.VerifiedAt)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return AccountLink{}, false, nil
+	}
+	if err != nil {
+		return AccountLink{}, false, fmt.Errorf("complete account link %s: %w", userID, err)
+	}
+	return link, true, nil
+}
+
+func (db *Database) AccountLinkByUser(ctx context.Context, userID string) (AccountLink, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return AccountLink{}, false, err
+	}
+
+	userID = strings.TrimSpace(userID)
+	query := `
+	SELECT user_id, platform_region, puuid, game_name, tag_line, verified_at
+	FROM account_links
+	WHERE user_id = $1`
+	var link AccountLink
+	err := db.pool.QueryRow(ctx, query, userID).Scan(&link.UserID, &link.PlatformRegion, &link.PUUID, &link.GameName, &link.TagLine, &link.VerifiedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return AccountLink{}, false, nil
+	}
+	if err != nil {
+		return AccountLink{}, false, fmt.Errorf("get account link %s: %w", userID, err)
+	}
+	link.VerifiedAt = link.VerifiedAt.UTC()
+	return link, true, nil
+}
+
+// UpdateAccountLinkRiotID refreshes the stored Riot ID after the linked account was renamed.
+func (db *Database) UpdateAccountLinkRiotID(ctx context.Context, userID, gameName, tagLine string) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	userID = strings.TrimSpace(userID)
+	gameName, tagLine = strings.TrimSpace(gameName), strings.TrimPrefix(strings.TrimSpace(tagLine), "#")
+	query := `
+	UPDATE account_links
+	SET game_name = $2,
+		tag_line = $3
+	WHERE user_id = $1`
+	if _, err := db.pool.Exec(ctx, query, userID, gameName, tagLine); err != nil {
+		return fmt.Errorf("update account link riot id %s: %w", userID, err)
+	}
+	return nil
+}
+
+// RemoveAccountLink drops the link and any pending verification of a user.
+func (db *Database) RemoveAccountLink(ctx context.Context, userID string) (bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return false, err
+	}
+
+	userID = strings.TrimSpace(userID)
+	query := `
+	WITH pending AS (
+		DELETE FROM account_link_verifications
+		WHERE user_id = $1
+	)
+	DELETE FROM account_links
+	WHERE user_id = $1`
+	result, err := db.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return false, fmt.Errorf("remove account link %s: %w", userID, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+const createAccountLinksSQL = `
+CREATE TABLE IF NOT EXISTS account_links (
+    user_id text PRIMARY KEY,
+    platform_region text NOT NULL,
+    puuid text NOT NULL,
+    game_name text NOT NULL,
+    tag_line text NOT NULL,
+    verified_at timestamptz NOT NULL DEFAULT now()
+)`
+
+const createAccountLinksPUUIDIdxSQL = `
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_links_puuid
+ON account_links (platform_region, puuid)`
+
+const createAccountLinkVerificationsSQL = `
+CREATE TABLE IF NOT EXISTS account_link_verifications (
+    user_id text PRIMARY KEY,
+    platform_region text NOT NULL,
+    puuid text NOT NULL,
+    game_name text NOT NULL,
+    tag_line text NOT NULL,
+    profile_icon_id int NOT NULL,
+    expires_at timestamptz NOT NULL
+)`
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/jackc/pgx/v5"
+)
+
+// Sources of a resolved user account, from most to least trusted.
+const (
+	UserAccountSourceLink    = "link"
+	UserAccountSourceProfile = "profile"
+	UserAccountSourceTrack   = "track"
+)
+
+// AccountProfile is the Riot ID a Discord user registered with /profile set. Unlike AccountLink it is not verified.
+type AccountProfile struct {
+	UserID         string
+	PlatformRegion string
+	PUUID          string
+	GameName       string
+	TagLine        string
+}
+
+// UserAccount is the Riot account shown for a Discord user and where it came from.
+type UserAccount struct {
+	PlatformRegion string
+	PUUID          string
+	GameName       string
+	TagLine        string
+	Source         string
+}
+
+func (a UserAccount) RiotID() string {
+	return riot.FormatRiotID(a.GameName, a.TagLine)
+}
+
+func (db *Database) SetAccountProfile(ctx context.Context, profile AccountProfile) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	profile.UserID = strings.TrimSpace(profile.UserID)
+	profile.PlatformRegion = riot.NormalizePlatformRegion(profile.PlatformRegion)
+	profile.PUUID = strings.TrimSpace(profile.PUUID)
+	profile.GameName = strings.TrimSpace(profile.GameName)
+	profile.TagLine = strings.TrimPrefix(strings.TrimSpace(profile.TagLine), "#")
+	query := `
+	INSERT INTO account_profiles (user_id, platform_region, puuid, game_name, tag_line)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (user_id) DO UPDATE
+	SET platform_region = excluded.platform_region,
+		puuid = excluded.puuid,
+		game_name = excluded.game_name,
+		tag_line = excluded.tag_line,
+		updated_at = now()`
+	if _, err := db.pool.Exec(ctx, query, profile.UserID, profile.PlatformRegion, profile.PUUID, profile.GameName, profile.TagLine); err != nil {
+		return fmt.Errorf("set account profile %s: %w", profile.UserID, err)
+	}
+	return nil
+}
+
+func (db *Database) RemoveAccountProfile(ctx context.Context, userID string) (bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return false, err
+	}
+
+	userID = strings.TrimSpace(userID)
+	result, err := db.pool.Exec(ctx, `DELETE FROM account_profiles WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("remove account profile %s: %w", userID, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ResolveUserAccount picks the account of a Discord user: the verified /link account first, then the
+// /profile set Riot ID, then the latest account the user added with /track add in guildID.
+func (db *Database) ResolveUserAccount(ctx context.Context, guildID, userID string) (UserAccount, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return UserAccount{}, false, err
+	}
+
+	guildID, userID = strings.TrimSpace(guildID), strings.TrimSpace(userID)
+	query := `
+	SELECT platform_region, puuid, game_name, tag_line, source
+	FROM (
+		SELECT platform_region, puuid, game_name, tag_line, 'link' AS source, 1 AS priority, verified_at AS seen_at
+		FROM account_links
+		WHERE user_id = $2
+		UNION ALL
+		SELECT platform_region, puuid, game_name, tag_line, 'profile', 2, updated_at
+		FROM account_profiles
+		WHERE user_id = $2
+		UNION ALL
+		SELECT platform_region, puuid, game_name, tag_line, 'track', 3, added_at
+		FROM track_accounts
+		WHERE guild_id = $1
+			AND $1 <> ''
+			AND added_by = $2
+	) candidates
+	ORDER BY priority, seen_at DESC
+	LIMIT 1`
+	var account UserAccount
+	err := db.pool.QueryRow(ctx, query, guildID, userID).Scan(&account.PlatformRegion, &account.PUUID, &account.GameName, &account.TagLine, &account.Source)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return UserAccount{}, false, nil
+	}
+	if err != nil {
+		return UserAccount{}, false, fmt.Errorf("resolve user account %s/%s: %w", guildID, userID, err)
+	}
+	return account, true, nil
+}
+
+const createAccountProfilesSQL = `
+CREATE TABLE IF NOT EXISTS account_profiles (
+    user_id text PRIMARY KEY,
+    platform_region text NOT NULL,
+    puuid text NOT NULL,
+    game_name text NOT NULL,
+    tag_line text NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now()
+)`
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *Database) CreateChampionMasteryTable(ctx context.Context) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS riot_champion_masteries (
+		platform_region text NOT NULL,
+		puuid text NOT NULL,
+		total_score int NOT NULL DEFAULT 0,
+		top_masteries jsonb NOT NULL,
+		fetched_at timestamptz NOT NULL,
+		expires_at timestamptz NOT NULL,
+		PRIMARY KEY (platform_region, puuid)
+	)`
+	return db.createTable(ctx, query, "create champion mastery schema")
+}
+
+func (db *Database) UpsertChampionMasteries(ctx context.Context, platformRegion, puuid string, masteries []riot.ChampionMastery, score int, fetchedAt, expiresAt time.Time) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	platformRegion = riot.NormalizePlatformRegion(platformRegion)
+	puuid = strings.TrimSpace(puuid)
+	if masteries == nil {
+		masteries = []riot.ChampionMastery{}
+	}
+	payload, err := json.Marshal(masteries)
+	if err != nil {
+		return fmt.Errorf("marshal champion masteries %s/%s: %w", platformRegion, puuid, err)
+	}
+	fetchedAt = utcNowIfZero(fetchedAt)
+	if expiresAt.IsZero() {
+		expiresAt = fetchedAt.Add(time.Hour)
+	}
+
+	query := `
+	INSERT INTO riot_champion_masteries (platform_region, puuid, total_score, top_masteries, fetched_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (platform_region, puuid) DO UPDATE
+	SET total_score = excluded.total_score,
+		top_masteries = excluded.top_masteries,
+		fetched_at = excluded.fetched_at,
+		expires_at = excluded.expires_at`
+	if _, err := db.pool.Exec(ctx, query, platformRegion, puuid, score, payload, fetchedAt, expiresAt.UTC()); err != nil {
+		return fmt.Errorf("upsert champion masteries %s/%s: %w", platformRegion, puuid, err)
+	}
+	return nil
+}
+
+func (db *Database) GetChampionMasteries(ctx context.Context, platformRegion, puuid string) ([]riot.ChampionMastery, int, time.Time, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, 0, time.Time{}, false, err
+	}
+
+	platformRegion = riot.NormalizePlatformRegion(platformRegion)
+	puuid = strings.TrimSpace(puuid)
+	query := `
+	SELECT total_score, top_masteries, expires_at
+	FROM riot_champion_masteries
+	WHERE platform_region = $1
+	AND puuid = $2`
+	var (
+		score     int
+		payload   []byte
+		expiresAt time.Time
+	)
+	err := db.pool.QueryRow(ctx, query, platformRegion, puuid).Scan(&score, &payload, &expiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, 0, time.Time{}, false, nil
+		}
+		return nil, 0, time.Time{}, false, fmt.Errorf("get champion masteries %s/%s: %w", platformRegion, puuid, err)
+	}
+
+	var masteries []riot.ChampionMastery
+	if err := json.Unmarshal(payload, &masteries); err != nil {
+		return nil, 0, time.Time{}, false, fmt.Errorf("decode champion masteries %s/%s: %w", platformRegion, puuid, err)
+	}
+	return masteries, score, expiresAt.UTC(), true, nil
+}
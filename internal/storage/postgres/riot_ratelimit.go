@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+)
+
+func (db *Database) CreateRiotRateLimitTable(ctx context.Context) error {
+	return db.createTable(ctx, createRiotRateLimitWindowsSQL, "create riot_rate_limit_windows table")
+}
+
+type riotRateLimitRow struct {
+	Key     string
+	Seconds int
+	Limit   int
+}
+
+// ReserveRiotRequest implements riot.LimiterBackend with fixed windows shared by every bot replica.
+// A reservation is one statement that locks the window rows, checks them and counts the request in
+// all of them or none, so replicas never both take the last slot. Window rows are created the first
+// time a key is seen.
+func (db *Database) ReserveRiotRequest(ctx context.Context, buckets []riot.RateLimitBucket) (time.Duration, error) {
+	if err := db.ensureReady(); err != nil {
+		return 0, err
+	}
+	rows := riotRateLimitRows(buckets)
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	keys := make([]string, 0, len(rows))
+	seconds := make([]int32, 0, len(rows))
+	limits := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.Key)
+		seconds = append(seconds, int32(row.Seconds))
+		limits = append(limits, int32(row.Limit))
+	}
+
+	found, wait, err := db.reserveRiotRateLimitWindows(ctx, keys, seconds, limits)
+	if err == nil && found < len(rows) {
+		if _, err := db.pool.Exec(ctx, insertRiotRateLimitWindowsSQL, keys, seconds); err != nil {
+			return 0, fmt.Errorf("insert riot rate limit windows: %w", err)
+		}
+		_, wait, err = db.reserveRiotRateLimitWindows(ctx, keys, seconds, limits)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reserve riot request: %w", err)
+	}
+	return wait, nil
+}
+
+// reserveRiotRateLimitWindows runs one reservation. It reports how many of the windows exist and how
+// long to wait when nothing was counted, either because a window is full or because one is missing.
+func (db *Database) reserveRiotRateLimitWindows(ctx context.Context, keys []string, seconds, limits []int32) (int, time.Duration, error) {
+	var found, reserved int
+	var waitSecs float64
+	if err := db.pool.QueryRow(ctx, reserveRiotRateLimitWindowsSQL, keys, seconds, limits).Scan(&found, &waitSecs, &reserved); err != nil {
+		return 0, 0, err
+	}
+	if reserved > 0 {
+		return found, 0, nil
+	}
+	return found, max(time.Duration(waitSecs*float64(time.Second)), time.Millisecond), nil
+}
+
+type riotRateLimitKey struct {
+	Key     string
+	Seconds int
+}
+
+// riotRateLimitRows flattens the buckets into one row per key and window, keeping the strictest limit
+// of duplicated windows, sorted so concurrent reservations lock rows in the same order.
+func riotRateLimitRows(buckets []riot.RateLimitBucket) []riotRateLimitRow {
+	byKey := map[riotRateLimitKey]int{}
+	for _, bucket := range buckets {
+		key := strings.TrimSpace(bucket.Key)
+		if key == "" {
+			continue
+		}
+		for _, window := range bucket.Windows {
+			if window.Requests <= 0 || window.Window <= 0 {
+				continue
+			}
+			k := riotRateLimitKey{Key: key, Seconds: int(math.Ceil(window.Window.Seconds()))}
+			if current, ok := byKey[k]; !ok || window.Requests < current {
+				byKey[k] = window.Requests
+			}
+		}
+	}
+	rows := make([]riotRateLimitRow, 0, len(byKey))
+	for k, limit := range byKey {
+		rows = append(rows, riotRateLimitRow{Key: k.Key, Seconds: k.Seconds, Limit: limit})
+	}
+	slices.SortFunc(rows, func(a, b riotRateLimitRow) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return a.Seconds - b.Seconds
+	})
+	return rows
+}
+
+const createRiotRateLimitWindowsSQL = `
+CREATE TABLE IF NOT EXISTS riot_rate_limit_windows (
+    limit_key text NOT NULL,
+    window_seconds int NOT NULL,
+    window_start timestamptz NOT NULL DEFAULT now(),
+    used int NOT NULL DEFAULT 0,
+    PRIMARY KEY (limit_key, window_seconds)
+)`
+
+const insertRiotRateLimitWindowsSQL = `
+INSERT INTO riot_rate_limit_windows (limit_key, window_seconds)
+SELECT w.limit_key, w.window_seconds
+FROM unnest($1::text[], $2::int[]) AS w(limit_key, window_seconds)
+ON CONFLICT (limit_key, window_seconds) DO NOTHING`
+
+// reserveRiotRateLimitWindowsSQL locks the window rows in key order, so concurrent reservations queue
+// instead of deadlocking, and counts the request only when every window exists and none is full. It returns how many of
+// the windows exist, how long until the fullest one resets, and how many were counted.
+const reserveRiotRateLimitWindowsSQL = `
+WITH w AS (
+	SELECT *
+	FROM unnest($1::text[], $2::int[], $3::int[]) AS w(limit_key, window_seconds, limit_requests)
+),
+locked AS (
+	SELECT r.limit_key,
+		r.window_seconds,
+		w.limit_requests,
+		r.used,
+		r.window_start + make_interval(secs => r.window_seconds) AS window_end
+	FROM riot_rate_limit_windows r
+	JOIN w
+	ON w.limit_key = r.limit_key
+		AND w.window_seconds = r.window_seconds
+	ORDER BY r.limit_key, r.window_seconds
+	FOR UPDATE OF r
+),
+blocked AS (
+	SELECT GREATEST(EXTRACT(EPOCH FROM window_end - now()), 0)::float8 AS wait
+	FROM locked
+	WHERE window_end > now()
+		AND used >= limit_requests
+),
+reserved AS (
+	UPDATE riot_rate_limit_windows r
+	SET window_start = CASE WHEN l.window_end <= now() THEN now() ELSE r.window_start END,
+		used = CASE WHEN l.window_end <= now() THEN 1 ELSE r.used + 1 END
+	FROM locked l
+	WHERE r.limit_key = l.limit_key
+		AND r.window_seconds = l.window_seconds
+		AND NOT EXISTS (SELECT 1 FROM blocked)
+		AND (SELECT count(*) FROM locked) = cardinality($1::text[])
+	RETURNING r.limit_key
+)
+SELECT (SELECT count(*) FROM locked)::int,
+	COALESCE((SELECT max(wait) FROM blocked), 0)::float8,
+	(SELECT count(*) FROM reserved)::int`
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+)
+
+func TestRiotRateLimitRows(t *testing.T) {
+	rows := riotRateLimitRows([]riot.RateLimitBucket{
+		{Key: "k:method", Windows: []riot.RateLimitWindow{{Requests: 50, Window: 10 * time.Second}}},
+		{Key: "k:app", Windows: []riot.RateLimitWindow{
+			{Requests: 100, Window: 2 * time.Minute},
+			{Requests: 20, Window: 500 * time.Millisecond},
+			{Requests: 10, Window: time.Second},
+			{Requests: 0, Window: time.Second},
+		}},
+		{Key: " ", Windows: []riot.RateLimitWindow{{Requests: 1, Window: time.Second}}},
+	})
+
+	want := []riotRateLimitRow{
+		{Key: "k:app", Seconds: 1, Limit: 10},
+		{Key: "k:app", Seconds: 120, Limit: 100},
+		{Key: "k:method", Seconds: 10, Limit: 50},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+	for idx := range want {
+		if rows[idx] != want[idx] {
+			t.Fatalf("rows[%d] = %+v, want %+v", idx, rows[idx], want[idx])
+		}
+	}
+}
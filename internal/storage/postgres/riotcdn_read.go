@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -85,6 +86,57 @@ func (db *Database) QueueDisplayByID(ctx context.Context, queueID int) (QueueDis
 	return queryDisplayByID[QueueDisplay](ctx, db, queueID, query, "query queue display by id")
 }
 
+// SearchQueueDisplays returns named queues whose name or ID contains query, for autocomplete.
+func (db *Database) SearchQueueDisplays(ctx context.Context, query string, limit int) ([]QueueDisplay, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 25 {
+		limit = 25
+	}
+
+	sql := `
+	SELECT queue_id,
+		COALESCE(name, ''),
+		COALESCE(game_select_category, '')
+	FROM riot_cdn_queues
+	WHERE COALESCE(name, '') <> ''
+	AND ($1 = '' OR name ILIKE '%' || $1 || '%' OR queue_id::text = $1)
+	ORDER BY queue_id
+	LIMIT $2`
+	rows, err := db.pool.Query(ctx, sql, strings.TrimSpace(query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("search queue displays: %w", err)
+	}
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByPos[QueueDisplay])
+	if err != nil {
+		return nil, fmt.Errorf("collect queue displays: %w", err)
+	}
+	return out, nil
+}
+
+// QueueCategories lists the distinct game select categories known from the CDN queue data.
+func (db *Database) QueueCategories(ctx context.Context) ([]string, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT DISTINCT game_select_category
+	FROM riot_cdn_queues
+	WHERE COALESCE(game_select_category, '') <> ''
+	ORDER BY game_select_category`
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list queue categories: %w", err)
+	}
+	out, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("collect queue categories: %w", err)
+	}
+	return out, nil
+}
+
 func (db *Database) MapDisplayByID(ctx context.Context, mapID int) (MapDisplay, bool, error) {
 	query := `
 	SELECT map_id,
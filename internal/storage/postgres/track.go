@@ -11,10 +11,18 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// Post modes decide how a finished game is announced: as a reply to the live message or by editing it.
+const (
+	TrackPostModeReply = "reply"
+	TrackPostModeEdit  = "edit"
+)
+
 type TrackGuildConfig struct {
-	GuildID   string
-	ChannelID string
-	UpdatedAt time.Time
+	GuildID         string
+	ChannelID       string
+	PostMode        string
+	AnnounceRenames bool
+	UpdatedAt       time.Time
 }
 
 type TrackedAccount struct {
@@ -31,18 +39,49 @@ func (a TrackedAccount) RiotID() string {
 	return riot.FormatRiotID(a.NickName, a.TagLine)
 }
 
+// Sort orders of ListTrackedAccountsPage.
+const (
+	TrackAccountSortName   = "name"
+	TrackAccountSortDate   = "date"
+	TrackAccountSortRegion = "region"
+)
+
+// TrackedAccountPage selects a slice of the tracked accounts of a guild, optionally only those of Region.
+type TrackedAccountPage struct {
+	Sort   string
+	Region string
+	Offset int
+	Limit  int
+}
+
+var trackAccountOrderBy = map[string]string{
+	TrackAccountSortName:   "lower(game_name), lower(tag_line)",
+	TrackAccountSortDate:   "added_at DESC, lower(game_name), lower(tag_line)",
+	TrackAccountSortRegion: "platform_region, lower(game_name), lower(tag_line)",
+}
+
 func (db *Database) CreateTrackTable(ctx context.Context) error {
 	return db.withTx(ctx, func(tx pgx.Tx) error {
 		b := &pgx.Batch{}
 		b.Queue(createTrackGuildConfigSQL)
+		b.Queue(addTrackGuildConfigPostModeSQL)
+		b.Queue(addTrackGuildConfigAnnounceRenamesSQL)
 		b.Queue(createTrackAccountsSQL)
+		b.Queue(addTrackAccountsCatchUpSeenAtSQL)
 		b.Queue(createTrackAccountsLookupIdxSQL)
 		b.Queue(createTrackMatchNotificationsSQL)
+		b.Queue(addTrackMatchNotificationsCatchUpSQL)
+		b.Queue(createTrackMatchNotificationsPlayerIdxSQL)
 		b.Queue(createTrackMatchNotificationsPostIdxSQL)
 		b.Queue(createTrackMatchNotificationsMatchIDIdxSQL)
 		b.Queue(createTrackMatchNotificationsUpdatedAtIdxSQL)
 		b.Queue(createTrackMatchSnapshotsSQL)
 		b.Queue(createTrackMatchSnapshotsUpdatedAtIdxSQL)
+		b.Queue(createTrackRankSnapshotsSQL)
+		b.Queue(createTrackRankSnapshotsLookupIdxSQL)
+		b.Queue(createTrackRankSnapshotsMatchIdxSQL)
+		b.Queue(createTrackQueueFiltersSQL)
+		b.Queue(createTrackChannelRoutesSQL)
 		if err := executeBatch(ctx, tx, b); err != nil {
 			return fmt.Errorf("create track schema: %w", err)
 		}
@@ -68,6 +107,50 @@ func (db *Database) UpsertTrackGuildConfig(ctx context.Context, guildID, channel
 	return nil
 }
 
+// SetTrackGuildPostMode stores how post-game results are published for a configured guild.
+func (db *Database) SetTrackGuildPostMode(ctx context.Context, guildID, mode string) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	query := `
+	UPDATE track_guild_config
+	SET post_mode = $2,
+		updated_at = now()
+	WHERE guild_id = $1`
+	if _, err := db.pool.Exec(ctx, query, guildID, NormalizeTrackPostMode(mode)); err != nil {
+		return fmt.Errorf("set track post mode %s: %w", guildID, err)
+	}
+	return nil
+}
+
+// SetTrackGuildAnnounceRenames turns the "X is now known as Y" posts of a configured guild on or off.
+func (db *Database) SetTrackGuildAnnounceRenames(ctx context.Context, guildID string, enabled bool) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	query := `
+	UPDATE track_guild_config
+	SET announce_renames = $2,
+		updated_at = now()
+	WHERE guild_id = $1`
+	if _, err := db.pool.Exec(ctx, query, guildID, enabled); err != nil {
+		return fmt.Errorf("set track announce renames %s: %w", guildID, err)
+	}
+	return nil
+}
+
+// NormalizeTrackPostMode maps unknown or empty values to TrackPostModeReply.
+func NormalizeTrackPostMode(mode string) string {
+	if strings.EqualFold(strings.TrimSpace(mode), TrackPostModeEdit) {
+		return TrackPostModeEdit
+	}
+	return TrackPostModeReply
+}
+
 func (db *Database) DisableTrackGuildConfig(ctx context.Context, guildID string) error {
 	if err := db.ensureReady(); err != nil {
 		return err
@@ -92,17 +175,18 @@ func (db *Database) TrackGuildConfig(ctx context.Context, guildID string) (Track
 
 	guildID = strings.TrimSpace(guildID)
 	query := `
-	SELECT guild_id, channel_id, updated_at
+	SELECT guild_id, channel_id, post_mode, announce_renames, updated_at
 	FROM track_guild_config
 	WHERE guild_id = $1`
 	var cfg TrackGuildConfig
-	err := db.pool.QueryRow(ctx, query, guildID).Scan(&cfg.GuildID, &cfg.ChannelID, &cfg.UpdatedAt)
+	err := db.pool.QueryRow(ctx, query, guildID).Scan(&cfg.GuildID, &cfg.ChannelID, &cfg.PostMode, &cfg.AnnounceRenames, &cfg.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return TrackGuildConfig{}, false, nil
 		}
 		return TrackGuildConfig{}, false, fmt.Errorf("get track guild config %s: %w", guildID, err)
 	}
+	cfg.PostMode = NormalizeTrackPostMode(cfg.PostMode)
 	cfg.UpdatedAt = cfg.UpdatedAt.UTC()
 	return cfg, true, nil
 }
@@ -156,16 +240,97 @@ func (db *Database) RemoveTrackedAccount(ctx context.Context, guildID, riotID st
 	if err != nil {
 		return false, err
 	}
+	// Per-account queue filters go away with the account.
 	query := `
-	DELETE FROM track_accounts
+	WITH removed AS (
+		DELETE FROM track_accounts
+		WHERE guild_id = $1
+			AND lower(game_name) = lower($2)
+			AND lower(tag_line) = lower($3)
+		RETURNING guild_id, puuid
+	),
+	cleared AS (
+		DELETE FROM track_queue_filters f
+		USING removed r
+		WHERE f.guild_id = r.guild_id
+			AND f.puuid = r.puuid
+	)
+	SELECT count(*) FROM removed`
+	var removed int
+	if err := db.pool.QueryRow(ctx, query, guildID, gameName, tagLine).Scan(&removed); err != nil {
+		return false, fmt.Errorf("remove tracked account %s/%s#%s: %w", guildID, gameName, tagLine, err)
+	}
+	return removed > 0, nil
+}
+
+// UpdateTrackedAccountRiotID stores the current Riot ID of a tracked PUUID in every guild tracking it.
+// It returns the guilds whose stored name actually changed.
+func (db *Database) UpdateTrackedAccountRiotID(ctx context.Context, platformRegion, puuid, gameName, tagLine string) ([]string, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	platformRegion = riot.NormalizePlatformRegion(platformRegion)
+	puuid = strings.TrimSpace(puuid)
+	gameName, tagLine = strings.TrimSpace(gameName), strings.TrimPrefix(strings.TrimSpace(tagLine), "#")
+	if gameName == "" || tagLine == "" {
+		return nil, riot.ErrInvalidRiotID
+	}
+	query := `
+	UPDATE track_accounts
+	SET game_name = $3,
+		tag_line = $4
+	WHERE platform_region = $1
+		AND puuid = $2
+		AND (game_name <> $3 OR tag_line <> $4)
+	RETURNING guild_id`
+	rows, err := db.pool.Query(ctx, query, platformRegion, puuid, gameName, tagLine)
+	if err != nil {
+		return nil, fmt.Errorf("update tracked account riot id %s/%s: %w", platformRegion, puuid, err)
+	}
+	guildIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("collect renamed tracked accounts: %w", err)
+	}
+	return guildIDs, nil
+}
+
+// TrackedAccountByRiotID looks up a tracked account in a guild by its case-insensitive Riot ID.
+func (db *Database) TrackedAccountByRiotID(ctx context.Context, guildID, riotID string) (TrackedAccount, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return TrackedAccount{}, false, err
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	gameName, tagLine, err := riot.SplitRiotID(riotID)
+	if err != nil {
+		return TrackedAccount{}, false, err
+	}
+	query := `
+	SELECT guild_id,
+       platform_region,
+       puuid,
+       game_name,
+       tag_line,
+       added_by,
+       added_at
+	FROM track_accounts
 	WHERE guild_id = $1
 		AND lower(game_name) = lower($2)
-		AND lower(tag_line) = lower($3)`
-	result, err := db.pool.Exec(ctx, query, guildID, gameName, tagLine)
+		AND lower(tag_line) = lower($3)
+	LIMIT 1`
+	var account TrackedAccount
+	err = db.pool.QueryRow(ctx, query, guildID, gameName, tagLine).Scan(
+		&account.GuildID, &account.PlatformRegion, &account.PUUID, &account.NickName, &account.TagLine, &account.AddedBy, &account.AddedAt,
+	)
 	if err != nil {
-		return false, fmt.Errorf("remove tracked account %s/%s#%s: %w", guildID, gameName, tagLine, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TrackedAccount{}, false, nil
+		}
+		return TrackedAccount{}, false, fmt.Errorf("get tracked account %s/%s#%s: %w", guildID, gameName, tagLine, err)
 	}
-	return result.RowsAffected() > 0, nil
+	account.AddedAt = account.AddedAt.UTC()
+	return account, true, nil
 }
 
 func (db *Database) ListTrackedAccounts(ctx context.Context, guildID string, limit int) ([]TrackedAccount, error) {
@@ -206,6 +371,66 @@ func (db *Database) ListTrackedAccounts(ctx context.Context, guildID string, lim
 	return out, nil
 }
 
+// ListTrackedAccountsPage returns one page of tracked accounts and how many accounts match in total.
+func (db *Database) ListTrackedAccountsPage(ctx context.Context, guildID string, page TrackedAccountPage) ([]TrackedAccount, int, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, 0, err
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	region := riot.NormalizePlatformRegion(page.Region)
+	orderBy, ok := trackAccountOrderBy[strings.ToLower(strings.TrimSpace(page.Sort))]
+	if !ok {
+		orderBy = trackAccountOrderBy[TrackAccountSortName]
+	}
+	limit := page.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 25
+	}
+	offset := max(page.Offset, 0)
+
+	var total int
+	countQuery := `
+	SELECT count(*)
+	FROM track_accounts
+	WHERE guild_id = $1
+		AND ($2 = '' OR platform_region = $2)`
+	if err := db.pool.QueryRow(ctx, countQuery, guildID, region).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count tracked accounts for %s: %w", guildID, err)
+	}
+	if total == 0 || offset >= total {
+		return []TrackedAccount{}, total, nil
+	}
+
+	query := `
+	SELECT guild_id,
+       platform_region,
+       puuid,
+       game_name,
+       tag_line,
+       added_by,
+       added_at
+	FROM track_accounts
+	WHERE guild_id = $1
+		AND ($2 = '' OR platform_region = $2)
+	ORDER BY ` + orderBy + `
+	LIMIT $3 OFFSET $4`
+	rows, err := db.pool.Query(ctx, query, guildID, region, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list tracked accounts page for %s: %w", guildID, err)
+	}
+	defer rows.Close()
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByPos[TrackedAccount])
+	if err != nil {
+		return nil, 0, fmt.Errorf("collect tracked accounts page: %w", err)
+	}
+	for i := range out {
+		out[i].AddedAt = out[i].AddedAt.UTC()
+	}
+	return out, total, nil
+}
+
 const createTrackGuildConfigSQL = `
 CREATE TABLE IF NOT EXISTS track_guild_config (
     guild_id text PRIMARY KEY,
@@ -214,6 +439,14 @@ CREATE TABLE IF NOT EXISTS track_guild_config (
     updated_at timestamptz NOT NULL DEFAULT now()
 )`
 
+const addTrackGuildConfigPostModeSQL = `
+ALTER TABLE track_guild_config
+ADD COLUMN IF NOT EXISTS post_mode text NOT NULL DEFAULT 'reply'`
+
+const addTrackGuildConfigAnnounceRenamesSQL = `
+ALTER TABLE track_guild_config
+ADD COLUMN IF NOT EXISTS announce_renames boolean NOT NULL DEFAULT false`
+
 const createTrackAccountsSQL = `
 CREATE TABLE IF NOT EXISTS track_accounts (
     guild_id text NOT NULL REFERENCES track_guild_config (guild_id) ON DELETE CASCADE,
@@ -226,6 +459,10 @@ CREATE TABLE IF NOT EXISTS track_accounts (
     PRIMARY KEY (guild_id, puuid)
 )`
 
+const addTrackAccountsCatchUpSeenAtSQL = `
+ALTER TABLE track_accounts
+ADD COLUMN IF NOT EXISTS catch_up_seen_at timestamptz`
+
 const createTrackAccountsLookupIdxSQL = `
 CREATE INDEX IF NOT EXISTS track_accounts_lookup_idx
 ON track_accounts (guild_id, lower(game_name), lower(tag_line))`
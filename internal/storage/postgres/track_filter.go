@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	TrackQueueFilterInclude = "include"
+	TrackQueueFilterExclude = "exclude"
+
+	TrackQueueFilterKindQueue    = "queue"
+	TrackQueueFilterKindCategory = "category"
+)
+
+// TrackQueueFilter is one include/exclude rule. Rules with an empty PUUID apply to the whole guild;
+// rules with a PUUID replace the guild rules for that account.
+type TrackQueueFilter struct {
+	GuildID   string
+	PUUID     string
+	Kind      string
+	Value     string
+	Mode      string
+	CreatedAt time.Time
+}
+
+// AddTrackQueueFilter stores a rule, switching its mode when the same queue or category is already filtered.
+func (db *Database) AddTrackQueueFilter(ctx context.Context, filter TrackQueueFilter) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	normalizeTrackQueueFilter(&filter)
+	query := `
+	INSERT INTO track_queue_filters (guild_id, puuid, kind, value, mode)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (guild_id, puuid, kind, value) DO UPDATE
+	SET mode = excluded.mode,
+		created_at = now()`
+	if _, err := db.pool.Exec(ctx, query, filter.GuildID, filter.PUUID, filter.Kind, filter.Value, filter.Mode); err != nil {
+		return fmt.Errorf("add track queue filter %s/%s:%s: %w", filter.GuildID, filter.Kind, filter.Value, err)
+	}
+	return nil
+}
+
+func (db *Database) RemoveTrackQueueFilter(ctx context.Context, guildID, puuid, kind, value string) (bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return false, err
+	}
+
+	filter := TrackQueueFilter{GuildID: guildID, PUUID: puuid, Kind: kind, Value: value}
+	normalizeTrackQueueFilter(&filter)
+	query := `
+	DELETE FROM track_queue_filters
+	WHERE guild_id = $1
+	AND puuid = $2
+	AND kind = $3
+	AND value = $4`
+	result, err := db.pool.Exec(ctx, query, filter.GuildID, filter.PUUID, filter.Kind, filter.Value)
+	if err != nil {
+		return false, fmt.Errorf("remove track queue filter %s/%s:%s: %w", filter.GuildID, filter.Kind, filter.Value, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ClearTrackQueueFilters removes the guild-wide rules, or the rules of one account when puuid is set.
+func (db *Database) ClearTrackQueueFilters(ctx context.Context, guildID, puuid string) (int64, error) {
+	if err := db.ensureReady(); err != nil {
+		return 0, err
+	}
+
+	guildID, puuid = strings.TrimSpace(guildID), strings.TrimSpace(puuid)
+	query := `
+	DELETE FROM track_queue_filters
+	WHERE guild_id = $1
+	AND puuid = $2`
+	result, err := db.pool.Exec(ctx, query, guildID, puuid)
+	if err != nil {
+		return 0, fmt.Errorf("clear track queue filters %s: %w", guildID, err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListTrackQueueFilters returns the guild-wide and per-account rules of a guild.
+func (db *Database) ListTrackQueueFilters(ctx context.Context, guildID string) ([]TrackQueueFilter, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	query := `
+	SELECT ` + trackQueueFilterColumns + `
+	FROM track_queue_filters
+	WHERE guild_id = $1
+	ORDER BY puuid, kind, value`
+	return db.queryTrackQueueFilters(ctx, query, "list track queue filters for "+guildID, guildID)
+}
+
+// ListAllTrackQueueFilters returns every rule of every guild, for the notification loop.
+func (db *Database) ListAllTrackQueueFilters(ctx context.Context) ([]TrackQueueFilter, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT ` + trackQueueFilterColumns + `
+	FROM track_queue_filters
+	ORDER BY guild_id, puuid, kind, value`
+	return db.queryTrackQueueFilters(ctx, query, "list all track queue filters")
+}
+
+const trackQueueFilterColumns = `guild_id, puuid, kind, value, mode, created_at`
+
+func (db *Database) queryTrackQueueFilters(ctx context.Context, query, op string, args ...any) ([]TrackQueueFilter, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByPos[TrackQueueFilter])
+	if err != nil {
+		return nil, fmt.Errorf("collect track queue filters: %w", err)
+	}
+	for i := range out {
+		out[i].CreatedAt = out[i].CreatedAt.UTC()
+	}
+	return out, nil
+}
+
+func normalizeTrackQueueFilter(f *TrackQueueFilter) {
+	f.GuildID = strings.TrimSpace(f.GuildID)
+	f.PUUID = strings.TrimSpace(f.PUUID)
+	f.Kind = strings.ToLower(strings.TrimSpace(f.Kind))
+	f.Value = strings.TrimSpace(f.Value)
+	if f.Kind == TrackQueueFilterKindCategory {
+		f.Value = strings.ToLower(f.Value)
+	}
+	if !strings.EqualFold(strings.TrimSpace(f.Mode), TrackQueueFilterInclude) {
+		f.Mode = TrackQueueFilterExclude
+	} else {
+		f.Mode = TrackQueueFilterInclude
+	}
+}
+
+const createTrackQueueFiltersSQL = `
+CREATE TABLE IF NOT EXISTS track_queue_filters (
+    guild_id text NOT NULL REFERENCES track_guild_config (guild_id) ON DELETE CASCADE,
+    puuid text NOT NULL DEFAULT '',
+    kind text NOT NULL,
+    value text NOT NULL,
+    mode text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (guild_id, puuid, kind, value)
+)`
@@ -24,6 +24,16 @@ func TestTrackIntegration_TrackedAccountLifecycle(t *testing.T) {
 	if err := fx.db.UpsertTrackGuildConfig(fx.ctx, " "+guildID+" ", " channel-1 "); err != nil {
 		t.Fatalf("UpsertTrackGuildConfig() error = %v", err)
 	}
+	cfg, found, err := fx.db.TrackGuildConfig(fx.ctx, guildID)
+	if err != nil || !found || cfg.PostMode != TrackPostModeReply {
+		t.Fatalf("TrackGuildConfig() = %+v found:%v err:%v; want reply post mode", cfg, found, err)
+	}
+	if err := fx.db.SetTrackGuildPostMode(fx.ctx, guildID, " EDIT "); err != nil {
+		t.Fatalf("SetTrackGuildPostMode() error = %v", err)
+	}
+	if cfg, _, err = fx.db.TrackGuildConfig(fx.ctx, guildID); err != nil || cfg.PostMode != TrackPostModeEdit {
+		t.Fatalf("TrackGuildConfig() post mode = %q, %v; want %q", cfg.PostMode, err, TrackPostModeEdit)
+	}
 
 	created, err := fx.db.AddTrackedAccount(fx.ctx, TrackedAccount{
 		GuildID:        " " + guildID + " ",
@@ -66,6 +76,88 @@ func TestTrackIntegration_TrackedAccountLifecycle(t *testing.T) {
 	}
 }
 
+func TestTrackIntegration_UpdateTrackedAccountRiotID(t *testing.T) {
+	fx := newTrackFixture(t)
+	guildA, guildB := fx.prefix+"_rename_a", fx.prefix+"_rename_b"
+
+	for _, guildID := range []string{guildA, guildB} {
+		if err := fx.db.UpsertTrackGuildConfig(fx.ctx, guildID, "channel-1"); err != nil {
+			t.Fatalf("UpsertTrackGuildConfig() error = %v", err)
+		}
+	}
+	if err := fx.db.SetTrackGuildAnnounceRenames(fx.ctx, guildA, true); err != nil {
+		t.Fatalf("SetTrackGuildAnnounceRenames() error = %v", err)
+	}
+	if cfg, _, err := fx.db.TrackGuildConfig(fx.ctx, guildA); err != nil || !cfg.AnnounceRenames {
+		t.Fatalf("TrackGuildConfig() = %+v, %v; want rename announcements on", cfg, err)
+	}
+	if _, err := fx.db.AddTrackedAccount(fx.ctx, TrackedAccount{GuildID: guildA, PlatformRegion: "br1", PUUID: fx.prefix + "_p1", NickName: "Ekko", TagLine: "BR1"}); err != nil {
+		t.Fatalf("AddTrackedAccount(a) error = %v", err)
+	}
+	if _, err := fx.db.AddTrackedAccount(fx.ctx, TrackedAccount{GuildID: guildB, PlatformRegion: "br1", PUUID: fx.prefix + "_p1", NickName: "Bekko", TagLine: "BR1"}); err != nil {
+		t.Fatalf("AddTrackedAccount(b) error = %v", err)
+	}
+
+	renamed, err := fx.db.UpdateTrackedAccountRiotID(fx.ctx, "BR1", fx.prefix+"_p1", " Bekko ", "#BR1")
+	if err != nil || len(renamed) != 1 || renamed[0] != guildA {
+		t.Fatalf("UpdateTrackedAccountRiotID() = %v, %v; want [%s]", renamed, err, guildA)
+	}
+	if got := fx.listTracked(t, guildA); len(got) != 1 || got[0].RiotID() != "Bekko#BR1" {
+		t.Fatalf("tracked after rename = %+v", got)
+	}
+	if renamed, err := fx.db.UpdateTrackedAccountRiotID(fx.ctx, "br1", fx.prefix+"_p1", "Bekko", "BR1"); err != nil || len(renamed) != 0 {
+		t.Fatalf("UpdateTrackedAccountRiotID(unchanged) = %v, %v; want none", renamed, err)
+	}
+}
+
+func TestTrackIntegration_ListTrackedAccountsPage(t *testing.T) {
+	fx := newTrackFixture(t)
+	guildID := fx.prefix + "_page_guild"
+
+	base := time.Now().UTC().Add(-time.Hour)
+	for n, account := range []TrackedAccount{
+		{PlatformRegion: "na1", PUUID: "puuid-c", NickName: "Caitlyn", TagLine: "NA1"},
+		{PlatformRegion: "br1", PUUID: "puuid-a", NickName: "ahri", TagLine: "BR1"},
+		{PlatformRegion: "na1", PUUID: "puuid-b", NickName: "Braum", TagLine: "NA1"},
+	} {
+		account.GuildID = guildID
+		if _, err := fx.db.AddTrackedAccount(fx.ctx, account); err != nil {
+			t.Fatalf("AddTrackedAccount(%s) error = %v", account.PUUID, err)
+		}
+		if _, err := fx.db.pool.Exec(fx.ctx, `UPDATE track_accounts SET added_at = $3 WHERE guild_id = $1 AND puuid = $2`, guildID, account.PUUID, base.Add(time.Duration(n)*time.Minute)); err != nil {
+			t.Fatalf("set added_at error = %v", err)
+		}
+	}
+
+	ids := func(accounts []TrackedAccount) string {
+		out := make([]string, 0, len(accounts))
+		for _, account := range accounts {
+			out = append(out, account.PUUID)
+		}
+		return strings.Join(out, ",")
+	}
+	for _, tc := range []struct {
+		page      TrackedAccountPage
+		want      string
+		wantTotal int
+	}{
+		{TrackedAccountPage{Sort: TrackAccountSortName, Limit: 2}, "puuid-a,puuid-b", 3},
+		{TrackedAccountPage{Sort: TrackAccountSortName, Limit: 2, Offset: 2}, "puuid-c", 3},
+		{TrackedAccountPage{Sort: TrackAccountSortDate, Limit: 10}, "puuid-b,puuid-a,puuid-c", 3},
+		{TrackedAccountPage{Sort: TrackAccountSortRegion, Limit: 10}, "puuid-a,puuid-b,puuid-c", 3},
+		{TrackedAccountPage{Region: " NA1 ", Limit: 10}, "puuid-b,puuid-c", 2},
+		{TrackedAccountPage{Limit: 10, Offset: 5}, "", 3},
+	} {
+		got, total, err := fx.db.ListTrackedAccountsPage(fx.ctx, guildID, tc.page)
+		if err != nil {
+			t.Fatalf("ListTrackedAccountsPage(%+v) error = %v", tc.page, err)
+		}
+		if ids(got) != tc.want || total != tc.wantTotal {
+			t.Fatalf("ListTrackedAccountsPage(%+v) = %q total %d, want %q total %d", tc.page, ids(got), total, tc.want, tc.wantTotal)
+		}
+	}
+}
+
 func TestTrackIntegration_MatchNotificationLifecycle(t *testing.T) {
 	fx := newTrackFixture(t)
 	notification := fx.createLiveNotification(t, "notify", " na1 ")
@@ -93,6 +185,106 @@ func TestTrackIntegration_MatchNotificationLifecycle(t *testing.T) {
 	fx.pendingByKey(t, key, time.Now().UTC().Add(time.Hour), false)
 }
 
+func TestTrackIntegration_MatchCatchUp(t *testing.T) {
+	fx := newTrackFixture(t)
+	live := fx.createLiveNotification(t, "catchup", "na1")
+	guildID := live.GuildID
+	if _, err := fx.db.AddTrackedAccount(fx.ctx, TrackedAccount{GuildID: guildID, PlatformRegion: "na1", PUUID: "puuid-1", NickName: "Ahri", TagLine: "NA1"}); err != nil {
+		t.Fatalf("AddTrackedAccount() error = %v", err)
+	}
+
+	cursors, err := fx.db.ListTrackCatchUpCursors(fx.ctx)
+	if err != nil {
+		t.Fatalf("ListTrackCatchUpCursors() error = %v", err)
+	}
+	var cursor TrackCatchUpCursor
+	for _, candidate := range cursors {
+		if candidate.GuildID == guildID {
+			cursor = candidate
+		}
+	}
+	if cursor.PUUID != "puuid-1" || cursor.PlatformRegion != "na1" || cursor.Since.IsZero() {
+		t.Fatalf("cursor = %+v, want one for puuid-1", cursor)
+	}
+
+	seenAt := time.Now().UTC().Add(time.Hour).Truncate(time.Microsecond)
+	if err := fx.db.AdvanceTrackCatchUpCursors(fx.ctx, guildID, []string{"puuid-1"}, seenAt); err != nil {
+		t.Fatalf("AdvanceTrackCatchUpCursors() error = %v", err)
+	}
+	if err := fx.db.AdvanceTrackCatchUpCursors(fx.ctx, guildID, []string{"puuid-1"}, seenAt.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("AdvanceTrackCatchUpCursors(earlier) error = %v", err)
+	}
+	cursors, err = fx.db.ListTrackCatchUpCursors(fx.ctx)
+	if err != nil {
+		t.Fatalf("ListTrackCatchUpCursors() error = %v", err)
+	}
+	for _, candidate := range cursors {
+		if candidate.GuildID == guildID && !candidate.Since.Equal(seenAt) {
+			t.Fatalf("cursor = %+v, want it advanced to %v", candidate, seenAt)
+		}
+	}
+
+	matchID := strings.ToUpper(fx.prefix + "_catchup_2002")
+	input := UpsertTrackMatchLiveInput{
+		GuildID:       guildID,
+		PlatformID:    "na1",
+		GameID:        2002,
+		MatchID:       matchID,
+		QueueID:       420,
+		QueueCategory: "kpvp",
+		PlayerPUUID:   "puuid-1",
+		PlayerRiotID:  "Ahri#NA1",
+		TrackedCount:  1,
+		LiveChannelID: "track-channel",
+	}
+	if inserted, err := fx.db.InsertTrackMatchCatchUp(fx.ctx, input); err != nil || !inserted {
+		t.Fatalf("InsertTrackMatchCatchUp() = %v, %v; want true, nil", inserted, err)
+	}
+	if inserted, err := fx.db.InsertTrackMatchCatchUp(fx.ctx, input); err != nil || inserted {
+		t.Fatalf("InsertTrackMatchCatchUp(duplicate) = %v, %v; want false, nil", inserted, err)
+	}
+	input.GameID, input.MatchID = live.GameID, live.MatchID
+	if inserted, err := fx.db.InsertTrackMatchCatchUp(fx.ctx, input); err != nil || inserted {
+		t.Fatalf("InsertTrackMatchCatchUp(live game) = %v, %v; want false, nil", inserted, err)
+	}
+
+	entry := fx.pendingByKey(t, TrackMatchNotificationKey{GuildID: guildID, PlatformID: "NA1", GameID: 2002}, time.Now().UTC(), true)
+	if !entry.CatchUp || entry.LiveMessageID != "" || entry.LiveChannelID != "track-channel" {
+		t.Fatalf("catch-up entry = %+v", entry)
+	}
+	if live := fx.pendingByKey(t, live.Key(), time.Now().UTC(), true); live.CatchUp {
+		t.Fatalf("live entry marked as catch-up: %+v", live)
+	}
+
+	guilds, err := fx.db.ListTrackMatchNotificationGuilds(fx.ctx, []string{matchID, " ", "NA1_0"})
+	if err != nil || len(guilds) != 1 || len(guilds[matchID]) != 1 || guilds[matchID][0] != guildID {
+		t.Fatalf("ListTrackMatchNotificationGuilds() = %v, %v", guilds, err)
+	}
+}
+
+func TestTrackIntegration_ReserveRiotRequest(t *testing.T) {
+	fx := newTrackFixture(t)
+	buckets := []riot.RateLimitBucket{
+		{Key: fx.prefix + ":app", Windows: []riot.RateLimitWindow{{Requests: 5, Window: time.Second}, {Requests: 2, Window: time.Minute}}},
+		{Key: fx.prefix + ":method", Windows: []riot.RateLimitWindow{{Requests: 10, Window: time.Minute}}},
+	}
+
+	for n := range 2 {
+		if wait, err := fx.db.ReserveRiotRequest(fx.ctx, buckets); err != nil || wait != 0 {
+			t.Fatalf("ReserveRiotRequest(#%d) = %v, %v; want 0, nil", n+1, wait, err)
+		}
+	}
+	wait, err := fx.db.ReserveRiotRequest(fx.ctx, buckets)
+	if err != nil || wait <= 0 || wait > time.Minute {
+		t.Fatalf("ReserveRiotRequest(full) = %v, %v; want a wait within the minute window", wait, err)
+	}
+
+	var used int
+	if err := fx.db.pool.QueryRow(fx.ctx, `SELECT used FROM riot_rate_limit_windows WHERE limit_key = $1 AND window_seconds = 60`, fx.prefix+":method").Scan(&used); err != nil || used != 2 {
+		t.Fatalf("method window used = %d, %v; want 2 (a refused reservation counts nothing)", used, err)
+	}
+}
+
 func TestTrackIntegration_SnapshotRoundTripAndCleanup(t *testing.T) {
 	fx := newTrackFixture(t)
 	matchID := strings.ToUpper(fx.prefix) + "_SNAP_1"
@@ -135,6 +327,188 @@ func TestTrackIntegration_SnapshotRoundTripAndCleanup(t *testing.T) {
 	}
 }
 
+func TestTrackIntegration_RankSnapshotLifecycle(t *testing.T) {
+	fx := newTrackFixture(t)
+	puuid := fx.prefix + "_puuid"
+	matchID := strings.ToUpper(fx.prefix) + "_RANK_1"
+	entry := riot.LeagueEntry{QueueType: "RANKED_SOLO_5x5", Tier: "gold", Rank: "ii", LeaguePoints: 36, Wins: 10, Losses: 9}
+
+	start := NewTrackRankSnapshot("BR1", puuid, entry, matchID, TrackRankPhaseStart, time.Now().UTC().Add(-30*time.Minute))
+	for range 2 {
+		if err := fx.db.InsertTrackRankSnapshot(fx.ctx, start); err != nil {
+			t.Fatalf("InsertTrackRankSnapshot() error = %v", err)
+		}
+	}
+	got, found, err := fx.db.TrackRankSnapshotForMatch(fx.ctx, puuid, entry.QueueType, matchID, TrackRankPhaseStart)
+	if err != nil || !found {
+		t.Fatalf("TrackRankSnapshotForMatch() = found:%v err:%v; want true, nil", found, err)
+	}
+	if got.Tier != "GOLD" || got.Rank != "II" || got.PlatformRegion != "br1" || got.LeaguePoints != 36 {
+		t.Fatalf("snapshot = %+v", got)
+	}
+
+	_, found, err = fx.db.LatestTrackRankSnapshot(fx.ctx, puuid, entry.QueueType, matchID)
+	if err != nil || found {
+		t.Fatalf("LatestTrackRankSnapshot(excluding match) = found:%v err:%v; want false, nil", found, err)
+	}
+	latest, found, err := fx.db.LatestTrackRankSnapshot(fx.ctx, puuid, entry.QueueType, "")
+	if err != nil || !found || latest.MatchID != matchID {
+		t.Fatalf("LatestTrackRankSnapshot() = %+v found:%v err:%v", latest, found, err)
+	}
+
+	listed, err := fx.db.ListTrackRankSnapshots(fx.ctx, puuid, entry.QueueType, time.Now().UTC().Add(-time.Hour))
+	if err != nil || len(listed) != 1 || listed[0].MatchID != matchID {
+		t.Fatalf("ListTrackRankSnapshots() = %+v, %v; want one snapshot", listed, err)
+	}
+	listed, err = fx.db.ListTrackRankSnapshots(fx.ctx, puuid, entry.QueueType, time.Now().UTC().Add(-10*time.Minute))
+	if err != nil || len(listed) != 0 {
+		t.Fatalf("ListTrackRankSnapshots(recent) = %+v, %v; want none", listed, err)
+	}
+
+	var count int
+	if err := fx.db.pool.QueryRow(fx.ctx, `SELECT count(*) FROM track_rank_snapshots WHERE puuid = $1`, puuid).Scan(&count); err != nil {
+		t.Fatalf("count rank snapshots error = %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("rank snapshots = %d, want 1", count)
+	}
+}
+
+func TestTrackIntegration_ListTrackLeaderboardEntries(t *testing.T) {
+	fx := newTrackFixture(t)
+	guildID := fx.prefix + "_board_guild"
+	ranked, unseen := fx.prefix+"_ranked", fx.prefix+"_unseen"
+	for _, puuid := range []string{ranked, unseen} {
+		if _, err := fx.db.AddTrackedAccount(fx.ctx, TrackedAccount{GuildID: guildID, PlatformRegion: "br1", PUUID: puuid, NickName: puuid, TagLine: "BR1"}); err != nil {
+			t.Fatalf("AddTrackedAccount(%s) error = %v", puuid, err)
+		}
+	}
+	entry := riot.LeagueEntry{QueueType: "RANKED_SOLO_5x5", Tier: "GOLD", Rank: "II", LeaguePoints: 36}
+	now := time.Now().UTC()
+	for n, lp := range []int{36, 18} {
+		entry.LeaguePoints = lp
+		if err := fx.db.InsertTrackRankSnapshot(fx.ctx, NewTrackRankSnapshot("br1", ranked, entry, "", TrackRankPhasePoll, now.Add(time.Duration(n)*time.Minute))); err != nil {
+			t.Fatalf("InsertTrackRankSnapshot() error = %v", err)
+		}
+	}
+
+	got, err := fx.db.ListTrackLeaderboardEntries(fx.ctx, guildID, entry.QueueType)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("ListTrackLeaderboardEntries() = %+v, %v; want two accounts", got, err)
+	}
+	for _, e := range got {
+		switch e.Account.PUUID {
+		case ranked:
+			if e.Snapshot == nil || e.Snapshot.LeaguePoints != 18 || e.Snapshot.Tier != "GOLD" {
+				t.Fatalf("ranked snapshot = %+v, want the latest GOLD II 18 LP", e.Snapshot)
+			}
+		case unseen:
+			if e.Snapshot != nil {
+				t.Fatalf("unseen snapshot = %+v, want none", e.Snapshot)
+			}
+		}
+	}
+}
+
+func TestTrackIntegration_AccountLinkLifecycle(t *testing.T) {
+	fx := newTrackFixture(t)
+	firstUser, secondUser := fx.prefix+"_user_1", fx.prefix+"_user_2"
+	puuid := fx.prefix + "_puuid"
+	pending := AccountLinkVerification{
+		PlatformRegion: "BR1",
+		PUUID:          puuid,
+		GameName:       " Ahri ",
+		TagLine:        "#BR1",
+		ProfileIconID:  7,
+		ExpiresAt:      time.Now().UTC().Add(10 * time.Minute),
+	}
+
+	for _, userID := range []string{firstUser, secondUser} {
+		pending.UserID = userID
+		if err := fx.db.UpsertAccountLinkVerification(fx.ctx, pending); err != nil {
+			t.Fatalf("UpsertAccountLinkVerification(%s) error = %v", userID, err)
+		}
+		got, found, err := fx.db.AccountLinkVerification(fx.ctx, userID)
+		if err != nil || !found || got.ProfileIconID != 7 || got.PlatformRegion != "br1" || got.RiotID() != "Ahri#BR1" {
+			t.Fatalf("AccountLinkVerification(%s) = %+v found:%v err:%v", userID, got, found, err)
+		}
+		link, completed, err := fx.db.CompleteAccountLink(fx.ctx, userID, time.Time{})
+		if err != nil || !completed || link.PUUID != puuid {
+			t.Fatalf("CompleteAccountLink(%s) = %+v completed:%v err:%v", userID, link, completed, err)
+		}
+	}
+
+	// The second verification takes the account over from the first user.
+	if _, found, err := fx.db.AccountLinkByUser(fx.ctx, firstUser); err != nil || found {
+		t.Fatalf("AccountLinkByUser(first) = found:%v err:%v; want false, nil", found, err)
+	}
+	if _, completed, err := fx.db.CompleteAccountLink(fx.ctx, secondUser, time.Time{}); err != nil || completed {
+		t.Fatalf("CompleteAccountLink(without pending) = completed:%v err:%v; want false, nil", completed, err)
+	}
+	if err := fx.db.UpdateAccountLinkRiotID(fx.ctx, secondUser, "Ahri Renamed", "BR2"); err != nil {
+		t.Fatalf("UpdateAccountLinkRiotID() error = %v", err)
+	}
+	link, found, err := fx.db.AccountLinkByUser(fx.ctx, secondUser)
+	if err != nil || !found || link.RiotID() != "Ahri Renamed#BR2" {
+		t.Fatalf("AccountLinkByUser(second) = %+v found:%v err:%v", link, found, err)
+	}
+
+	removed, err := fx.db.RemoveAccountLink(fx.ctx, secondUser)
+	if err != nil || !removed {
+		t.Fatalf("RemoveAccountLink() = %v, %v; want true, nil", removed, err)
+	}
+	if removed, err := fx.db.RemoveAccountLink(fx.ctx, secondUser); err != nil || removed {
+		t.Fatalf("RemoveAccountLink(again) = %v, %v; want false, nil", removed, err)
+	}
+}
+
+func TestTrackIntegration_ResolveUserAccountPriority(t *testing.T) {
+	fx := newTrackFixture(t)
+	userID, guildID := fx.prefix+"_user", fx.prefix+"_guild"
+	if err := fx.db.UpsertTrackGuildConfig(fx.ctx, guildID, "channel"); err != nil {
+		t.Fatalf("UpsertTrackGuildConfig() error = %v", err)
+	}
+	resolve := func(wantSource, wantPUUID string) {
+		t.Helper()
+		got, found, err := fx.db.ResolveUserAccount(fx.ctx, guildID, userID)
+		if wantSource == "" {
+			if err != nil || found {
+				t.Fatalf("ResolveUserAccount() = %+v found:%v err:%v; want none", got, found, err)
+			}
+			return
+		}
+		if err != nil || !found || got.Source != wantSource || got.PUUID != wantPUUID {
+			t.Fatalf("ResolveUserAccount() = %+v found:%v err:%v; want %s/%s", got, found, err, wantSource, wantPUUID)
+		}
+	}
+
+	resolve("", "")
+	if _, err := fx.db.AddTrackedAccount(fx.ctx, TrackedAccount{GuildID: guildID, PlatformRegion: "br1", PUUID: fx.prefix + "_tracked", NickName: "Lux", TagLine: "BR1", AddedBy: userID}); err != nil {
+		t.Fatalf("AddTrackedAccount() error = %v", err)
+	}
+	resolve(UserAccountSourceTrack, fx.prefix+"_tracked")
+	if _, found, err := fx.db.ResolveUserAccount(fx.ctx, "", userID); err != nil || found {
+		t.Fatalf("ResolveUserAccount(outside guild) = found:%v err:%v; want false, nil", found, err)
+	}
+
+	if err := fx.db.SetAccountProfile(fx.ctx, AccountProfile{UserID: userID, PlatformRegion: "BR1", PUUID: fx.prefix + "_profile", GameName: "Ahri", TagLine: "BR1"}); err != nil {
+		t.Fatalf("SetAccountProfile() error = %v", err)
+	}
+	resolve(UserAccountSourceProfile, fx.prefix+"_profile")
+
+	if err := fx.db.UpsertAccountLinkVerification(fx.ctx, AccountLinkVerification{UserID: userID, PlatformRegion: "br1", PUUID: fx.prefix + "_linked", GameName: "Zed", TagLine: "BR1", ProfileIconID: 3, ExpiresAt: time.Now().Add(time.Minute)}); err != nil {
+		t.Fatalf("UpsertAccountLinkVerification() error = %v", err)
+	}
+	if _, _, err := fx.db.CompleteAccountLink(fx.ctx, userID, time.Time{}); err != nil {
+		t.Fatalf("CompleteAccountLink() error = %v", err)
+	}
+	resolve(UserAccountSourceLink, fx.prefix+"_linked")
+
+	if removed, err := fx.db.RemoveAccountProfile(fx.ctx, userID); err != nil || !removed {
+		t.Fatalf("RemoveAccountProfile() = %v, %v; want true, nil", removed, err)
+	}
+}
+
 func newTrackFixture(t *testing.T) trackFixture {
 	t.Helper()
 
@@ -215,6 +589,12 @@ func openTrackIntegrationDB(t *testing.T, ctx context.Context) (*Database, strin
 	if err := db.CreateTrackTable(ctx); err != nil {
 		t.Fatalf("CreateTrackTable() error = %v", err)
 	}
+	if err := db.CreateAccountLinkTable(ctx); err != nil {
+		t.Fatalf("CreateAccountLinkTable() error = %v", err)
+	}
+	if err := db.CreateRiotRateLimitTable(ctx); err != nil {
+		t.Fatalf("CreateRiotRateLimitTable() error = %v", err)
+	}
 
 	prefix := integrationPrefix(t.Name())
 	cleanupTrackIntegrationData(t, db, prefix)
@@ -250,6 +630,21 @@ func cleanupTrackIntegrationData(t *testing.T, db *Database, prefix string) {
 	if _, err := db.pool.Exec(ctx, `DELETE FROM track_match_snapshots WHERE match_id ILIKE $1`, prefix+"%"); err != nil {
 		t.Fatalf("cleanup track_match_snapshots: %v", err)
 	}
+	if _, err := db.pool.Exec(ctx, `DELETE FROM track_rank_snapshots WHERE puuid LIKE $1`, prefix+"%"); err != nil {
+		t.Fatalf("cleanup track_rank_snapshots: %v", err)
+	}
+	if _, err := db.pool.Exec(ctx, `DELETE FROM riot_rate_limit_windows WHERE limit_key LIKE $1`, prefix+"%"); err != nil {
+		t.Fatalf("cleanup riot_rate_limit_windows: %v", err)
+	}
+	if _, err := db.pool.Exec(ctx, `DELETE FROM account_links WHERE user_id LIKE $1`, prefix+"%"); err != nil {
+		t.Fatalf("cleanup account_links: %v", err)
+	}
+	if _, err := db.pool.Exec(ctx, `DELETE FROM account_link_verifications WHERE user_id LIKE $1`, prefix+"%"); err != nil {
+		t.Fatalf("cleanup account_link_verifications: %v", err)
+	}
+	if _, err := db.pool.Exec(ctx, `DELETE FROM account_profiles WHERE user_id LIKE $1`, prefix+"%"); err != nil {
+		t.Fatalf("cleanup account_profiles: %v", err)
+	}
 }
 
 func findNotificationByKey(list []TrackMatchNotification, key TrackMatchNotificationKey) (TrackMatchNotification, bool) {
@@ -19,6 +19,10 @@ type TrackNotificationTarget struct {
 	PUUID          string
 	NickName       string
 	TagLine        string
+	PostMode       string
+	// DiscordUserID is the Discord user that verified ownership of the account with /link, if any.
+	DiscordUserID   string
+	AnnounceRenames bool
 }
 
 func (t TrackNotificationTarget) RiotID() string {
@@ -52,6 +56,7 @@ type TrackMatchNotification struct {
 	LastPostError       string
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
+	CatchUp             bool
 }
 
 func (n TrackMatchNotification) Key() TrackMatchNotificationKey {
@@ -86,10 +91,16 @@ func (db *Database) ListTrackNotificationTargets(ctx context.Context) ([]TrackNo
 		a.platform_region,
 		a.puuid,
 		a.game_name,
-		a.tag_line
+		a.tag_line,
+		c.post_mode,
+		COALESCE(l.user_id, ''),
+		c.announce_renames
 	FROM track_accounts a
 	JOIN track_guild_config c
 	ON c.guild_id = a.guild_id
+	LEFT JOIN account_links l
+	ON l.platform_region = a.platform_region
+		AND l.puuid = a.puuid
 	ORDER BY a.guild_id, a.platform_region, a.puuid`
 	rows, err := db.pool.Query(ctx, query)
 	if err != nil {
@@ -110,6 +121,8 @@ func (db *Database) ListTrackNotificationTargets(ctx context.Context) ([]TrackNo
 		target.PUUID = strings.TrimSpace(target.PUUID)
 		target.NickName = strings.TrimSpace(target.NickName)
 		target.TagLine = strings.TrimPrefix(strings.TrimSpace(target.TagLine), "#")
+		target.PostMode = NormalizeTrackPostMode(target.PostMode)
+		target.DiscordUserID = strings.TrimSpace(target.DiscordUserID)
 		if target.GuildID == "" || target.ChannelID == "" || target.PlatformRegion == "" || target.PUUID == "" {
 			continue
 		}
@@ -163,7 +176,10 @@ func (db *Database) UpsertTrackMatchNotificationLive(ctx context.Context, input
 		player_puuid = excluded.player_puuid,
 		player_riot_id = excluded.player_riot_id,
 		tracked_count = excluded.tracked_count,
-		live_channel_id = excluded.live_channel_id,
+		live_channel_id = CASE
+			WHEN COALESCE(track_match_notifications.live_message_id, '') = '' THEN excluded.live_channel_id
+			ELSE track_match_notifications.live_channel_id
+		END,
 		last_live_seen_at = excluded.last_live_seen_at,
 		updated_at = now()
 	RETURNING guild_id,
@@ -186,7 +202,8 @@ func (db *Database) UpsertTrackMatchNotificationLive(ctx context.Context, input
 			post_abandoned_at,
 			last_post_error,
 			created_at,
-			updated_at`
+			updated_at,
+			catch_up`
 	row := db.pool.QueryRow(
 		ctx, query,
 		input.GuildID, input.PlatformID,
@@ -251,7 +268,8 @@ func (db *Database) ListPendingTrackMatchNotifications(ctx context.Context, now
 		post_abandoned_at,
 		last_post_error,
 		created_at,
-		updated_at
+		updated_at,
+		catch_up
 	FROM track_match_notifications
 	WHERE live_posted_at IS NOT NULL
 	AND post_posted_at IS NULL
@@ -334,6 +352,172 @@ func (db *Database) AbandonTrackMatchNotification(ctx context.Context, key Track
 	return db.execTrackMatchNotificationUpdate(ctx, "abandon track match notification", key, query, abandonedAt, lastError)
 }
 
+// TrackCatchUpCursor is the point in time since which a tracked account may have played games
+// that never got a notification, e.g. while the bot was offline.
+type TrackCatchUpCursor struct {
+	GuildID        string
+	PlatformRegion string
+	PUUID          string
+	Since          time.Time
+}
+
+// ListTrackCatchUpCursors returns, per guild and tracked account, the last time the account was seen
+// in a notified or caught-up game, or when it started being tracked if it never was.
+func (db *Database) ListTrackCatchUpCursors(ctx context.Context) ([]TrackCatchUpCursor, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+	query := `
+	SELECT a.guild_id,
+		a.platform_region,
+		a.puuid,
+		GREATEST(a.added_at, a.catch_up_seen_at, max(n.last_live_seen_at))
+	FROM track_accounts a
+	JOIN track_guild_config c
+	ON c.guild_id = a.guild_id
+		AND c.channel_id <> ''
+	LEFT JOIN track_match_notifications n
+	ON n.guild_id = a.guild_id
+		AND n.player_puuid = a.puuid
+	GROUP BY a.guild_id, a.platform_region, a.puuid, a.added_at, a.catch_up_seen_at
+	ORDER BY a.guild_id, a.platform_region, a.puuid`
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list track catch-up cursors: %w", err)
+	}
+	defer rows.Close()
+
+	cursors, err := pgx.CollectRows(rows, pgx.RowToStructByPos[TrackCatchUpCursor])
+	if err != nil {
+		return nil, fmt.Errorf("collect track catch-up cursors: %w", err)
+	}
+	for idx := range cursors {
+		cursors[idx].GuildID = strings.TrimSpace(cursors[idx].GuildID)
+		cursors[idx].PlatformRegion = riot.NormalizePlatformRegion(cursors[idx].PlatformRegion)
+		cursors[idx].PUUID = strings.TrimSpace(cursors[idx].PUUID)
+		cursors[idx].Since = cursors[idx].Since.UTC()
+	}
+	return cursors, nil
+}
+
+// AdvanceTrackCatchUpCursors moves the catch-up cursor of the given tracked accounts of a guild to
+// seenAt, so games they played in, whichever of them got the notification, are not looked up again.
+// A cursor never moves back.
+func (db *Database) AdvanceTrackCatchUpCursors(ctx context.Context, guildID string, puuids []string, seenAt time.Time) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	ids := make([]string, 0, len(puuids))
+	for _, puuid := range puuids {
+		if puuid = strings.TrimSpace(puuid); puuid != "" {
+			ids = append(ids, puuid)
+		}
+	}
+	if guildID == "" || len(ids) == 0 || seenAt.IsZero() {
+		return nil
+	}
+
+	query := `
+	UPDATE track_accounts
+	SET catch_up_seen_at = $3
+	WHERE guild_id = $1
+		AND puuid = ANY($2)
+		AND (catch_up_seen_at IS NULL OR catch_up_seen_at < $3)`
+	if _, err := db.pool.Exec(ctx, query, guildID, ids, seenAt.UTC()); err != nil {
+		return fmt.Errorf("advance track catch-up cursors %s: %w", guildID, err)
+	}
+	return nil
+}
+
+// ListTrackMatchNotificationGuilds maps each of the given match IDs to the guilds that already have a
+// notification for it. Match IDs without any notification are left out.
+func (db *Database) ListTrackMatchNotificationGuilds(ctx context.Context, matchIDs []string) (map[string][]string, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(matchIDs))
+	for _, matchID := range matchIDs {
+		if matchID = strings.TrimSpace(matchID); matchID != "" {
+			ids = append(ids, matchID)
+		}
+	}
+	if len(ids) == 0 {
+		return map[string][]string{}, nil
+	}
+
+	query := `
+	SELECT match_id, guild_id
+	FROM track_match_notifications
+	WHERE match_id = ANY($1)`
+	rows, err := db.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query track match notification guilds: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string][]string, len(ids))
+	for rows.Next() {
+		var matchID, guildID string
+		if err := rows.Scan(&matchID, &guildID); err != nil {
+			return nil, fmt.Errorf("scan track match notification guild: %w", err)
+		}
+		out[matchID] = append(out[matchID], guildID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate track match notification guilds: %w", err)
+	}
+	return out, nil
+}
+
+// InsertTrackMatchCatchUp stores a notification for a finished game the bot never saw live. The row
+// is ready for the post-game pipeline right away and is never posted twice: it reports false when
+// the guild already has a notification for the game.
+func (db *Database) InsertTrackMatchCatchUp(ctx context.Context, input UpsertTrackMatchLiveInput) (bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return false, err
+	}
+
+	key := normalizeTrackMatchKey(TrackMatchNotificationKey{
+		GuildID:    input.GuildID,
+		PlatformID: input.PlatformID,
+		GameID:     input.GameID,
+	})
+	input.LastLiveSeenAt = utcNowIfZero(input.LastLiveSeenAt)
+
+	query := `
+	INSERT INTO track_match_notifications (
+		guild_id,
+		platform_id,
+		game_id,
+		match_id,
+		queue_id,
+		queue_category,
+		player_puuid,
+		player_riot_id,
+		tracked_count,
+		live_channel_id,
+		live_posted_at,
+		last_live_seen_at,
+		catch_up
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11, true)
+	ON CONFLICT (guild_id, platform_id, game_id) DO NOTHING`
+	tag, err := db.pool.Exec(
+		ctx, query,
+		key.GuildID, key.PlatformID, key.GameID,
+		strings.TrimSpace(input.MatchID), input.QueueID, strings.TrimSpace(input.QueueCategory),
+		strings.TrimSpace(input.PlayerPUUID), strings.TrimSpace(input.PlayerRiotID), input.TrackedCount,
+		strings.TrimSpace(input.LiveChannelID), input.LastLiveSeenAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert track match catch-up %s/%s/%d: %w", key.GuildID, key.PlatformID, key.GameID, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func (db *Database) GetTrackMatchSnapshot(ctx context.Context, matchID string) (riot.MatchDetail, bool, error) {
 	if err := db.ensureReady(); err != nil {
 		return riot.MatchDetail{}, false, err
@@ -367,6 +551,55 @@ func (db *Database) GetTrackMatchSnapshot(ctx context.Context, matchID string) (
 	return match, true, nil
 }
 
+func (db *Database) GetTrackMatchSnapshots(ctx context.Context, matchIDs []string) (map[string]riot.MatchDetail, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(matchIDs))
+	for _, matchID := range matchIDs {
+		if matchID = strings.TrimSpace(matchID); matchID != "" {
+			ids = append(ids, matchID)
+		}
+	}
+	if len(ids) == 0 {
+		return map[string]riot.MatchDetail{}, nil
+	}
+
+	query := `
+	SELECT match_id, payload
+	FROM track_match_snapshots
+	WHERE match_id = ANY($1)`
+	rows, err := db.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query track match snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]riot.MatchDetail, len(ids))
+	for rows.Next() {
+		var (
+			matchID string
+			payload []byte
+		)
+		if err := rows.Scan(&matchID, &payload); err != nil {
+			return nil, fmt.Errorf("scan track match snapshot: %w", err)
+		}
+		var match riot.MatchDetail
+		if err := json.Unmarshal(payload, &match); err != nil {
+			return nil, fmt.Errorf("decode track match snapshot %q: %w", matchID, err)
+		}
+		if strings.TrimSpace(match.Metadata.MatchID) == "" {
+			match.Metadata.MatchID = matchID
+		}
+		out[matchID] = match
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate track match snapshots: %w", err)
+	}
+	return out, nil
+}
+
 func (db *Database) UpsertTrackMatchSnapshot(ctx context.Context, match riot.MatchDetail) error {
 	if err := db.ensureReady(); err != nil {
 		return err
@@ -478,7 +711,7 @@ func scanTrackMatchNotification(scan func(dest ...any) error) (TrackMatchNotific
 		&liveMessageID, &notification.LivePostedAt, &notification.LastLiveSeenAt, &postMessageID,
 		&notification.PostPostedAt, &notification.PostAttempts, &notification.NextPostAttemptAt,
 		&notification.PostAbandonedAt, &notification.LastPostError,
-		&notification.CreatedAt, &notification.UpdatedAt,
+		&notification.CreatedAt, &notification.UpdatedAt, &notification.CatchUp,
 	)
 	if err != nil {
 		return TrackMatchNotification{}, err
@@ -545,6 +778,14 @@ CREATE TABLE IF NOT EXISTS track_match_notifications (
     PRIMARY KEY (guild_id, platform_id, game_id)
 )`
 
+const addTrackMatchNotificationsCatchUpSQL = `
+ALTER TABLE track_match_notifications
+ADD COLUMN IF NOT EXISTS catch_up boolean NOT NULL DEFAULT false`
+
+const createTrackMatchNotificationsPlayerIdxSQL = `
+CREATE INDEX IF NOT EXISTS track_match_notifications_player_idx
+ON track_match_notifications (guild_id, player_puuid, last_live_seen_at)`
+
 const createTrackMatchNotificationsPostIdxSQL = `
 CREATE INDEX IF NOT EXISTS track_match_notifications_post_idx
 ON track_match_notifications (post_posted_at, post_abandoned_at, next_post_attempt_at)`
@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	TrackRankPhaseStart = "start"
+	TrackRankPhaseEnd   = "end"
+	TrackRankPhasePoll  = "poll"
+)
+
+type TrackRankSnapshot struct {
+	PlatformRegion string
+	PUUID          string
+	QueueType      string
+	Tier           string
+	Rank           string
+	LeaguePoints   int
+	Wins           int
+	Losses         int
+	MatchID        string
+	Phase          string
+	CapturedAt     time.Time
+}
+
+func (s TrackRankSnapshot) LeagueEntry() riot.LeagueEntry {
+	return riot.LeagueEntry{
+		QueueType:    s.QueueType,
+		Tier:         s.Tier,
+		Rank:         s.Rank,
+		LeaguePoints: s.LeaguePoints,
+		Wins:         s.Wins,
+		Losses:       s.Losses,
+	}
+}
+
+func NewTrackRankSnapshot(platformRegion, puuid string, entry riot.LeagueEntry, matchID, phase string, capturedAt time.Time) TrackRankSnapshot {
+	return TrackRankSnapshot{
+		PlatformRegion: platformRegion,
+		PUUID:          puuid,
+		QueueType:      entry.QueueType,
+		Tier:           entry.Tier,
+		Rank:           entry.Rank,
+		LeaguePoints:   entry.LeaguePoints,
+		Wins:           entry.Wins,
+		Losses:         entry.Losses,
+		MatchID:        matchID,
+		Phase:          phase,
+		CapturedAt:     capturedAt,
+	}
+}
+
+// InsertTrackRankSnapshot stores one ranked snapshot. Snapshots bound to a match are written at most once per phase.
+func (db *Database) InsertTrackRankSnapshot(ctx context.Context, snapshot TrackRankSnapshot) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	normalizeTrackRankSnapshot(&snapshot)
+	if snapshot.PUUID == "" || snapshot.QueueType == "" {
+		return fmt.Errorf("insert track rank snapshot: puuid and queue type are required")
+	}
+	query := `
+	INSERT INTO track_rank_snapshots (platform_region, puuid, queue_type, tier, division, league_points, wins, losses, match_id, phase, captured_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (puuid, queue_type, match_id, phase) WHERE match_id <> '' DO NOTHING`
+	if _, err := db.pool.Exec(ctx, query,
+		snapshot.PlatformRegion, snapshot.PUUID, snapshot.QueueType, snapshot.Tier, snapshot.Rank,
+		snapshot.LeaguePoints, snapshot.Wins, snapshot.Losses, snapshot.MatchID, snapshot.Phase, snapshot.CapturedAt,
+	); err != nil {
+		return fmt.Errorf("insert track rank snapshot %s/%s: %w", snapshot.PUUID, snapshot.QueueType, err)
+	}
+	return nil
+}
+
+func (db *Database) TrackRankSnapshotForMatch(ctx context.Context, puuid, queueType, matchID, phase string) (TrackRankSnapshot, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return TrackRankSnapshot{}, false, err
+	}
+
+	puuid, queueType = strings.TrimSpace(puuid), strings.TrimSpace(queueType)
+	matchID, phase = strings.TrimSpace(matchID), strings.TrimSpace(phase)
+	query := `
+	SELECT ` + trackRankSnapshotColumns + `
+	FROM track_rank_snapshots
+	WHERE puuid = $1
+	AND queue_type = $2
+	AND match_id = $3
+	AND phase = $4`
+	snapshot, found, err := scanTrackRankSnapshot(db.pool.QueryRow(ctx, query, puuid, queueType, matchID, phase))
+	if err != nil {
+		return TrackRankSnapshot{}, false, fmt.Errorf("get track rank snapshot %s/%s: %w", matchID, phase, err)
+	}
+	return snapshot, found, nil
+}
+
+// LatestTrackRankSnapshot returns the most recent snapshot that does not belong to excludeMatchID.
+func (db *Database) LatestTrackRankSnapshot(ctx context.Context, puuid, queueType, excludeMatchID string) (TrackRankSnapshot, bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return TrackRankSnapshot{}, false, err
+	}
+
+	puuid, queueType, excludeMatchID = strings.TrimSpace(puuid), strings.TrimSpace(queueType), strings.TrimSpace(excludeMatchID)
+	query := `
+	SELECT ` + trackRankSnapshotColumns + `
+	FROM track_rank_snapshots
+	WHERE puuid = $1
+	AND queue_type = $2
+	AND ($3 = '' OR match_id <> $3)
+	ORDER BY captured_at DESC, id DESC
+	LIMIT 1`
+	snapshot, found, err := scanTrackRankSnapshot(db.pool.QueryRow(ctx, query, puuid, queueType, excludeMatchID))
+	if err != nil {
+		return TrackRankSnapshot{}, false, fmt.Errorf("get latest track rank snapshot %s/%s: %w", puuid, queueType, err)
+	}
+	return snapshot, found, nil
+}
+
+// ListTrackRankSnapshots returns every snapshot captured since the given time, oldest first.
+func (db *Database) ListTrackRankSnapshots(ctx context.Context, puuid, queueType string, since time.Time) ([]TrackRankSnapshot, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	puuid, queueType = strings.TrimSpace(puuid), strings.TrimSpace(queueType)
+	query := `
+	SELECT ` + trackRankSnapshotColumns + `
+	FROM track_rank_snapshots
+	WHERE puuid = $1
+	AND queue_type = $2
+	AND captured_at >= $3
+	ORDER BY captured_at ASC, id ASC`
+	rows, err := db.pool.Query(ctx, query, puuid, queueType, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("list track rank snapshots %s/%s: %w", puuid, queueType, err)
+	}
+	defer rows.Close()
+
+	out := make([]TrackRankSnapshot, 0)
+	for rows.Next() {
+		snapshot, _, err := scanTrackRankSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan track rank snapshot: %w", err)
+		}
+		out = append(out, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate track rank snapshots: %w", err)
+	}
+	return out, nil
+}
+
+// TrackLeaderboardEntry is a tracked account with its latest stored snapshot of one queue. Snapshot is nil
+// when none was stored for the account yet.
+type TrackLeaderboardEntry struct {
+	Account  TrackedAccount
+	Snapshot *TrackRankSnapshot
+}
+
+// ListTrackLeaderboardEntries returns every tracked account of the guild with its latest snapshot of the
+// queue, so a leaderboard can be ranked without asking Riot about each account.
+func (db *Database) ListTrackLeaderboardEntries(ctx context.Context, guildID, queueType string) ([]TrackLeaderboardEntry, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	guildID, queueType = strings.TrimSpace(guildID), strings.TrimSpace(queueType)
+	query := `
+	SELECT a.guild_id,
+		a.platform_region,
+		a.puuid,
+		a.game_name,
+		a.tag_line,
+		a.added_by,
+		a.added_at,
+		COALESCE(s.platform_region, ''),
+		COALESCE(s.tier, ''),
+		COALESCE(s.division, ''),
+		COALESCE(s.league_points, 0),
+		COALESCE(s.wins, 0),
+		COALESCE(s.losses, 0),
+		COALESCE(s.match_id, ''),
+		COALESCE(s.phase, ''),
+		s.captured_at
+	FROM track_accounts a
+	LEFT JOIN LATERAL (
+		SELECT ` + trackRankSnapshotColumns + `
+		FROM track_rank_snapshots
+		WHERE puuid = a.puuid
+		AND queue_type = $2
+		ORDER BY captured_at DESC, id DESC
+		LIMIT 1
+	) s ON true
+	WHERE a.guild_id = $1
+	ORDER BY a.game_name, a.tag_line, a.puuid`
+	rows, err := db.pool.Query(ctx, query, guildID, queueType)
+	if err != nil {
+		return nil, fmt.Errorf("list track leaderboard entries for %s: %w", guildID, err)
+	}
+	defer rows.Close()
+
+	out := make([]TrackLeaderboardEntry, 0)
+	for rows.Next() {
+		var entry TrackLeaderboardEntry
+		var snapshot TrackRankSnapshot
+		var capturedAt *time.Time
+		account := &entry.Account
+		if err := rows.Scan(
+			&account.GuildID, &account.PlatformRegion, &account.PUUID, &account.NickName, &account.TagLine, &account.AddedBy, &account.AddedAt,
+			&snapshot.PlatformRegion, &snapshot.Tier, &snapshot.Rank, &snapshot.LeaguePoints, &snapshot.Wins, &snapshot.Losses,
+			&snapshot.MatchID, &snapshot.Phase, &capturedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan track leaderboard entry: %w", err)
+		}
+		account.AddedAt = account.AddedAt.UTC()
+		if capturedAt != nil {
+			snapshot.PUUID, snapshot.QueueType, snapshot.CapturedAt = account.PUUID, queueType, capturedAt.UTC()
+			entry.Snapshot = &snapshot
+		}
+		out = append(out, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate track leaderboard entries: %w", err)
+	}
+	return out, nil
+}
+
+const trackRankSnapshotColumns = `platform_region, puuid, queue_type, tier, division, league_points, wins, losses, match_id, phase, captured_at`
+
+func scanTrackRankSnapshot(row pgx.Row) (TrackRankSnapshot, bool, error) {
+	var snapshot TrackRankSnapshot
+	err := row.Scan(
+		&snapshot.PlatformRegion, &snapshot.PUUID, &snapshot.QueueType, &snapshot.Tier, &snapshot.Rank,
+		&snapshot.LeaguePoints, &snapshot.Wins, &snapshot.Losses, &snapshot.MatchID, &snapshot.Phase, &snapshot.CapturedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TrackRankSnapshot{}, false, nil
+		}
+		return TrackRankSnapshot{}, false, err
+	}
+	snapshot.CapturedAt = snapshot.CapturedAt.UTC()
+	return snapshot, true, nil
+}
+
+func normalizeTrackRankSnapshot(s *TrackRankSnapshot) {
+	s.PlatformRegion = riot.NormalizePlatformRegion(s.PlatformRegion)
+	s.PUUID = strings.TrimSpace(s.PUUID)
+	s.QueueType = strings.TrimSpace(s.QueueType)
+	s.Tier = strings.ToUpper(strings.TrimSpace(s.Tier))
+	s.Rank = strings.ToUpper(strings.TrimSpace(s.Rank))
+	s.MatchID = strings.TrimSpace(s.MatchID)
+	s.Phase = strings.TrimSpace(s.Phase)
+	s.CapturedAt = utcNowIfZero(s.CapturedAt)
+}
+
+const createTrackRankSnapshotsSQL = `
+CREATE TABLE IF NOT EXISTS track_rank_snapshots (
+    id bigserial PRIMARY KEY,
+    platform_region text NOT NULL,
+    puuid text NOT NULL,
+    queue_type text NOT NULL,
+    tier text NOT NULL DEFAULT '',
+    division text NOT NULL DEFAULT '',
+    league_points int NOT NULL DEFAULT 0,
+    wins int NOT NULL DEFAULT 0,
+    losses int NOT NULL DEFAULT 0,
+    match_id text NOT NULL DEFAULT '',
+    phase text NOT NULL DEFAULT '',
+    captured_at timestamptz NOT NULL DEFAULT now()
+)`
+
+const createTrackRankSnapshotsLookupIdxSQL = `
+CREATE INDEX IF NOT EXISTS track_rank_snapshots_lookup_idx
+ON track_rank_snapshots (puuid, queue_type, captured_at DESC)`
+
+const createTrackRankSnapshotsMatchIdxSQL = `
+CREATE UNIQUE INDEX IF NOT EXISTS track_rank_snapshots_match_idx
+ON track_rank_snapshots (puuid, queue_type, match_id, phase)
+WHERE match_id <> ''`
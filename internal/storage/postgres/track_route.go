@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Route events group the notifications that can be sent to their own channel.
+// Live and post-game messages share the "game" route so replies and edits stay in one channel.
+const (
+	TrackRouteEventGame   = "game"
+	TrackRouteEventRank   = "rank"
+	TrackRouteEventRename = "rename"
+)
+
+// TrackChannelRoute sends one event, optionally narrowed to a queue or queue category, to a channel
+// other than the guild default. QueueKind is empty for routes that match every queue.
+type TrackChannelRoute struct {
+	GuildID    string
+	Event      string
+	QueueKind  string
+	QueueValue string
+	ChannelID  string
+	UpdatedAt  time.Time
+}
+
+func (db *Database) UpsertTrackChannelRoute(ctx context.Context, route TrackChannelRoute) error {
+	if err := db.ensureReady(); err != nil {
+		return err
+	}
+
+	normalizeTrackChannelRoute(&route)
+	query := `
+	INSERT INTO track_channel_routes (guild_id, event, queue_kind, queue_value, channel_id)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (guild_id, event, queue_kind, queue_value) DO UPDATE
+	SET channel_id = excluded.channel_id,
+		updated_at = now()`
+	if _, err := db.pool.Exec(ctx, query, route.GuildID, route.Event, route.QueueKind, route.QueueValue, route.ChannelID); err != nil {
+		return fmt.Errorf("upsert track channel route %s/%s: %w", route.GuildID, route.Event, err)
+	}
+	return nil
+}
+
+func (db *Database) RemoveTrackChannelRoute(ctx context.Context, guildID, event, queueKind, queueValue string) (bool, error) {
+	if err := db.ensureReady(); err != nil {
+		return false, err
+	}
+
+	route := TrackChannelRoute{GuildID: guildID, Event: event, QueueKind: queueKind, QueueValue: queueValue}
+	normalizeTrackChannelRoute(&route)
+	query := `
+	DELETE FROM track_channel_routes
+	WHERE guild_id = $1
+	AND event = $2
+	AND queue_kind = $3
+	AND queue_value = $4`
+	result, err := db.pool.Exec(ctx, query, route.GuildID, route.Event, route.QueueKind, route.QueueValue)
+	if err != nil {
+		return false, fmt.Errorf("remove track channel route %s/%s: %w", route.GuildID, route.Event, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// RemoveTrackChannelRoutesByChannel drops every route of a guild that points at channelID.
+func (db *Database) RemoveTrackChannelRoutesByChannel(ctx context.Context, guildID, channelID string) (int64, error) {
+	if err := db.ensureReady(); err != nil {
+		return 0, err
+	}
+
+	guildID, channelID = strings.TrimSpace(guildID), strings.TrimSpace(channelID)
+	query := `
+	DELETE FROM track_channel_routes
+	WHERE guild_id = $1
+	AND channel_id = $2`
+	result, err := db.pool.Exec(ctx, query, guildID, channelID)
+	if err != nil {
+		return 0, fmt.Errorf("remove track channel routes %s/%s: %w", guildID, channelID, err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (db *Database) ListTrackChannelRoutes(ctx context.Context, guildID string) ([]TrackChannelRoute, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	query := `
+	SELECT ` + trackChannelRouteColumns + `
+	FROM track_channel_routes
+	WHERE guild_id = $1
+	ORDER BY event, queue_kind, queue_value`
+	return db.queryTrackChannelRoutes(ctx, query, "list track channel routes for "+guildID, guildID)
+}
+
+// ListAllTrackChannelRoutes returns the routes of every guild, for the notification loop.
+func (db *Database) ListAllTrackChannelRoutes(ctx context.Context) ([]TrackChannelRoute, error) {
+	if err := db.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT ` + trackChannelRouteColumns + `
+	FROM track_channel_routes
+	ORDER BY guild_id, event, queue_kind, queue_value`
+	return db.queryTrackChannelRoutes(ctx, query, "list all track channel routes")
+}
+
+const trackChannelRouteColumns = `guild_id, event, queue_kind, queue_value, channel_id, updated_at`
+
+func (db *Database) queryTrackChannelRoutes(ctx context.Context, query, op string, args ...any) ([]TrackChannelRoute, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByPos[TrackChannelRoute])
+	if err != nil {
+		return nil, fmt.Errorf("collect track channel routes: %w", err)
+	}
+	for i := range out {
+		out[i].UpdatedAt = out[i].UpdatedAt.UTC()
+	}
+	return out, nil
+}
+
+func normalizeTrackChannelRoute(r *TrackChannelRoute) {
+	r.GuildID = strings.TrimSpace(r.GuildID)
+	r.Event = strings.ToLower(strings.TrimSpace(r.Event))
+	r.QueueKind = strings.ToLower(strings.TrimSpace(r.QueueKind))
+	r.QueueValue = strings.TrimSpace(r.QueueValue)
+	if r.QueueKind == TrackQueueFilterKindCategory {
+		r.QueueValue = strings.ToLower(r.QueueValue)
+	}
+	if r.QueueKind == "" {
+		r.QueueValue = ""
+	}
+	r.ChannelID = strings.TrimSpace(r.ChannelID)
+}
+
+const createTrackChannelRoutesSQL = `
+CREATE TABLE IF NOT EXISTS track_channel_routes (
+    guild_id text NOT NULL REFERENCES track_guild_config (guild_id) ON DELETE CASCADE,
+    event text NOT NULL,
+    queue_kind text NOT NULL DEFAULT '',
+    queue_value text NOT NULL DEFAULT '',
+    channel_id text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (guild_id, event, queue_kind, queue_value)
+)`
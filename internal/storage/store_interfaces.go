@@ -21,21 +21,33 @@ type SearchDB interface {
 type TrackDB interface {
 	UpsertTrackGuildConfig(ctx context.Context, guildID, channelID string) error
 	DisableTrackGuildConfig(ctx context.Context, guildID string) error
+	SetTrackGuildPostMode(ctx context.Context, guildID, mode string) error
+	SetTrackGuildAnnounceRenames(ctx context.Context, guildID string, enabled bool) error
 	TrackGuildConfig(ctx context.Context, guildID string) (postgres.TrackGuildConfig, bool, error)
 	AddTrackedAccount(ctx context.Context, account postgres.TrackedAccount) (bool, error)
 	RemoveTrackedAccount(ctx context.Context, guildID, riotID string) (bool, error)
 	ListTrackedAccounts(ctx context.Context, guildID string, limit int) ([]postgres.TrackedAccount, error)
+	ListTrackedAccountsPage(ctx context.Context, guildID string, page postgres.TrackedAccountPage) ([]postgres.TrackedAccount, int, error)
+	ListTrackLeaderboardEntries(ctx context.Context, guildID, queueType string) ([]postgres.TrackLeaderboardEntry, error)
+	UpsertTrackChannelRoute(ctx context.Context, route postgres.TrackChannelRoute) error
+	RemoveTrackChannelRoute(ctx context.Context, guildID, event, queueKind, queueValue string) (bool, error)
+	ListTrackChannelRoutes(ctx context.Context, guildID string) ([]postgres.TrackChannelRoute, error)
 }
 
 type TrackNotifyDB interface {
 	DisableTrackGuildConfig(ctx context.Context, guildID string) error
 	ListTrackNotificationTargets(ctx context.Context) ([]postgres.TrackNotificationTarget, error)
+	UpdateTrackedAccountRiotID(ctx context.Context, platformRegion, puuid, gameName, tagLine string) ([]string, error)
 	UpsertTrackMatchNotificationLive(ctx context.Context, input postgres.UpsertTrackMatchLiveInput) (postgres.TrackMatchNotification, error)
 	MarkTrackMatchLivePosted(ctx context.Context, key postgres.TrackMatchNotificationKey, channelID, messageID string, postedAt time.Time) error
 	ListPendingTrackMatchNotifications(ctx context.Context, now time.Time, limit int) ([]postgres.TrackMatchNotification, error)
 	MarkTrackMatchPostRetry(ctx context.Context, key postgres.TrackMatchNotificationKey, attempts int, nextAttemptAt time.Time, lastError string) error
 	MarkTrackMatchPostPosted(ctx context.Context, key postgres.TrackMatchNotificationKey, messageID string, postedAt time.Time) error
 	AbandonTrackMatchNotification(ctx context.Context, key postgres.TrackMatchNotificationKey, abandonedAt time.Time, lastError string) error
+	ListTrackCatchUpCursors(ctx context.Context) ([]postgres.TrackCatchUpCursor, error)
+	AdvanceTrackCatchUpCursors(ctx context.Context, guildID string, puuids []string, seenAt time.Time) error
+	ListTrackMatchNotificationGuilds(ctx context.Context, matchIDs []string) (map[string][]string, error)
+	InsertTrackMatchCatchUp(ctx context.Context, input postgres.UpsertTrackMatchLiveInput) (bool, error)
 	GetTrackMatchSnapshot(ctx context.Context, matchID string) (riot.MatchDetail, bool, error)
 	UpsertTrackMatchSnapshot(ctx context.Context, match riot.MatchDetail) error
 	CleanupTrackMatchNotifications(ctx context.Context, olderThan time.Time) (int64, error)
@@ -45,10 +57,65 @@ type TrackNotifyDB interface {
 	RuneTreeDisplayByIDs(ctx context.Context, ids []int) (map[int]postgres.RuneTreeDisplay, error)
 	RuneDisplayByIDs(ctx context.Context, ids []int) (map[int]postgres.RuneDisplay, error)
 	ItemDisplayByIDs(ctx context.Context, ids []int) (map[int]postgres.ItemDisplay, error)
+	ListAllTrackQueueFilters(ctx context.Context) ([]postgres.TrackQueueFilter, error)
+	ListAllTrackChannelRoutes(ctx context.Context) ([]postgres.TrackChannelRoute, error)
+	RemoveTrackChannelRoutesByChannel(ctx context.Context, guildID, channelID string) (int64, error)
+}
+
+type RankHistoryDB interface {
+	InsertTrackRankSnapshot(ctx context.Context, snapshot postgres.TrackRankSnapshot) error
+	TrackRankSnapshotForMatch(ctx context.Context, puuid, queueType, matchID, phase string) (postgres.TrackRankSnapshot, bool, error)
+	LatestTrackRankSnapshot(ctx context.Context, puuid, queueType, excludeMatchID string) (postgres.TrackRankSnapshot, bool, error)
+}
+
+type HistoryDB interface {
+	GetTrackMatchSnapshots(ctx context.Context, matchIDs []string) (map[string]riot.MatchDetail, error)
+	UpsertTrackMatchSnapshot(ctx context.Context, match riot.MatchDetail) error
+	ChampionDisplayByIDs(ctx context.Context, ids []int) (map[int]postgres.ChampionDisplay, error)
+	QueueDisplayByID(ctx context.Context, queueID int) (postgres.QueueDisplay, bool, error)
+}
+
+type MasteryDB interface {
+	GetChampionMasteries(ctx context.Context, platformRegion, puuid string) ([]riot.ChampionMastery, int, time.Time, bool, error)
+	UpsertChampionMasteries(ctx context.Context, platformRegion, puuid string, masteries []riot.ChampionMastery, score int, fetchedAt, expiresAt time.Time) error
+	ChampionDisplayByIDs(ctx context.Context, ids []int) (map[int]postgres.ChampionDisplay, error)
+}
+
+type RankGraphDB interface {
+	TrackedAccountByRiotID(ctx context.Context, guildID, riotID string) (postgres.TrackedAccount, bool, error)
+	ListTrackRankSnapshots(ctx context.Context, puuid, queueType string, since time.Time) ([]postgres.TrackRankSnapshot, error)
+}
+
+type TrackFilterDB interface {
+	AddTrackQueueFilter(ctx context.Context, filter postgres.TrackQueueFilter) error
+	RemoveTrackQueueFilter(ctx context.Context, guildID, puuid, kind, value string) (bool, error)
+	ClearTrackQueueFilters(ctx context.Context, guildID, puuid string) (int64, error)
+	ListTrackQueueFilters(ctx context.Context, guildID string) ([]postgres.TrackQueueFilter, error)
+	TrackedAccountByRiotID(ctx context.Context, guildID, riotID string) (postgres.TrackedAccount, bool, error)
+	SearchQueueDisplays(ctx context.Context, query string, limit int) ([]postgres.QueueDisplay, error)
+	QueueCategories(ctx context.Context) ([]string, error)
+	QueueDisplayByID(ctx context.Context, queueID int) (postgres.QueueDisplay, bool, error)
+}
+
+type AccountLinkDB interface {
+	UpsertAccountLinkVerification(ctx context.Context, verification postgres.AccountLinkVerification) error
+	AccountLinkVerification(ctx context.Context, userID string) (postgres.AccountLinkVerification, bool, error)
+	CompleteAccountLink(ctx context.Context, userID string, verifiedAt time.Time) (postgres.AccountLink, bool, error)
+	AccountLinkByUser(ctx context.Context, userID string) (postgres.AccountLink, bool, error)
+	UpdateAccountLinkRiotID(ctx context.Context, userID, gameName, tagLine string) error
+	RemoveAccountLink(ctx context.Context, userID string) (bool, error)
+	SetAccountProfile(ctx context.Context, profile postgres.AccountProfile) error
+	RemoveAccountProfile(ctx context.Context, userID string) (bool, error)
+	ResolveUserAccount(ctx context.Context, guildID, userID string) (postgres.UserAccount, bool, error)
 }
 
 type CommandDB interface {
 	FreeWeekDB
 	SearchDB
 	TrackDB
+	HistoryDB
+	MasteryDB
+	RankGraphDB
+	TrackFilterDB
+	AccountLinkDB
 }
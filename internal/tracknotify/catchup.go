@@ -0,0 +1,322 @@
+package tracknotify
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultCatchUpInterval = 15 * time.Minute
+	catchUpTimeout         = 10 * time.Minute
+	// catchUpLookupLimit is the most match IDs one match-v5 lookup returns.
+	catchUpLookupLimit = 100
+	// catchUpMatchLimit bounds the games replayed per account and run; the rest wait for the next run.
+	catchUpMatchLimit = 10
+)
+
+type catchUpAccount struct {
+	Since   time.Time
+	Cursors map[string]time.Time
+	Clamped bool
+}
+
+type catchUpMatch struct {
+	Detail riot.MatchDetail
+	Err    error
+}
+
+func (s *Service) catchUpDue(now time.Time) bool {
+	if s.catchUpInterval <= 0 {
+		return false
+	}
+	return s.lastCatchUp.IsZero() || now.Sub(s.lastCatchUp) >= s.catchUpInterval
+}
+
+// startCatchUp runs a due catch-up sweep in the background with its own deadline, since replaying the
+// games missed during downtime can outlast a loop tick and must not delay the posts of games followed
+// live. The sweep is only marked done once it completes, so one cut short is tried again on a later tick.
+func (s *Service) startCatchUp(parent context.Context, targets []postgres.TrackNotificationTarget, now time.Time) {
+	s.catchUpMu.Lock()
+	defer s.catchUpMu.Unlock()
+	if s.catchUpRunning || !s.catchUpDue(now) {
+		return
+	}
+	s.catchUpRunning = true
+	s.jobs.Go(func() {
+		ctx, cancel := context.WithTimeout(riot.WithPriority(parent, riot.PriorityBackground), catchUpTimeout)
+		defer cancel()
+		err := s.catchUpMissedGames(ctx, targets, now)
+
+		s.catchUpMu.Lock()
+		defer s.catchUpMu.Unlock()
+		s.catchUpRunning = false
+		if err != nil {
+			s.logger.Warn("Track notify catch-up failed", "error", err)
+			return
+		}
+		s.lastCatchUp = now
+	})
+}
+
+// catchUpMissedGames looks up the games tracked accounts finished without a notification, typically while
+// the bot was offline, and queues them for the post-game pipeline as catch-up notifications. Games are
+// replayed oldest first from each account cursor, at most catchUpMatchLimit per account and run, and the
+// cursors of every tracked player of a replayed game move past it. It fails when the sweep ran out of
+// time or its notifications could not be looked up.
+func (s *Service) catchUpMissedGames(ctx context.Context, targets []postgres.TrackNotificationTarget, now time.Time) error {
+	cursors, err := s.database.ListTrackCatchUpCursors(ctx)
+	if err != nil {
+		return fmt.Errorf("list track catch-up cursors: %w", err)
+	}
+	accounts := catchUpAccounts(cursors, now)
+	if clamped := countClampedAccounts(accounts); clamped > 0 {
+		s.logger.Warn("Catch-up skips games older than the notification retention", "accounts", clamped, "retentionDays", defaultRetentionDays)
+	}
+	matchIDs := s.fetchCatchUpMatchIDs(ctx, accounts)
+	ids := make([]string, 0)
+	for _, accountIDs := range matchIDs {
+		for _, matchID := range accountIDs {
+			if !slices.Contains(ids, matchID) {
+				ids = append(ids, matchID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		s.logger.Debug("Track notify catch-up summary", "accounts", len(accounts), "matches", 0, "queued", 0)
+		return ctx.Err()
+	}
+	known, err := s.database.ListTrackMatchNotificationGuilds(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("list known track match notifications: %w", err)
+	}
+
+	targetsByAccount := make(map[targetProbeKey][]postgres.TrackNotificationTarget)
+	guildsByPUUID := make(map[string][]string)
+	for _, target := range targets {
+		key := targetProbeKey{PlatformRegion: target.PlatformRegion, PUUID: target.PUUID}
+		targetsByAccount[key] = append(targetsByAccount[key], target)
+		guildsByPUUID[target.PUUID] = append(guildsByPUUID[target.PUUID], target.GuildID)
+	}
+	filters := s.loadQueueFilters(ctx)
+	routes := s.loadChannelRoutes(ctx)
+	matches := make(map[string]catchUpMatch)
+	queued := 0
+	for key, accountIDs := range matchIDs {
+		replayed := 0
+		for _, matchID := range accountIDs {
+			candidates := slices.DeleteFunc(slices.Clone(targetsByAccount[key]), func(target postgres.TrackNotificationTarget) bool {
+				_, tracked := accounts[key].Cursors[target.GuildID]
+				return !tracked || slices.Contains(known[matchID], target.GuildID)
+			})
+			if len(candidates) == 0 {
+				continue
+			}
+			if replayed >= catchUpMatchLimit {
+				break
+			}
+			replayed++
+			match, ok := matches[matchID]
+			if !ok {
+				match.Detail, match.Err = s.resolvePostMatch(ctx, postgres.TrackMatchNotification{MatchID: matchID}, riot.PlatformContinent(key.PlatformRegion))
+				matches[matchID] = match
+				if match.Err != nil {
+					s.logger.Warn("Failed to fetch catch-up match", "matchID", matchID, "error", match.Err)
+				}
+			}
+			if match.Err != nil {
+				if httpStatusIs(match.Err, http.StatusNotFound) {
+					continue
+				}
+				// Later games wait for the next run, so the cursors never move past this one.
+				break
+			}
+			failed := false
+			for _, target := range candidates {
+				inserted, err := s.queueCatchUp(ctx, target, match.Detail, accounts[key].Cursors[target.GuildID], filters, routes, now)
+				if err != nil {
+					failed = true
+					continue
+				}
+				if inserted {
+					known[matchID] = append(known[matchID], target.GuildID)
+					queued++
+				}
+			}
+			if failed {
+				break
+			}
+			s.advanceCatchUpCursors(ctx, match.Detail, guildsByPUUID)
+		}
+	}
+	s.logger.Debug("Track notify catch-up summary", "accounts", len(accounts), "matches", len(ids), "queued", queued)
+	return ctx.Err()
+}
+
+// catchUpAccounts merges the per-guild cursors of every tracked account, so each account is looked up
+// once from its oldest cursor. Cursors are clamped to the notification retention: older games may have
+// been notified already, and their rows are gone.
+func catchUpAccounts(cursors []postgres.TrackCatchUpCursor, now time.Time) map[targetProbeKey]*catchUpAccount {
+	oldest := now.AddDate(0, 0, -defaultRetentionDays)
+	out := make(map[targetProbeKey]*catchUpAccount)
+	for _, cursor := range cursors {
+		if cursor.GuildID == "" || cursor.PlatformRegion == "" || cursor.PUUID == "" {
+			continue
+		}
+		since, clamped := cursor.Since, cursor.Since.Before(oldest)
+		if clamped {
+			since = oldest
+		}
+		key := targetProbeKey{PlatformRegion: cursor.PlatformRegion, PUUID: cursor.PUUID}
+		account, ok := out[key]
+		if !ok {
+			account = &catchUpAccount{Since: since, Cursors: make(map[string]time.Time)}
+			out[key] = account
+		}
+		if since.Before(account.Since) {
+			account.Since = since
+		}
+		account.Clamped = account.Clamped || clamped
+		account.Cursors[cursor.GuildID] = since
+	}
+	return out
+}
+
+func countClampedAccounts(accounts map[targetProbeKey]*catchUpAccount) int {
+	count := 0
+	for _, account := range accounts {
+		if account.Clamped {
+			count++
+		}
+	}
+	return count
+}
+
+// fetchCatchUpMatchIDs returns the match IDs of every account since its cursor, oldest first.
+func (s *Service) fetchCatchUpMatchIDs(ctx context.Context, accounts map[targetProbeKey]*catchUpAccount) map[targetProbeKey][]string {
+	results := make(map[targetProbeKey][]string, len(accounts))
+	var mu sync.Mutex
+	var g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(defaultFetchLimit)
+	for key, account := range accounts {
+		continent := riot.PlatformContinent(key.PlatformRegion)
+		if continent == "" {
+			continue
+		}
+		g.Go(func() error {
+			matchIDs, err := s.riot.FetchMatchIDsByPUUID(gctx, continent, key.PUUID, riot.MatchIDsFilter{
+				Count:     catchUpLookupLimit,
+				StartTime: account.Since,
+			})
+			if err != nil {
+				s.logger.Warn("Catch-up match lookup failed", "platformRegion", key.PlatformRegion, "puuid", key.PUUID, "error", err)
+				return nil
+			}
+			if len(matchIDs) == 0 {
+				return nil
+			}
+			if len(matchIDs) >= catchUpLookupLimit {
+				s.logger.Warn("Catch-up skips games beyond the match lookup limit", "platformRegion", key.PlatformRegion, "puuid", key.PUUID, "since", account.Since, "limit", catchUpLookupLimit)
+			}
+			slices.Reverse(matchIDs)
+			mu.Lock()
+			results[key] = matchIDs
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+// advanceCatchUpCursors moves the cursors of every tracked player of the match past its end, in each
+// guild that tracks them.
+func (s *Service) advanceCatchUpCursors(ctx context.Context, match riot.MatchDetail, guildsByPUUID map[string][]string) {
+	seenAt := cmp.Or(match.Info.GameEndTimestamp, match.Info.GameStartTimestamp)
+	if seenAt <= 0 {
+		return
+	}
+	puuidsByGuild := make(map[string][]string)
+	for _, player := range match.Info.Players {
+		for _, guildID := range guildsByPUUID[player.PUUID] {
+			puuidsByGuild[guildID] = append(puuidsByGuild[guildID], player.PUUID)
+		}
+	}
+	for guildID, puuids := range puuidsByGuild {
+		if err := s.database.AdvanceTrackCatchUpCursors(ctx, guildID, puuids, time.UnixMilli(seenAt).UTC()); err != nil {
+			s.logger.Warn("Failed to advance track catch-up cursors", "guildID", guildID, "matchID", match.Metadata.MatchID, "error", err)
+		}
+	}
+}
+
+// queueCatchUp stores a catch-up notification of the match for one guild and reports whether it was new.
+// Games that started before the guild cursor, or that the guild would not have been notified about live,
+// are skipped. An error means the game should be looked at again on the next run.
+func (s *Service) queueCatchUp(ctx context.Context, target postgres.TrackNotificationTarget, match riot.MatchDetail, since time.Time, filters *queueFilterSet, routes *channelRouteSet, now time.Time) (bool, error) {
+	platformID, gameID, ok := riot.ParseMatchID(match.Metadata.MatchID)
+	if !ok || match.Info.GameStartTimestamp < since.UnixMilli() {
+		return false, nil
+	}
+	queueDisplay, found, err := s.database.QueueDisplayByID(ctx, match.Info.QueueID)
+	if err != nil {
+		s.logger.Warn("Failed to load catch-up queue display", "matchID", match.Metadata.MatchID, "queueID", match.Info.QueueID, "error", err)
+		return false, err
+	}
+	if !found || !queueSupportsPostGame(queueDisplay.GameSelectCategory) {
+		return false, nil
+	}
+	if !filters.allows(target.GuildID, target.PUUID, match.Info.QueueID, queueDisplay.GameSelectCategory) {
+		return false, nil
+	}
+	channelID := routes.channelFor(target.GuildID, target.ChannelID, postgres.TrackRouteEventGame, match.Info.QueueID, queueDisplay.GameSelectCategory)
+	if strings.TrimSpace(channelID) == "" {
+		return false, nil
+	}
+
+	inserted, err := s.database.InsertTrackMatchCatchUp(ctx, postgres.UpsertTrackMatchLiveInput{
+		GuildID:        target.GuildID,
+		PlatformID:     platformID,
+		GameID:         gameID,
+		MatchID:        match.Metadata.MatchID,
+		QueueID:        match.Info.QueueID,
+		QueueCategory:  queueDisplay.GameSelectCategory,
+		PlayerPUUID:    target.PUUID,
+		PlayerRiotID:   target.RiotID(),
+		TrackedCount:   1,
+		LiveChannelID:  channelID,
+		LastLiveSeenAt: now,
+	})
+	if err != nil {
+		s.logger.Warn("Failed to queue catch-up notification", "guildID", target.GuildID, "matchID", match.Metadata.MatchID, "error", err)
+		return false, err
+	}
+	if inserted {
+		s.logger.Info("Queued catch-up notification", "guildID", target.GuildID, "matchID", match.Metadata.MatchID, "puuid", target.PUUID)
+	}
+	return inserted, nil
+}
+
+// markCatchUpEmbed flags a post-game embed of a game that was not followed live.
+func markCatchUpEmbed(embed *discordgo.MessageEmbed, match riot.MatchDetail) {
+	if embed == nil {
+		return
+	}
+	if embed.Author != nil {
+		embed.Author.Name += " · Catch-up"
+	}
+	line := "⏪ Missed live, caught up after the game."
+	if end := match.Info.GameEndTimestamp; end > 0 {
+		line = fmt.Sprintf("⏪ Missed live, the game ended <t:%d:R>.", end/1000)
+	}
+	embed.Description = line + "\n\n" + embed.Description
+}
@@ -0,0 +1,190 @@
+package tracknotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCatchUpAccountsMergesCursors(t *testing.T) {
+	now := time.Now().UTC()
+	accounts := catchUpAccounts([]postgres.TrackCatchUpCursor{
+		{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1", Since: now.Add(-time.Hour)},
+		{GuildID: "g2", PlatformRegion: "br1", PUUID: "p1", Since: now.AddDate(0, 0, -30)},
+		{GuildID: "g1", PlatformRegion: "kr", PUUID: "p2", Since: now.Add(-time.Minute)},
+		{GuildID: "", PlatformRegion: "kr", PUUID: "p3", Since: now},
+	}, now)
+
+	if len(accounts) != 2 {
+		t.Fatalf("accounts = %+v, want 2", accounts)
+	}
+	account := accounts[targetProbeKey{PlatformRegion: "br1", PUUID: "p1"}]
+	if oldest := now.AddDate(0, 0, -defaultRetentionDays); !account.Since.Equal(oldest) || !account.Cursors["g2"].Equal(oldest) || !account.Clamped {
+		t.Fatalf("account = %+v, want the cursor clamped to %v", account, oldest)
+	}
+	if !account.Cursors["g1"].Equal(now.Add(-time.Hour)) {
+		t.Fatalf("g1 cursor = %v, want %v", account.Cursors["g1"], now.Add(-time.Hour))
+	}
+	if other := accounts[targetProbeKey{PlatformRegion: "kr", PUUID: "p2"}]; other.Clamped {
+		t.Fatalf("account = %+v, want it not clamped", other)
+	}
+}
+
+func TestCatchUpMissedGamesQueuesUnseenMatches(t *testing.T) {
+	now := time.Now().UTC()
+	riotAPI := &fakeRiotAPI{matchIDs: []string{"BR1_3", "BR1_2", "BR1_1"}}
+	db := &postPublishTestDB{
+		catchUpCursors: []postgres.TrackCatchUpCursor{
+			{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1", Since: now.Add(-3 * time.Hour)},
+			{GuildID: "g2", PlatformRegion: "br1", PUUID: "p1", Since: now.Add(-30 * time.Minute)},
+		},
+		knownMatches: map[string][]string{"BR1_2": {"g1"}},
+		snapshots: map[string]riot.MatchDetail{
+			"BR1_1": catchUpTestMatch("BR1_1", 420, now.Add(-2*time.Hour)),
+			"BR1_2": catchUpTestMatch("BR1_2", 420, now.Add(-90*time.Minute)),
+			"BR1_3": catchUpTestMatch("BR1_3", 450, now.Add(-time.Hour)),
+		},
+		queues: map[int]postgres.QueueDisplay{
+			420: {Name: "Ranked Solo/Duo", GameSelectCategory: queueCategoryPvP},
+			450: {Name: "ARAM", GameSelectCategory: "kaprs"},
+		},
+	}
+	service := newPostTestService(db, io.Discard)
+	service.riot = riotAPI
+
+	err := service.catchUpMissedGames(context.Background(), []postgres.TrackNotificationTarget{
+		{GuildID: "g1", ChannelID: "c1", PlatformRegion: "br1", PUUID: "p1", NickName: "Ahri", TagLine: "BR1"},
+		{GuildID: "g2", ChannelID: "c2", PlatformRegion: "br1", PUUID: "p1", NickName: "Ahri", TagLine: "BR1"},
+	}, now)
+	if err != nil {
+		t.Fatalf("catchUpMissedGames() error = %v", err)
+	}
+
+	if len(db.catchUps) != 1 {
+		t.Fatalf("catch-ups = %+v, want only BR1_1 for g1", db.catchUps)
+	}
+	got := db.catchUps[0]
+	if got.GuildID != "g1" || got.MatchID != "BR1_1" || got.PlatformID != "BR1" || got.GameID != 1 || got.LiveChannelID != "c1" || got.PlayerRiotID != "Ahri#BR1" {
+		t.Fatalf("catch-up = %+v", got)
+	}
+	if seen := db.catchUpSeen["g1/p1"]; !seen.Equal(now.Add(-time.Hour + 30*time.Minute).Truncate(time.Millisecond)) {
+		t.Fatalf("g1 cursor = %v, want the end of BR1_3", seen)
+	}
+}
+
+func TestStartCatchUpMarksOnlyCompletedSweeps(t *testing.T) {
+	now := time.Now().UTC()
+	targets := []postgres.TrackNotificationTarget{{GuildID: "g1", ChannelID: "c1", PlatformRegion: "br1", PUUID: "p1", NickName: "Ahri", TagLine: "BR1"}}
+	db := &postPublishTestDB{
+		catchUpErr:     errors.New("database unavailable"),
+		catchUpCursors: []postgres.TrackCatchUpCursor{{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1", Since: now.Add(-3 * time.Hour)}},
+		knownMatches:   map[string][]string{},
+		snapshots:      map[string]riot.MatchDetail{"BR1_1": catchUpTestMatch("BR1_1", 420, now.Add(-2*time.Hour))},
+		queues:         map[int]postgres.QueueDisplay{420: {Name: "Ranked Solo/Duo", GameSelectCategory: queueCategoryPvP}},
+	}
+	service := newPostTestService(db, io.Discard)
+	service.riot = &fakeRiotAPI{matchIDs: []string{"BR1_1"}}
+	service.catchUpInterval = time.Hour
+
+	service.startCatchUp(t.Context(), targets, now)
+	service.jobs.Wait()
+	if !service.lastCatchUp.IsZero() || service.catchUpRunning {
+		t.Fatalf("lastCatchUp = %v, running = %v; want the failed sweep due again", service.lastCatchUp, service.catchUpRunning)
+	}
+
+	db.catchUpErr = nil
+	service.startCatchUp(t.Context(), targets, now)
+	service.jobs.Wait()
+	if !service.lastCatchUp.Equal(now) || len(db.catchUps) != 1 {
+		t.Fatalf("lastCatchUp = %v, catch-ups = %+v; want the sweep marked done", service.lastCatchUp, db.catchUps)
+	}
+}
+
+func TestCatchUpMissedGamesReplaysOldestFirst(t *testing.T) {
+	tests := []struct {
+		name    string
+		missing string
+		want    int
+	}{
+		{"match limit", "", catchUpMatchLimit},
+		{"stops at an unavailable match", "BR1_4", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now().UTC()
+			var matchIDs []string
+			snapshots := make(map[string]riot.MatchDetail)
+			for idx := range catchUpMatchLimit + 5 {
+				matchID := fmt.Sprintf("BR1_%d", idx+1)
+				matchIDs = append([]string{matchID}, matchIDs...)
+				match := catchUpTestMatch(matchID, 420, now.Add(-time.Duration(40-idx)*time.Hour))
+				match.Info.Players = append(match.Info.Players, riot.MatchPlayer{PUUID: "p2"})
+				snapshots[matchID] = match
+			}
+			delete(snapshots, tt.missing)
+			db := &postPublishTestDB{
+				catchUpCursors: []postgres.TrackCatchUpCursor{{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1", Since: now.Add(-48 * time.Hour)}},
+				knownMatches:   map[string][]string{},
+				snapshots:      snapshots,
+				queues:         map[int]postgres.QueueDisplay{420: {Name: "Ranked Solo/Duo", GameSelectCategory: queueCategoryPvP}},
+			}
+			service := newPostTestService(db, io.Discard)
+			service.riot = &fakeRiotAPI{matchIDs: matchIDs}
+
+			err := service.catchUpMissedGames(context.Background(), []postgres.TrackNotificationTarget{
+				{GuildID: "g1", ChannelID: "c1", PlatformRegion: "br1", PUUID: "p1", NickName: "Ahri", TagLine: "BR1"},
+				{GuildID: "g2", ChannelID: "c2", PlatformRegion: "br1", PUUID: "p2", NickName: "Zed", TagLine: "BR1"},
+			}, now)
+			if err != nil {
+				t.Fatalf("catchUpMissedGames() error = %v", err)
+			}
+
+			if len(db.catchUps) != tt.want {
+				t.Fatalf("catch-ups = %d, want %d", len(db.catchUps), tt.want)
+			}
+			for idx, catchUp := range db.catchUps {
+				if want := fmt.Sprintf("BR1_%d", idx+1); catchUp.MatchID != want {
+					t.Fatalf("catch-up %d = %s, want %s", idx, catchUp.MatchID, want)
+				}
+			}
+			end := snapshots[fmt.Sprintf("BR1_%d", tt.want)].Info.GameEndTimestamp
+			for _, key := range []string{"g1/p1", "g2/p2"} {
+				if seen := db.catchUpSeen[key]; seen.UnixMilli() != end {
+					t.Fatalf("%s cursor = %v, want the end of the last replayed game", key, seen)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkCatchUpEmbed(t *testing.T) {
+	embed := &discordgo.MessageEmbed{Author: &discordgo.MessageEmbedAuthor{Name: "Post Game"}, Description: "Won a game."}
+	markCatchUpEmbed(embed, riot.MatchDetail{Info: riot.MatchInfo{GameEndTimestamp: 1_700_000_000_000}})
+
+	if embed.Author.Name != "Post Game · Catch-up" {
+		t.Fatalf("author = %q", embed.Author.Name)
+	}
+	if !strings.HasPrefix(embed.Description, "⏪ Missed live, the game ended <t:1700000000:R>.\n\nWon a game.") {
+		t.Fatalf("description = %q", embed.Description)
+	}
+}
+
+func catchUpTestMatch(matchID string, queueID int, start time.Time) riot.MatchDetail {
+	return riot.MatchDetail{
+		Metadata: riot.MatchMetadata{MatchID: matchID},
+		Info: riot.MatchInfo{
+			QueueID:            queueID,
+			GameStartTimestamp: start.UnixMilli(),
+			GameEndTimestamp:   start.Add(30 * time.Minute).UnixMilli(),
+			Players:            []riot.MatchPlayer{{PUUID: "p1"}},
+		},
+	}
+}
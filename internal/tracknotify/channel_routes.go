@@ -0,0 +1,77 @@
+package tracknotify
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+// channelRouteSet indexes the per-guild routing table. A nil set always returns the default channel.
+type channelRouteSet struct {
+	byGuild map[string][]postgres.TrackChannelRoute
+}
+
+func newChannelRouteSet(routes []postgres.TrackChannelRoute) *channelRouteSet {
+	set := &channelRouteSet{byGuild: make(map[string][]postgres.TrackChannelRoute)}
+	for _, route := range routes {
+		guildID := strings.TrimSpace(route.GuildID)
+		if guildID == "" || strings.TrimSpace(route.ChannelID) == "" {
+			continue
+		}
+		set.byGuild[guildID] = append(set.byGuild[guildID], route)
+	}
+	return set
+}
+
+// loadChannelRoutes fetches the routing table. On error everything goes to the default channel.
+func (s *Service) loadChannelRoutes(ctx context.Context) *channelRouteSet {
+	routes, err := s.database.ListAllTrackChannelRoutes(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load track channel routes", "error", err)
+		return nil
+	}
+	return newChannelRouteSet(routes)
+}
+
+// channelFor picks the most specific route for the event: a queue route beats a category route,
+// which beats an any-queue route. Without a match the guild default channel is used.
+func (r *channelRouteSet) channelFor(guildID, defaultChannelID, event string, queueID int, category string) string {
+	if r == nil {
+		return defaultChannelID
+	}
+	best, bestScore := defaultChannelID, -1
+	for _, route := range r.byGuild[strings.TrimSpace(guildID)] {
+		if route.Event != event {
+			continue
+		}
+		score := 0
+		switch route.QueueKind {
+		case "":
+		case postgres.TrackQueueFilterKindQueue, postgres.TrackQueueFilterKindCategory:
+			if !queueSelectorMatches(route.QueueKind, route.QueueValue, queueID, category) {
+				continue
+			}
+			score = 1
+			if route.QueueKind == postgres.TrackQueueFilterKindQueue {
+				score = 2
+			}
+		default:
+			continue
+		}
+		if score > bestScore {
+			best, bestScore = strings.TrimSpace(route.ChannelID), score
+		}
+	}
+	return best
+}
+
+// rankedQueueIDForType maps a league-v4 queue type back to the match queue ID, for routing rank changes.
+func rankedQueueIDForType(queueType string) int {
+	for queueID, rankedType := range rankedQueueTypes {
+		if rankedType == queueType {
+			return queueID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package tracknotify
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestChannelRouteSetChannelFor(t *testing.T) {
+	routes := newChannelRouteSet([]postgres.TrackChannelRoute{
+		{GuildID: "g1", Event: postgres.TrackRouteEventGame, QueueKind: "category", QueueValue: "kpvp", ChannelID: "games"},
+		{GuildID: "g1", Event: postgres.TrackRouteEventGame, QueueKind: "queue", QueueValue: "420", ChannelID: "ranked"},
+		{GuildID: "g1", Event: postgres.TrackRouteEventGame, QueueKind: "queue", QueueValue: "440", ChannelID: "ranked"},
+		{GuildID: "g1", Event: postgres.TrackRouteEventRank, ChannelID: "announcements"},
+		{GuildID: "g1", Event: postgres.TrackRouteEventRename, ChannelID: "names"},
+		{GuildID: "g2", Event: postgres.TrackRouteEventGame, ChannelID: "everything"},
+	})
+
+	tests := []struct {
+		name     string
+		guildID  string
+		event    string
+		queueID  int
+		category string
+		want     string
+	}{
+		{name: "queue route wins", guildID: "g1", event: "game", queueID: 420, category: "kPvP", want: "ranked"},
+		{name: "category route", guildID: "g1", event: "game", queueID: 400, category: "kPvP", want: "games"},
+		{name: "no match uses default", guildID: "g1", event: "game", queueID: 830, category: "kVersusAi", want: "default"},
+		{name: "rank event any queue", guildID: "g1", event: "rank", queueID: 420, category: "kpvp", want: "announcements"},
+		{name: "rename event", guildID: "g1", event: "rename", want: "names"},
+		{name: "any queue route", guildID: "g2", event: "game", queueID: 450, category: "kPvP", want: "everything"},
+		{name: "other guild", guildID: "g3", event: "game", queueID: 420, want: "default"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := routes.channelFor(tc.guildID, "default", tc.event, tc.queueID, tc.category); got != tc.want {
+				t.Fatalf("channelFor() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	var none *channelRouteSet
+	if got := none.channelFor("g1", "default", "game", 420, ""); got != "default" {
+		t.Fatalf("nil route set channelFor() = %q, want default", got)
+	}
+}
+
+func TestRankedQueueIDForType(t *testing.T) {
+	if rankedQueueIDForType(soloQueueType) != 420 || rankedQueueIDForType(flexQueueType) != 440 || rankedQueueIDForType("CHERRY") != 0 {
+		t.Fatalf("rankedQueueIDForType() mapped queue types incorrectly")
+	}
+}
+
+func TestDisableGuildTrackingOnAccessLossRemovesRouteFirst(t *testing.T) {
+	accessErr := &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: 50001}}
+
+	db := &postPublishTestDB{routesRemoved: 2}
+	service := newPostTestService(db, io.Discard)
+	if !service.disableGuildTrackingOnAccessLoss(context.Background(), "g1", "ranked", accessErr) {
+		t.Fatalf("disableGuildTrackingOnAccessLoss() = false, want true")
+	}
+	if len(db.disabledGuilds) != 0 {
+		t.Fatalf("guild disabled although only a route was broken: %v", db.disabledGuilds)
+	}
+
+	db = &postPublishTestDB{}
+	service = newPostTestService(db, io.Discard)
+	if !service.disableGuildTrackingOnAccessLoss(context.Background(), "g1", "default", accessErr) {
+		t.Fatalf("disableGuildTrackingOnAccessLoss() = false, want true")
+	}
+	if len(db.disabledGuilds) != 1 || db.disabledGuilds[0] != "g1" {
+		t.Fatalf("disabledGuilds = %v, want [g1]", db.disabledGuilds)
+	}
+}
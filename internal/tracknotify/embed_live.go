@@ -91,7 +91,7 @@ func (s *Service) fetchSoloEntries(ctx context.Context, platformRegion string, p
 		}
 
 		g.Go(func() error {
-			entries, err := riot.FetchLeagueEntriesByPUUID(gctx, platformRegion, puuid, s.riotAPIKey)
+			entries, err := s.riot.FetchLeagueEntriesByPUUID(gctx, platformRegion, puuid)
 			if err != nil {
 				return nil
 			}
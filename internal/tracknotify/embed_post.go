@@ -12,7 +12,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
-func (s *Service) buildPostEmbed(ctx context.Context, notification postgres.TrackMatchNotification, match riot.MatchDetail, queueName string) (*discordgo.MessageEmbed, error) {
+func (s *Service) buildPostEmbed(ctx context.Context, notification postgres.TrackMatchNotification, match riot.MatchDetail, queueName string, change *rankChange) (*discordgo.MessageEmbed, error) {
 	player := findMatchPlayer(match.Info.Players, notification.PlayerPUUID, notification.PlayerRiotID)
 	if player == nil {
 		return nil, fmt.Errorf("player not found")
@@ -69,7 +69,7 @@ func (s *Service) buildPostEmbed(ctx context.Context, notification postgres.Trac
 
 	fields := []*discordgo.MessageEmbedField{
 		{Name: "Champion", Value: championToken(player.ChampionID, champions), Inline: true},
-		{Name: "KDA", Value: formatKDA(*player), Inline: true},
+		{Name: "KDA", Value: riot.FormatKDA(*player), Inline: true},
 		{Name: "Summoners", Value: summonerSpellTokens(*player, spells), Inline: false},
 	}
 	if primaryStyle != nil {
@@ -92,18 +92,25 @@ func (s *Service) buildPostEmbed(ctx context.Context, notification postgres.Trac
 		fields = append(fields, &discordgo.MessageEmbedField{Name: "🔴 Bans", Value: redBans, Inline: true})
 	}
 
+	description := fmt.Sprintf(
+		"%s a %s game.\n\n**Match Duration**: %s.",
+		winWord, queueName, riot.FormatDuration(riot.MatchDurationSeconds(match.Info)),
+	)
+	if change != nil {
+		if line := formatRankChange(*change); line != "" {
+			description = fmt.Sprintf("%s\n**LP**: %s", description, line)
+		}
+	}
+
 	embed := &discordgo.MessageEmbed{
 		Author: &discordgo.MessageEmbedAuthor{
 			Name:    "Post Game",
 			IconURL: authorIcon,
 		},
-		Color: color,
-		Title: playerTitle,
-		Description: fmt.Sprintf(
-			"%s a %s game.\n\n**Match Duration**: %s.",
-			winWord, queueName, formatDuration(matchDurationSeconds(match.Info)),
-		),
-		Fields: fields,
+		Color:       color,
+		Title:       playerTitle,
+		Description: description,
+		Fields:      fields,
 	}
 	if playerProfileIconURL != "" {
 		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
@@ -207,14 +214,6 @@ func summonerSpellTokens(player riot.MatchPlayer, spells map[int]postgres.Summon
 	return strings.Join(tokens, " ")
 }
 
-func formatKDA(player riot.MatchPlayer) string {
-	ratio := float64(player.Kills + player.Assists)
-	if player.Deaths > 0 {
-		ratio /= float64(player.Deaths)
-	}
-	return fmt.Sprintf("%d/%d/%d %.2f:1", player.Kills, player.Deaths, player.Assists, ratio)
-}
-
 func perkStyles(styles []riot.MatchPerkStyle) (primary *riot.MatchPerkStyle, secondary *riot.MatchPerkStyle) {
 	for idx := range styles {
 		description := strings.ToLower(strings.TrimSpace(styles[idx].Description))
@@ -329,22 +328,3 @@ func bansLinePost(teams []riot.MatchTeam, teamID int, champions map[int]postgres
 	}
 	return ""
 }
-
-func matchDurationSeconds(info riot.MatchInfo) int64 {
-	if info.GameDuration > 0 {
-		return info.GameDuration
-	}
-	if info.GameStartTimestamp > 0 && info.GameEndTimestamp > info.GameStartTimestamp {
-		return (info.GameEndTimestamp - info.GameStartTimestamp) / 1000
-	}
-	return 0
-}
-
-func formatDuration(seconds int64) string {
-	if seconds < 0 {
-		seconds = 0
-	}
-	minutes := seconds / 60
-	rem := seconds % 60
-	return fmt.Sprintf("%dm%ds", minutes, rem)
-}
@@ -0,0 +1,132 @@
+package tracknotify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/discord/embedtest"
+	"github.com/bingbr/League-API-bot/internal/httprecord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+// newFixtureRiotClient answers Riot requests from the hand-written fixtures in testdata/riot, which
+// follow the Riot API payloads for made-up players and matches. They are not recordings: the riot
+// client is pointed at the riot-fixtures.test host so nothing mistakes them for real responses.
+func newFixtureRiotClient() *riot.Client {
+	replayer := httprecord.New(filepath.Join("testdata", "riot"), httprecord.ModeReplay)
+	return riot.NewClient(riot.WithAPIKey("test-key"), riot.WithBaseURLTemplate("https://riot-fixtures.test/{routing}"), riot.WithHTTPClient(replayer.Client()))
+}
+
+// embedDisplayTestDB serves the display names and icons of a few champions, spells, runes and items,
+// leaving the others to the embed fallbacks.
+type embedDisplayTestDB struct {
+	*postPublishTestDB
+}
+
+func (embedDisplayTestDB) ChampionDisplayByIDs(context.Context, []int) (map[int]postgres.ChampionDisplay, error) {
+	return map[int]postgres.ChampionDisplay{
+		245: {ChampionID: 245, Name: "Ekko", DiscordIcon: "<:Ekko:1>"},
+		157: {ChampionID: 157, Name: "Yasuo", DiscordIcon: "<:Yasuo:2>"},
+		64:  {ChampionID: 64, Name: "Lee Sin", DiscordIcon: "<:LeeSin:3>"},
+	}, nil
+}
+
+func (embedDisplayTestDB) SummonerSpellDisplayByIDs(context.Context, []int) (map[int]postgres.SummonerSpellDisplay, error) {
+	return map[int]postgres.SummonerSpellDisplay{4: {SpellID: 4, Name: "Flash", DiscordIcon: "<:SummonerFlash:4>"}}, nil
+}
+
+func (embedDisplayTestDB) RuneTreeDisplayByIDs(context.Context, []int) (map[int]postgres.RuneTreeDisplay, error) {
+	return map[int]postgres.RuneTreeDisplay{8100: {TreeID: 8100, Name: "Domination", DiscordIcon: "<:Domination:5>"}}, nil
+}
+
+func (embedDisplayTestDB) RuneDisplayByIDs(context.Context, []int) (map[int]postgres.RuneDisplay, error) {
+	return map[int]postgres.RuneDisplay{8112: {RuneID: 8112, TreeID: 8100, Name: "Electrocute", DiscordIcon: "<:Electrocute:6>"}}, nil
+}
+
+func (embedDisplayTestDB) ItemDisplayByIDs(context.Context, []int) (map[int]postgres.ItemDisplay, error) {
+	return map[int]postgres.ItemDisplay{3152: {ItemID: "3152", Name: "Hextech Rocketbelt"}, 3020: {ItemID: "3020", Name: "Sorcerer's Shoes"}}, nil
+}
+
+func (embedDisplayTestDB) MapDisplayByID(_ context.Context, mapID int) (postgres.MapDisplay, bool, error) {
+	if mapID != 11 {
+		return postgres.MapDisplay{}, false, nil
+	}
+	return postgres.MapDisplay{MapID: 11, Name: "Summoner's Rift"}, true, nil
+}
+
+func (embedDisplayTestDB) RankIconsByTiers(context.Context, []string) (map[string]string, error) {
+	return map[string]string{"gold": "<:Gold:7>"}, nil
+}
+
+func TestBuildPostEmbedFromMatchFixture(t *testing.T) {
+	db := &postPublishTestDB{}
+	service := newPostTestService(db, io.Discard)
+	service.database = embedDisplayTestDB{db}
+	service.riot = newFixtureRiotClient()
+	ctx := t.Context()
+	notification := postgres.TrackMatchNotification{GuildID: "g1", PlatformID: "BR1", GameID: 3001, MatchID: "BR1_3001", PlayerPUUID: "puuid-bekko"}
+
+	match, err := service.resolvePostMatch(ctx, notification, "americas")
+	if err != nil {
+		t.Fatalf("resolvePostMatch() error = %v", err)
+	}
+	after, err := service.fetchQueueEntry(ctx, "br1", "puuid-bekko", soloQueueType)
+	if err != nil {
+		t.Fatalf("fetchQueueEntry() error = %v", err)
+	}
+	before := after
+	before.LeaguePoints, before.Wins = after.LeaguePoints-19, after.Wins-1
+
+	embed, err := service.buildPostEmbed(ctx, notification, match, "Ranked Solo/Duo", &rankChange{Before: before, After: after})
+	if err != nil {
+		t.Fatalf("buildPostEmbed() error = %v", err)
+	}
+	embedtest.Golden(t, "post_ranked_win", embed)
+}
+
+func TestBuildLiveEmbedGolden(t *testing.T) {
+	db := &postPublishTestDB{}
+	service := newPostTestService(db, io.Discard)
+	service.database = embedDisplayTestDB{db}
+	service.riot = &fakeRiotAPI{leagueEntries: []riot.LeagueEntry{
+		{QueueType: soloQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 36, Wins: 41, Losses: 38},
+	}}
+	riotIDs := []string{
+		"Bekko#Ekko", "Ahri#BR1", "ThisRiotIDIsFarTooLongToFit#BR1", "페이커는최고의선수입니다#KR1", "Mid or Feed#0001",
+		"Yasuo Main#Wind", "Lee Sin#Kick", "ＦｕｌｌＷｉｄｔｈＰｌａｙｅｒ#JP1", "", "Support Diff#SUP",
+	}
+	champions := []int{245, 103, 157, 64, 99, 157, 64, 1, 412, 267}
+	players := make([]riot.LiveGamePlayer, 0, len(riotIDs))
+	for idx, riotID := range riotIDs {
+		teamID := 100
+		if idx >= 5 {
+			teamID = 200
+		}
+		puuid := ""
+		if riotID != "" {
+			puuid = fmt.Sprintf("puuid-%d", idx)
+		}
+		players = append(players, riot.LiveGamePlayer{PUUID: puuid, TeamID: teamID, ChampionID: champions[idx], RiotID: riotID})
+	}
+	match := &liveGuildMatch{
+		GuildID:        "g1",
+		PlatformRegion: "br1",
+		Game: &riot.LiveGame{
+			GameID:          3002,
+			MapID:           11,
+			Players:         players,
+			BannedChampions: []riot.LiveGameBan{{ChampionID: 157, TeamID: 100}, {ChampionID: 64, TeamID: 200}, {ChampionID: 555, TeamID: 200}},
+		},
+		TrackedByPUUID: map[string]string{"puuid-0": "Bekko#Ekko", "puuid-1": "Ahri#BR1"},
+	}
+
+	embed, err := service.buildLiveEmbed(t.Context(), match, "Bekko#Ekko", "Ranked Solo/Duo")
+	if err != nil {
+		t.Fatalf("buildLiveEmbed() error = %v", err)
+	}
+	embedtest.Golden(t, "live_ranked_full_lobby", embed)
+}
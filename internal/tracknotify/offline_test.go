@@ -0,0 +1,267 @@
+package tracknotify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/riot/riotfake"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestServiceFollowsGameAgainstRiotStandIn(t *testing.T) {
+	server, err := riotfake.NewServer(riotfake.Fixtures{
+		Accounts: []riot.RiotAccount{{PUUID: "puuid-bekko", GameName: "Bekko", TagLine: "Ekko"}},
+		LeagueEntries: map[string][]riot.LeagueEntry{
+			"puuid-bekko": {{QueueType: soloQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 36, Wins: 40, Losses: 38}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	db := &offlineTestDB{
+		postPublishTestDB: &postPublishTestDB{
+			queues:       map[int]postgres.QueueDisplay{420: {Name: "Ranked Solo/Duo", GameSelectCategory: queueCategoryPvP}},
+			knownMatches: map[string][]string{},
+		},
+		targets: []postgres.TrackNotificationTarget{{
+			GuildID: "g1", ChannelID: "c1", PlatformRegion: "br1", PUUID: "puuid-bekko", NickName: "Bekko", TagLine: "Ekko",
+		}},
+	}
+	discordAPI := newDiscordStandIn()
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: discordAPI}
+	riotClient := riot.NewClient(riot.WithAPIKey("test-key"), riot.WithBaseURLTemplate(httpServer.URL+"/{routing}"))
+	service := NewService(db, session, riotClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.pollInterval = time.Second
+	// Probe on every tick instead of spreading the probes over minutes of wall time.
+	service.probes = nil
+	tick := func() {
+		t.Helper()
+		service.runOnce(t.Context())
+		service.jobs.Wait()
+	}
+
+	tick()
+	if sent := discordAPI.sent(); len(sent) != 0 {
+		t.Fatalf("messages before the game = %+v, want none", sent)
+	}
+	if _, found, _ := db.LatestTrackRankSnapshot(t.Context(), "puuid-bekko", soloQueueType, ""); !found {
+		t.Fatalf("rank check stored no solo queue snapshot before the game")
+	}
+
+	server.StartGame(riot.LiveGame{
+		GameID:            4001,
+		PlatformID:        "BR1",
+		GameQueueConfigID: 420,
+		Players:           []riot.LiveGamePlayer{{PUUID: "puuid-bekko", TeamID: 200, ChampionID: 245, RiotID: "Bekko#Ekko"}},
+	})
+	// The first live probe after a scripted 429 is retried by the client.
+	server.LimitNext(1, time.Second, riotfake.LimitTypeMethod)
+	tick()
+	tick()
+	sent := discordAPI.sent()
+	if len(sent) != 1 || sent[0].ChannelID != "c1" {
+		t.Fatalf("messages in game = %+v, want one live post in c1", sent)
+	}
+	live := db.notification(postgres.TrackMatchNotificationKey{GuildID: "g1", PlatformID: "BR1", GameID: 4001})
+	if live == nil || live.LiveMessageID != sent[0].ID || live.PostPostedAt != nil {
+		t.Fatalf("notification in game = %+v, want the live message %q and no post", live, sent[0].ID)
+	}
+	start, found, _ := db.TrackRankSnapshotForMatch(t.Context(), "puuid-bekko", soloQueueType, "BR1_4001", postgres.TrackRankPhaseStart)
+	if !found || start.LeaguePoints != 36 {
+		t.Fatalf("start rank snapshot = %+v, %v, want GOLD II 36 LP", start, found)
+	}
+
+	if _, ok := server.EndGame(4001, riot.MatchDetail{}); !ok {
+		t.Fatalf("EndGame() found no game")
+	}
+	server.SetLeagueEntries("puuid-bekko", []riot.LeagueEntry{{QueueType: soloQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 18, Wins: 40, Losses: 39}})
+	tick()
+	sent = discordAPI.sent()
+	if len(sent) != 2 {
+		t.Fatalf("messages after the game = %+v, want the live and the post-game message", sent)
+	}
+	post := sent[1]
+	if post.ChannelID != "c1" || post.Reference == nil || post.Reference.MessageID != sent[0].ID {
+		t.Fatalf("post-game message = %+v, want a reply to the live message", post)
+	}
+	ended := db.notification(live.Key())
+	if ended.PostPostedAt == nil || ended.PostMessageID != post.ID || ended.PostAbandonedAt != nil {
+		t.Fatalf("notification after the game = %+v, want the post %q", ended, post.ID)
+	}
+	if len(db.upsertedSnapshots) != 1 || db.upsertedSnapshots[0].Metadata.MatchID != "BR1_4001" {
+		t.Fatalf("cached match snapshots = %d, want BR1_4001", len(db.upsertedSnapshots))
+	}
+	if end, found, _ := db.TrackRankSnapshotForMatch(t.Context(), "puuid-bekko", soloQueueType, "BR1_4001", postgres.TrackRankPhaseEnd); !found || end.LeaguePoints != 18 {
+		t.Fatalf("end rank snapshot = %+v, %v, want GOLD II 18 LP", end, found)
+	}
+
+	tick()
+	if sent := discordAPI.sent(); len(sent) != 2 {
+		t.Fatalf("messages after the post = %d, want no more", len(sent))
+	}
+}
+
+// offlineTestDB keeps the notification rows of the live and post-game pipeline in memory, so runOnce can
+// follow a game from its first probe to the post-game message.
+type offlineTestDB struct {
+	*postPublishTestDB
+	targets       []postgres.TrackNotificationTarget
+	notifications []postgres.TrackMatchNotification
+}
+
+func (d *offlineTestDB) notification(key postgres.TrackMatchNotificationKey) *postgres.TrackMatchNotification {
+	idx := slices.IndexFunc(d.notifications, func(n postgres.TrackMatchNotification) bool { return n.Key() == key })
+	if idx < 0 {
+		return nil
+	}
+	return &d.notifications[idx]
+}
+
+func (d *offlineTestDB) ListTrackNotificationTargets(context.Context) ([]postgres.TrackNotificationTarget, error) {
+	return d.targets, nil
+}
+
+func (d *offlineTestDB) UpsertTrackMatchNotificationLive(_ context.Context, input postgres.UpsertTrackMatchLiveInput) (postgres.TrackMatchNotification, error) {
+	key := postgres.TrackMatchNotificationKey{GuildID: input.GuildID, PlatformID: input.PlatformID, GameID: input.GameID}
+	if n := d.notification(key); n != nil {
+		n.LastLiveSeenAt = input.LastLiveSeenAt
+		return *n, nil
+	}
+	d.notifications = append(d.notifications, postgres.TrackMatchNotification{
+		GuildID:        input.GuildID,
+		PlatformID:     input.PlatformID,
+		GameID:         input.GameID,
+		MatchID:        input.MatchID,
+		QueueID:        input.QueueID,
+		QueueCategory:  input.QueueCategory,
+		PlayerPUUID:    input.PlayerPUUID,
+		PlayerRiotID:   input.PlayerRiotID,
+		TrackedCount:   input.TrackedCount,
+		LiveChannelID:  input.LiveChannelID,
+		LastLiveSeenAt: input.LastLiveSeenAt,
+		CreatedAt:      input.LastLiveSeenAt,
+	})
+	return d.notifications[len(d.notifications)-1], nil
+}
+
+func (d *offlineTestDB) MarkTrackMatchLivePosted(_ context.Context, key postgres.TrackMatchNotificationKey, channelID, messageID string, postedAt time.Time) error {
+	if n := d.notification(key); n != nil {
+		n.LiveChannelID, n.LiveMessageID, n.LivePostedAt = channelID, messageID, &postedAt
+	}
+	return nil
+}
+
+func (d *offlineTestDB) ListPendingTrackMatchNotifications(_ context.Context, now time.Time, limit int) ([]postgres.TrackMatchNotification, error) {
+	pending := make([]postgres.TrackMatchNotification, 0)
+	for _, n := range d.notifications {
+		if n.LivePostedAt != nil && n.PostPostedAt == nil && n.PostAbandonedAt == nil &&
+			(n.NextPostAttemptAt == nil || !n.NextPostAttemptAt.After(now)) && len(pending) < limit {
+			pending = append(pending, n)
+		}
+	}
+	return pending, nil
+}
+
+func (d *offlineTestDB) MarkTrackMatchPostRetry(_ context.Context, key postgres.TrackMatchNotificationKey, attempts int, nextAttempt time.Time, reason string) error {
+	if n := d.notification(key); n != nil {
+		n.PostAttempts, n.NextPostAttemptAt, n.LastPostError = attempts, &nextAttempt, reason
+	}
+	return nil
+}
+
+func (d *offlineTestDB) MarkTrackMatchPostPosted(_ context.Context, key postgres.TrackMatchNotificationKey, messageID string, postedAt time.Time) error {
+	if n := d.notification(key); n != nil {
+		n.PostMessageID, n.PostPostedAt = messageID, &postedAt
+	}
+	return nil
+}
+
+func (d *offlineTestDB) AbandonTrackMatchNotification(ctx context.Context, key postgres.TrackMatchNotificationKey, abandonedAt time.Time, reason string) error {
+	if n := d.notification(key); n != nil {
+		n.PostAbandonedAt, n.LastPostError = &abandonedAt, reason
+	}
+	return d.postPublishTestDB.AbandonTrackMatchNotification(ctx, key, abandonedAt, reason)
+}
+
+func (d *offlineTestDB) MapDisplayByID(context.Context, int) (postgres.MapDisplay, bool, error) {
+	return postgres.MapDisplay{}, false, nil
+}
+
+func (d *offlineTestDB) SummonerSpellDisplayByIDs(context.Context, []int) (map[int]postgres.SummonerSpellDisplay, error) {
+	return map[int]postgres.SummonerSpellDisplay{}, nil
+}
+
+func (d *offlineTestDB) RuneTreeDisplayByIDs(context.Context, []int) (map[int]postgres.RuneTreeDisplay, error) {
+	return map[int]postgres.RuneTreeDisplay{}, nil
+}
+
+func (d *offlineTestDB) RuneDisplayByIDs(context.Context, []int) (map[int]postgres.RuneDisplay, error) {
+	return map[int]postgres.RuneDisplay{}, nil
+}
+
+func (d *offlineTestDB) ItemDisplayByIDs(context.Context, []int) (map[int]postgres.ItemDisplay, error) {
+	return map[int]postgres.ItemDisplay{}, nil
+}
+
+// discordStandIn answers the message sends of a discordgo session in process and remembers them.
+type discordStandIn struct {
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	messages []sentMessage
+}
+
+type sentMessage struct {
+	discordgo.MessageSend
+	ID        string
+	ChannelID string
+}
+
+func newDiscordStandIn() *discordStandIn {
+	d := &discordStandIn{mux: http.NewServeMux()}
+	d.mux.HandleFunc("POST /api/{version}/channels/{channelID}/messages", func(w http.ResponseWriter, r *http.Request) {
+		var send discordgo.MessageSend
+		if err := json.NewDecoder(r.Body).Decode(&send); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		d.mu.Lock()
+		message := sentMessage{MessageSend: send, ID: strconv.Itoa(len(d.messages) + 1), ChannelID: r.PathValue("channelID")}
+		d.messages = append(d.messages, message)
+		d.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(discordgo.Message{ID: message.ID, ChannelID: message.ChannelID, Embeds: send.Embeds})
+	})
+	return d
+}
+
+func (d *discordStandIn) RoundTrip(r *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	d.mux.ServeHTTP(recorder, r)
+	return recorder.Result(), nil
+}
+
+func (d *discordStandIn) sent() []sentMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.messages)
+}
@@ -0,0 +1,236 @@
+package tracknotify
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	probeIntervalInGame  = 30 * time.Second
+	probeIntervalWarm    = time.Minute
+	probeIntervalCool    = 5 * time.Minute
+	probeIntervalCold    = 15 * time.Minute
+	probeHotWindow       = time.Hour
+	probeWarmWindow      = 24 * time.Hour
+	probeCoolWindow      = 7 * 24 * time.Hour
+	probeActivityRefresh = 2 * time.Hour
+	// probeBudgetShare keeps part of the rate limit free for commands and post-game lookups.
+	probeBudgetShare = 0.5
+	// probeDefaultRequestRate stands in for a host without known limits: the two-minute application
+	// limit of a development key, the smallest Riot hands out.
+	probeDefaultRequestRate = 100.0 / 120
+
+	spectatorEndpointPath = "/lol/spectator/v5/active-games/by-summoner/"
+	summonerEndpointPath  = "/lol/summoner/v4/summoners/by-puuid/"
+)
+
+// probeState is what the scheduler remembers about a tracked account between ticks.
+type probeState struct {
+	NextProbe time.Time
+	// Game is the live game the account was last seen in, nil when it was not in game.
+	Game     *riot.LiveGame
+	LastLive time.Time
+	// LastActive is the summoner revision date, which moves when the account finishes a game.
+	LastActive        time.Time
+	ActivityCheckedAt time.Time
+}
+
+// probeBudget is how many spectator probes and summoner lookups fit in one tick on one platform host.
+type probeBudget struct {
+	Probes  int
+	Refresh int
+}
+
+// probeScheduler spreads spectator probes over time: accounts that are in game or played recently are
+// probed every tick, while accounts that have been inactive for days are probed a few times an hour.
+type probeScheduler struct {
+	hot    time.Duration
+	states map[targetProbeKey]*probeState
+}
+
+func newProbeScheduler(hot time.Duration) *probeScheduler {
+	return &probeScheduler{hot: hot, states: make(map[targetProbeKey]*probeState)}
+}
+
+func (p *probeScheduler) interval(state *probeState, now time.Time) time.Duration {
+	if state.Game != nil {
+		return max(p.hot, probeIntervalInGame)
+	}
+	if state.ActivityCheckedAt.IsZero() && state.LastLive.IsZero() {
+		return max(p.hot, probeIntervalWarm)
+	}
+	idle := now.Sub(state.LastActive)
+	if state.LastLive.After(state.LastActive) {
+		idle = now.Sub(state.LastLive)
+	}
+	switch {
+	case idle < probeHotWindow:
+		return p.hot
+	case idle < probeWarmWindow:
+		return max(p.hot, probeIntervalWarm)
+	case idle < probeCoolWindow:
+		return max(p.hot, probeIntervalCool)
+	default:
+		return max(p.hot, probeIntervalCold)
+	}
+}
+
+// plan picks the accounts to probe this tick, most overdue first, and spends what is left of the budget
+// on refreshing the activity of the accounts that have not been refreshed for the longest time. Each
+// platform region spends its own budget, since Riot limits every platform host separately. Accounts
+// that are no longer tracked are forgotten.
+func (p *probeScheduler) plan(keys map[targetProbeKey]struct{}, now time.Time, budgets map[string]probeBudget) (probes, refresh map[targetProbeKey]struct{}) {
+	if p == nil {
+		return keys, nil
+	}
+	for key := range p.states {
+		if _, tracked := keys[key]; !tracked {
+			delete(p.states, key)
+		}
+	}
+
+	due := make(map[string][]targetProbeKey)
+	stale := make(map[string][]targetProbeKey)
+	for key := range keys {
+		state, ok := p.states[key]
+		if !ok {
+			state = &probeState{}
+			p.states[key] = state
+		}
+		if !now.Before(state.NextProbe) {
+			due[key.PlatformRegion] = append(due[key.PlatformRegion], key)
+		}
+		if state.Game == nil && now.Sub(state.ActivityCheckedAt) >= probeActivityRefresh {
+			stale[key.PlatformRegion] = append(stale[key.PlatformRegion], key)
+		}
+	}
+
+	probes = make(map[targetProbeKey]struct{})
+	refresh = make(map[targetProbeKey]struct{})
+	for region, budget := range budgets {
+		regionDue, regionStale := due[region], stale[region]
+		slices.SortFunc(regionDue, func(a, b targetProbeKey) int {
+			return p.states[a].NextProbe.Compare(p.states[b].NextProbe)
+		})
+		slices.SortFunc(regionStale, func(a, b targetProbeKey) int {
+			return p.states[a].ActivityCheckedAt.Compare(p.states[b].ActivityCheckedAt)
+		})
+		planned := min(len(regionDue), budget.Probes)
+		for _, key := range regionDue[:planned] {
+			probes[key] = struct{}{}
+		}
+		refreshed := max(0, min(len(regionStale), budget.Refresh, budget.Probes-planned))
+		for _, key := range regionStale[:refreshed] {
+			refresh[key] = struct{}{}
+		}
+	}
+	return probes, refresh
+}
+
+// record updates the schedule with the probe results: a key mapped to a game is in game, a key mapped to
+// nil is not, and a probed key without a result failed and is retried on the next tick.
+func (p *probeScheduler) record(probed map[targetProbeKey]struct{}, results map[targetProbeKey]*riot.LiveGame, now time.Time) {
+	if p == nil {
+		return
+	}
+	for key := range probed {
+		state, ok := p.states[key]
+		if !ok {
+			continue
+		}
+		game, checked := results[key]
+		if !checked {
+			state.NextProbe = now.Add(p.hot)
+			continue
+		}
+		if game != nil || state.Game != nil {
+			state.LastLive = now
+		}
+		state.Game = game
+		state.NextProbe = now.Add(p.interval(state, now))
+	}
+}
+
+// recordActivity stores the revision date of an account and brings its next probe forward when the
+// account turns out to be more active than its schedule assumed.
+func (p *probeScheduler) recordActivity(key targetProbeKey, revision, now time.Time) {
+	if p == nil {
+		return
+	}
+	state, ok := p.states[key]
+	if !ok {
+		return
+	}
+	state.ActivityCheckedAt = now
+	if revision.After(state.LastActive) {
+		state.LastActive = revision
+	}
+	if next := now.Add(p.interval(state, now)); next.Before(state.NextProbe) {
+		state.NextProbe = next
+	}
+}
+
+// liveGames completes the probe results with the last known game of the accounts that were not probed
+// this tick, so a game that is still running is not mistaken for a finished one.
+func (p *probeScheduler) liveGames(results map[targetProbeKey]*riot.LiveGame) map[targetProbeKey]*riot.LiveGame {
+	if p == nil {
+		return results
+	}
+	for key, state := range p.states {
+		if _, probed := results[key]; !probed && state.Game != nil {
+			results[key] = state.Game
+		}
+	}
+	return results
+}
+
+// probeBudgets derives, for every platform region of the keys, how many spectator probes and summoner
+// lookups fit in one tick from the current rate limits of its host, learned from Riot or configured.
+func (s *Service) probeBudgets(keys map[targetProbeKey]struct{}) map[string]probeBudget {
+	tick := s.pollInterval.Seconds()
+	budgets := make(map[string]probeBudget)
+	for key := range keys {
+		if _, ok := budgets[key.PlatformRegion]; ok {
+			continue
+		}
+		probes := s.requestRate(key.PlatformRegion, spectatorEndpointPath) * tick * probeBudgetShare
+		refresh := s.requestRate(key.PlatformRegion, summonerEndpointPath) * tick * probeBudgetShare
+		budgets[key.PlatformRegion] = probeBudget{Probes: max(1, int(probes)), Refresh: max(0, int(refresh))}
+	}
+	return budgets
+}
+
+func (s *Service) requestRate(platformRegion, path string) float64 {
+	if rate := s.riot.RequestRate(riot.RateLimitEndpoint(platformRegion, path)); rate > 0 {
+		return rate
+	}
+	return probeDefaultRequestRate
+}
+
+func (s *Service) refreshProbeActivity(ctx context.Context, keys map[targetProbeKey]struct{}, now time.Time) {
+	if len(keys) == 0 {
+		return
+	}
+	var mu sync.Mutex
+	var g, gctx = errgroup.WithContext(riot.WithPriority(ctx, riot.PriorityBackground))
+	g.SetLimit(defaultFetchLimit)
+	for key := range keys {
+		g.Go(func() error {
+			summoner, err := s.riot.FetchSummonerByPUUID(gctx, key.PlatformRegion, key.PUUID)
+			if err != nil {
+				s.logger.Warn("Activity check failed", "platformRegion", key.PlatformRegion, "puuid", key.PUUID, "error", err)
+				return nil
+			}
+			mu.Lock()
+			s.probes.recordActivity(key, time.UnixMilli(summoner.RevisionDate).UTC(), now)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
@@ -0,0 +1,133 @@
+package tracknotify
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+)
+
+func TestProbeSchedulerInterval(t *testing.T) {
+	now := time.Now().UTC()
+	scheduler := newProbeScheduler(10 * time.Second)
+	for _, tc := range []struct {
+		name  string
+		state probeState
+		want  time.Duration
+	}{
+		{"in game", probeState{Game: &riot.LiveGame{GameID: 1}}, probeIntervalInGame},
+		{"unknown activity", probeState{}, probeIntervalWarm},
+		{"just finished a game", probeState{LastLive: now.Add(-10 * time.Minute), ActivityCheckedAt: now}, 10 * time.Second},
+		{"played today", probeState{LastActive: now.Add(-5 * time.Hour), ActivityCheckedAt: now}, probeIntervalWarm},
+		{"played this week", probeState{LastActive: now.Add(-72 * time.Hour), ActivityCheckedAt: now}, probeIntervalCool},
+		{"inactive for weeks", probeState{LastActive: now.Add(-30 * 24 * time.Hour), ActivityCheckedAt: now}, probeIntervalCold},
+	} {
+		if got := scheduler.interval(&tc.state, now); got != tc.want {
+			t.Fatalf("%s: interval() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestProbeSchedulerPlanRespectsBudget(t *testing.T) {
+	now := time.Now().UTC()
+	a, b, c := targetProbeKey{"br1", "a"}, targetProbeKey{"br1", "b"}, targetProbeKey{"br1", "c"}
+	scheduler := newProbeScheduler(10 * time.Second)
+	scheduler.states[a] = &probeState{NextProbe: now.Add(-time.Minute), ActivityCheckedAt: now}
+	scheduler.states[b] = &probeState{NextProbe: now.Add(-time.Hour), ActivityCheckedAt: now.Add(-3 * time.Hour)}
+	scheduler.states[c] = &probeState{NextProbe: now.Add(time.Minute), ActivityCheckedAt: now.Add(-5 * time.Hour)}
+	scheduler.states[targetProbeKey{"br1", "gone"}] = &probeState{}
+
+	keys := map[targetProbeKey]struct{}{a: {}, b: {}, c: {}}
+	probes, refresh := scheduler.plan(keys, now, map[string]probeBudget{"br1": {Probes: 1, Refresh: 10}})
+	if _, ok := probes[b]; len(probes) != 1 || !ok || len(refresh) != 0 {
+		t.Fatalf("plan() = %v, %v; want only the most overdue account", probes, refresh)
+	}
+	probes, refresh = scheduler.plan(keys, now, map[string]probeBudget{"br1": {Probes: 3, Refresh: 10}})
+	if _, ok := probes[c]; len(probes) != 2 || ok {
+		t.Fatalf("probes = %v, want the two due accounts", probes)
+	}
+	if _, ok := refresh[c]; len(refresh) != 1 || !ok {
+		t.Fatalf("refresh = %v, want the account with the oldest activity check", refresh)
+	}
+	if len(scheduler.states) != 3 {
+		t.Fatalf("states = %d, want untracked accounts forgotten", len(scheduler.states))
+	}
+
+	var none *probeScheduler
+	if probes, refresh := none.plan(map[targetProbeKey]struct{}{a: {}}, now, nil); len(probes) != 1 || len(refresh) != 0 {
+		t.Fatalf("nil scheduler plan() = %v, %v; want every account probed", probes, refresh)
+	}
+}
+
+func TestProbeSchedulerPlanSpendsBudgetPerRegion(t *testing.T) {
+	now := time.Now().UTC()
+	keys := map[targetProbeKey]struct{}{{"br1", "a"}: {}, {"br1", "b"}: {}, {"kr", "c"}: {}, {"kr", "d"}: {}}
+	scheduler := newProbeScheduler(10 * time.Second)
+
+	probes, _ := scheduler.plan(keys, now, map[string]probeBudget{"br1": {Probes: 2}, "kr": {Probes: 1}})
+	perRegion := map[string]int{}
+	for key := range probes {
+		perRegion[key.PlatformRegion]++
+	}
+	if perRegion["br1"] != 2 || perRegion["kr"] != 1 {
+		t.Fatalf("probes per region = %v, want 2 on br1 and 1 on kr", perRegion)
+	}
+}
+
+func TestProbeBudgetsPerRegion(t *testing.T) {
+	service := newPostTestService(&postPublishTestDB{}, io.Discard)
+	service.pollInterval = 10 * time.Second
+	rates := map[string]float64{
+		riot.RateLimitEndpoint("br1", spectatorEndpointPath): 20,
+		riot.RateLimitEndpoint("br1", summonerEndpointPath):  4,
+	}
+	service.riot = &fakeRiotAPI{requestRates: rates}
+
+	budgets := service.probeBudgets(map[targetProbeKey]struct{}{{"br1", "a"}: {}, {"kr", "b"}: {}})
+	if got := budgets["br1"]; got != (probeBudget{Probes: 100, Refresh: 20}) {
+		t.Fatalf("br1 budget = %+v, want it from the br1 limits", got)
+	}
+	if got := budgets["kr"]; got != (probeBudget{Probes: 4, Refresh: 4}) {
+		t.Fatalf("kr budget = %+v, want the default rate without known limits", got)
+	}
+}
+
+func TestProbeSchedulerKeepsGamesBetweenProbes(t *testing.T) {
+	now := time.Now().UTC()
+	key, failed := targetProbeKey{"br1", "a"}, targetProbeKey{"br1", "b"}
+	scheduler := newProbeScheduler(10 * time.Second)
+	probes, _ := scheduler.plan(map[targetProbeKey]struct{}{key: {}, failed: {}}, now, map[string]probeBudget{"br1": {Probes: 10}})
+
+	game := &riot.LiveGame{GameID: 7}
+	scheduler.record(probes, map[targetProbeKey]*riot.LiveGame{key: game}, now)
+	if state := scheduler.states[key]; state.Game != game || !state.NextProbe.Equal(now.Add(probeIntervalInGame)) {
+		t.Fatalf("in-game state = %+v", state)
+	}
+	if state := scheduler.states[failed]; !state.NextProbe.Equal(now.Add(10 * time.Second)) {
+		t.Fatalf("failed probe next = %v, want a retry on the next tick", state.NextProbe)
+	}
+
+	if games := scheduler.liveGames(map[targetProbeKey]*riot.LiveGame{}); games[key] != game {
+		t.Fatalf("liveGames() = %v, want the cached game of the unprobed account", games)
+	}
+	scheduler.record(map[targetProbeKey]struct{}{key: {}}, map[targetProbeKey]*riot.LiveGame{key: nil}, now.Add(time.Minute))
+	if games := scheduler.liveGames(map[targetProbeKey]*riot.LiveGame{}); len(games) != 0 {
+		t.Fatalf("liveGames() after the game ended = %v, want none", games)
+	}
+}
+
+func TestRefreshProbeActivityBringsNextProbeForward(t *testing.T) {
+	now := time.Now().UTC()
+	key := targetProbeKey{"br1", "a"}
+	service := newPostTestService(&postPublishTestDB{}, io.Discard)
+	service.riot = &fakeRiotAPI{summoner: &riot.SummonerProfile{RevisionDate: now.Add(-5 * time.Minute).UnixMilli()}}
+	service.probes = newProbeScheduler(10 * time.Second)
+	service.probes.states[key] = &probeState{NextProbe: now.Add(probeIntervalCold)}
+	service.refreshProbeActivity(context.Background(), map[targetProbeKey]struct{}{key: {}}, now)
+
+	if state := service.probes.states[key]; !state.NextProbe.Equal(now.Add(10*time.Second)) || !state.ActivityCheckedAt.Equal(now) {
+		t.Fatalf("state = %+v, want a hot schedule after recent activity", state)
+	}
+}
@@ -0,0 +1,110 @@
+package tracknotify
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+type accountFilterKey struct {
+	GuildID string
+	PUUID   string
+}
+
+// queueFilterSet indexes the /track filter rules of every guild. A nil set allows everything.
+type queueFilterSet struct {
+	guild   map[string][]postgres.TrackQueueFilter
+	account map[accountFilterKey][]postgres.TrackQueueFilter
+}
+
+func newQueueFilterSet(filters []postgres.TrackQueueFilter) *queueFilterSet {
+	set := &queueFilterSet{
+		guild:   make(map[string][]postgres.TrackQueueFilter),
+		account: make(map[accountFilterKey][]postgres.TrackQueueFilter),
+	}
+	for _, filter := range filters {
+		guildID, puuid := strings.TrimSpace(filter.GuildID), strings.TrimSpace(filter.PUUID)
+		if guildID == "" {
+			continue
+		}
+		if puuid == "" {
+			set.guild[guildID] = append(set.guild[guildID], filter)
+			continue
+		}
+		key := accountFilterKey{GuildID: guildID, PUUID: puuid}
+		set.account[key] = append(set.account[key], filter)
+	}
+	return set
+}
+
+// loadQueueFilters fetches the current rules. On error every queue is allowed, so a database hiccup
+// never silences notifications.
+func (s *Service) loadQueueFilters(ctx context.Context) *queueFilterSet {
+	filters, err := s.database.ListAllTrackQueueFilters(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load track queue filters", "error", err)
+		return nil
+	}
+	return newQueueFilterSet(filters)
+}
+
+// allows reports whether a game in queueID should be posted for puuid in guildID.
+// Per-account rules replace the guild rules for that account.
+func (f *queueFilterSet) allows(guildID, puuid string, queueID int, category string) bool {
+	if f == nil {
+		return true
+	}
+	guildID, puuid = strings.TrimSpace(guildID), strings.TrimSpace(puuid)
+	rules, ok := f.account[accountFilterKey{GuildID: guildID, PUUID: puuid}]
+	if !ok {
+		rules = f.guild[guildID]
+	}
+	return queueFiltersAllow(rules, queueID, category)
+}
+
+// queueFiltersAllow rejects any excluded queue and, when include rules exist, anything not included.
+func queueFiltersAllow(rules []postgres.TrackQueueFilter, queueID int, category string) bool {
+	hasInclude, included := false, false
+	for _, rule := range rules {
+		matched := queueFilterMatches(rule, queueID, category)
+		switch rule.Mode {
+		case postgres.TrackQueueFilterExclude:
+			if matched {
+				return false
+			}
+		case postgres.TrackQueueFilterInclude:
+			hasInclude = true
+			included = included || matched
+		}
+	}
+	return !hasInclude || included
+}
+
+func queueFilterMatches(rule postgres.TrackQueueFilter, queueID int, category string) bool {
+	return queueSelectorMatches(rule.Kind, rule.Value, queueID, category)
+}
+
+// queueSelectorMatches checks a "queue"/"category" selector shared by filters and channel routes.
+func queueSelectorMatches(kind, value string, queueID int, category string) bool {
+	switch kind {
+	case postgres.TrackQueueFilterKindQueue:
+		return strings.TrimSpace(value) == strconv.Itoa(queueID)
+	case postgres.TrackQueueFilterKindCategory:
+		category = strings.TrimSpace(category)
+		return category != "" && strings.EqualFold(strings.TrimSpace(value), category)
+	default:
+		return false
+	}
+}
+
+// filterTrackedByQueue drops the tracked players whose filters reject the queue and returns how many remain.
+func filterTrackedByQueue(filters *queueFilterSet, guildID string, tracked map[string]string, queueID int, category string) int {
+	for puuid := range tracked {
+		if !filters.allows(guildID, puuid, queueID, category) {
+			delete(tracked, puuid)
+		}
+	}
+	return len(tracked)
+}
@@ -0,0 +1,69 @@
+package tracknotify
+
+import (
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestQueueFiltersAllow(t *testing.T) {
+	include := func(kind, value string) postgres.TrackQueueFilter {
+		return postgres.TrackQueueFilter{Kind: kind, Value: value, Mode: postgres.TrackQueueFilterInclude}
+	}
+	exclude := func(kind, value string) postgres.TrackQueueFilter {
+		return postgres.TrackQueueFilter{Kind: kind, Value: value, Mode: postgres.TrackQueueFilterExclude}
+	}
+
+	tests := []struct {
+		name     string
+		rules    []postgres.TrackQueueFilter
+		queueID  int
+		category string
+		want     bool
+	}{
+		{name: "no rules", queueID: 450, category: "kPvP", want: true},
+		{name: "excluded queue", rules: []postgres.TrackQueueFilter{exclude("queue", "450")}, queueID: 450, want: false},
+		{name: "other queue passes exclude", rules: []postgres.TrackQueueFilter{exclude("queue", "450")}, queueID: 420, want: true},
+		{name: "included queue", rules: []postgres.TrackQueueFilter{include("queue", "420"), include("queue", "400")}, queueID: 400, want: true},
+		{name: "not in include list", rules: []postgres.TrackQueueFilter{include("queue", "420")}, queueID: 450, want: false},
+		{name: "category match ignores case", rules: []postgres.TrackQueueFilter{exclude("category", "kpvp")}, queueID: 1700, category: "kPvP", want: false},
+		{name: "exclude wins over include", rules: []postgres.TrackQueueFilter{include("category", "kpvp"), exclude("queue", "450")}, queueID: 450, category: "kPvP", want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := queueFiltersAllow(tc.rules, tc.queueID, tc.category); got != tc.want {
+				t.Fatalf("queueFiltersAllow() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueueFilterSetAccountOverride(t *testing.T) {
+	set := newQueueFilterSet([]postgres.TrackQueueFilter{
+		{GuildID: "g1", Kind: "queue", Value: "450", Mode: postgres.TrackQueueFilterExclude},
+		{GuildID: "g1", PUUID: "smurf", Kind: "queue", Value: "420", Mode: postgres.TrackQueueFilterInclude},
+	})
+
+	if set.allows("g1", "main", 450, "kPvP") {
+		t.Fatalf("guild rule should exclude ARAM for accounts without overrides")
+	}
+	if !set.allows("g1", "main", 400, "kPvP") {
+		t.Fatalf("guild rule should allow other queues")
+	}
+	if set.allows("g1", "smurf", 400, "kPvP") || !set.allows("g1", "smurf", 420, "kPvP") {
+		t.Fatalf("account override should only allow ranked solo")
+	}
+	if !set.allows("g2", "main", 450, "kPvP") {
+		t.Fatalf("other guilds should not be affected")
+	}
+
+	var none *queueFilterSet
+	if !none.allows("g1", "main", 450, "") {
+		t.Fatalf("nil filter set should allow everything")
+	}
+
+	tracked := map[string]string{"main": "Main#BR1", "smurf": "Smurf#BR1"}
+	if remaining := filterTrackedByQueue(set, "g1", tracked, 400, "kPvP"); remaining != 1 || tracked["main"] == "" {
+		t.Fatalf("filterTrackedByQueue() remaining = %d, tracked = %v", remaining, tracked)
+	}
+}
@@ -0,0 +1,246 @@
+package tracknotify
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultRankCheckInterval = 30 * time.Minute
+	rankCheckTimeout         = 10 * time.Minute
+	rankPromotionColor       = 0xF1C40F
+	rankDemotionColor        = 0x5D6D7E
+)
+
+type rankAnnouncement struct {
+	PUUID  string
+	Change rankChange
+}
+
+func (s *Service) rankCheckDue(now time.Time) bool {
+	if s.rankCheckInterval <= 0 {
+		return false
+	}
+	return s.lastRankCheck.IsZero() || now.Sub(s.lastRankCheck) >= s.rankCheckInterval
+}
+
+// startRankCheck runs a due rank check in the background with its own deadline, since fetching the
+// league entries of every tracked account can outlast a loop tick and must not delay the live posts.
+// The check is only marked done once it completes, so one cut short is tried again on a later tick.
+func (s *Service) startRankCheck(parent context.Context, targets []postgres.TrackNotificationTarget, now time.Time) {
+	s.rankCheckMu.Lock()
+	defer s.rankCheckMu.Unlock()
+	if s.rankCheckRunning || !s.rankCheckDue(now) {
+		return
+	}
+	s.rankCheckRunning = true
+	s.jobs.Go(func() {
+		ctx, cancel := context.WithTimeout(riot.WithPriority(parent, riot.PriorityBackground), rankCheckTimeout)
+		defer cancel()
+		err := s.checkRankChanges(ctx, targets)
+
+		s.rankCheckMu.Lock()
+		defer s.rankCheckMu.Unlock()
+		s.rankCheckRunning = false
+		if err != nil {
+			s.logger.Warn("Track notify rank check failed", "error", err)
+			return
+		}
+		s.lastRankCheck = now
+	})
+}
+
+// checkRankChanges diffs the current league entries of every tracked account against the latest
+// stored snapshot. This catches changes that happen outside tracked games, like decay or placements.
+// It fails when the check ran out of time or a snapshot could not be loaded or stored.
+func (s *Service) checkRankChanges(ctx context.Context, targets []postgres.TrackNotificationTarget) error {
+	entriesByKey := s.fetchTrackedLeagueEntries(ctx, targetProbeKeys(targets))
+	now := time.Now().UTC()
+
+	var storeErr error
+	announcements := make([]rankAnnouncement, 0)
+	for key, entries := range entriesByKey {
+		for _, queueType := range []string{soloQueueType, flexQueueType} {
+			entry := riot.LeagueEntry{QueueType: queueType}
+			if found := riot.QueueEntry(entries, queueType); found != nil {
+				entry = *found
+			}
+
+			announcement, err := s.storePolledRank(ctx, key, entry, now)
+			if err != nil {
+				s.logger.Warn("Failed to update polled rank snapshot", "puuid", key.PUUID, "queueType", queueType, "error", err)
+				storeErr = cmp.Or(storeErr, err)
+				continue
+			}
+			if announcement != nil {
+				announcements = append(announcements, *announcement)
+			}
+		}
+	}
+	s.logger.Debug("Track notify rank check summary", "accounts", len(entriesByKey), "announcements", len(announcements))
+	s.announceRankChanges(ctx, announcements, targets)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return storeErr
+}
+
+// storePolledRank stores entry when it differs from the latest snapshot of the account and returns
+// the announcement for a changed rank step. It holds rankSnapshotMu so a post-game rank resolved at the
+// same time cannot compare against the same snapshot and announce the change twice.
+func (s *Service) storePolledRank(ctx context.Context, key targetProbeKey, entry riot.LeagueEntry, now time.Time) (*rankAnnouncement, error) {
+	s.rankSnapshotMu.Lock()
+	defer s.rankSnapshotMu.Unlock()
+
+	previous, hasPrevious, err := s.database.LatestTrackRankSnapshot(ctx, key.PUUID, entry.QueueType, "")
+	if err != nil {
+		return nil, fmt.Errorf("load latest rank snapshot: %w", err)
+	}
+	if hasPrevious && sameRankState(previous.LeagueEntry(), entry) {
+		return nil, nil
+	}
+
+	snapshot := postgres.NewTrackRankSnapshot(key.PlatformRegion, key.PUUID, entry, "", postgres.TrackRankPhasePoll, now)
+	if err := s.database.InsertTrackRankSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("insert rank snapshot: %w", err)
+	}
+	if !hasPrevious || !rankStepChanged(previous.LeagueEntry(), entry) {
+		return nil, nil
+	}
+	return &rankAnnouncement{
+		PUUID:  key.PUUID,
+		Change: rankChange{Before: previous.LeagueEntry(), After: entry, Announce: true},
+	}, nil
+}
+
+func (s *Service) fetchTrackedLeagueEntries(ctx context.Context, keys map[targetProbeKey]struct{}) map[targetProbeKey][]riot.LeagueEntry {
+	results := make(map[targetProbeKey][]riot.LeagueEntry, len(keys))
+	var mu sync.Mutex
+	var g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(defaultFetchLimit)
+	for key := range keys {
+		g.Go(func() error {
+			entries, err := s.riot.FetchLeagueEntriesByPUUID(gctx, key.PlatformRegion, key.PUUID)
+			if err != nil {
+				s.logger.Warn("Rank check failed", "platformRegion", key.PlatformRegion, "puuid", key.PUUID, "error", err)
+				return nil
+			}
+			mu.Lock()
+			results[key] = entries
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+// announceRankChanges posts one embed per change to every guild that tracks the account.
+func (s *Service) announceRankChanges(ctx context.Context, announcements []rankAnnouncement, targets []postgres.TrackNotificationTarget) {
+	if len(announcements) == 0 {
+		return
+	}
+	routes := s.loadChannelRoutes(ctx)
+	for _, announcement := range announcements {
+		queueID := rankedQueueIDForType(announcement.Change.After.QueueType)
+		rankIcons := loadOrEmptyMap(func() (map[string]string, error) {
+			return s.database.RankIconsByTiers(ctx, riot.RankTiersToLookup([]riot.LeagueEntry{announcement.Change.Before, announcement.Change.After}))
+		})
+		for _, target := range targets {
+			if strings.TrimSpace(target.PUUID) != announcement.PUUID {
+				continue
+			}
+			channelID := routes.channelFor(target.GuildID, strings.TrimSpace(target.ChannelID), postgres.TrackRouteEventRank, queueID, queueCategoryPvP)
+			if channelID == "" {
+				continue
+			}
+			riotID := target.RiotID()
+			if riotID == "" {
+				riotID = announcement.PUUID
+			}
+
+			embed := buildRankAnnouncementEmbed(riotID, announcement.Change, rankIcons)
+			if _, err := s.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+				Embeds: []*discordgo.MessageEmbed{embed},
+			}); err != nil {
+				if s.disableGuildTrackingOnAccessLoss(ctx, target.GuildID, channelID, err) {
+					continue
+				}
+				s.logger.Warn("Failed to send rank announcement", "guildID", target.GuildID, "channelID", channelID, "puuid", announcement.PUUID, "error", err)
+				continue
+			}
+			s.logger.Info("Rank announcement posted", "guildID", target.GuildID, "channelID", channelID, "puuid", announcement.PUUID, "tier", announcement.Change.After.Tier)
+		}
+	}
+}
+
+func buildRankAnnouncementEmbed(riotID string, change rankChange, rankIcons map[string]string) *discordgo.MessageEmbed {
+	before, after := change.Before, change.After
+	promoted := riot.RankStep(after) > riot.RankStep(before)
+	beforeApex, afterApex := riot.HideDivisionForTier(before.Tier), riot.HideDivisionForTier(after.Tier)
+	label := riot.RankLabel(after)
+
+	var headline, message string
+	color, authorIcon := rankPromotionColor, cdn.ProfileIconURL(4069)
+	switch {
+	case riot.RankStep(before) < 0:
+		headline, message = "Placements Complete", fmt.Sprintf("🆕 Placed in **%s**. The climb starts now!", label)
+	case promoted && afterApex && !beforeApex:
+		headline, message = "Apex Tier Reached", fmt.Sprintf("🏆 Made it to **%s**! Welcome to the top of the ladder.", label)
+	case promoted:
+		headline, message = "Promoted", fmt.Sprintf("🎉 Promoted to **%s**. Congratulations!", label)
+	case beforeApex && !afterApex:
+		headline, message = "Left Apex Tier", fmt.Sprintf("💔 Dropped out of **%s** to **%s**. It's only a matter of time.", riot.RankLabel(before), label)
+		color, authorIcon = rankDemotionColor, cdn.ProfileIconURL(3367)
+	default:
+		headline, message = "Demoted", fmt.Sprintf("📉 Demoted to **%s**. Shake it off and queue up again.", label)
+		color, authorIcon = rankDemotionColor, cdn.ProfileIconURL(3367)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    headline,
+			IconURL: authorIcon,
+		},
+		Color:       color,
+		Title:       strings.TrimSpace(riotID),
+		Description: message,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Queue", Value: rankQueueName(after.QueueType), Inline: true},
+			{Name: "Before", Value: riot.RankedLineWithIcon(&before, rankIcons, "Unranked"), Inline: true},
+			{Name: "Now", Value: riot.RankedLineWithIcon(&after, rankIcons, "Unranked"), Inline: true},
+		},
+	}
+	discord.ApplyDefaultFooter(embed)
+	return embed
+}
+
+func rankQueueName(queueType string) string {
+	switch queueType {
+	case soloQueueType:
+		return "Ranked Solo/Duo"
+	case flexQueueType:
+		return "Ranked Flex"
+	default:
+		return queueType
+	}
+}
+
+func sameRankState(a, b riot.LeagueEntry) bool {
+	return riot.NormalizeRankTier(a.Tier) == riot.NormalizeRankTier(b.Tier) &&
+		strings.EqualFold(strings.TrimSpace(a.Rank), strings.TrimSpace(b.Rank)) &&
+		a.LeaguePoints == b.LeaguePoints &&
+		a.Wins == b.Wins &&
+		a.Losses == b.Losses
+}
@@ -0,0 +1,155 @@
+package tracknotify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestBuildRankAnnouncementEmbed(t *testing.T) {
+	icons := map[string]string{"diamond": "<:Diamond:1>", "master": "<:Master:1>"}
+	tests := []struct {
+		name     string
+		change   rankChange
+		headline string
+		message  string
+		color    int
+	}{
+		{
+			name: "promotion",
+			change: rankChange{
+				Before: riot.LeagueEntry{QueueType: soloQueueType, Tier: "GOLD", Rank: "I", LeaguePoints: 95},
+				After:  riot.LeagueEntry{QueueType: soloQueueType, Tier: "PLATINUM", Rank: "IV", LeaguePoints: 5},
+			},
+			headline: "Promoted",
+			message:  "Platinum IV",
+			color:    rankPromotionColor,
+		},
+		{
+			name: "enter apex",
+			change: rankChange{
+				Before: riot.LeagueEntry{QueueType: soloQueueType, Tier: "DIAMOND", Rank: "I", LeaguePoints: 99},
+				After:  riot.LeagueEntry{QueueType: soloQueueType, Tier: "MASTER", Rank: "I", LeaguePoints: 0},
+			},
+			headline: "Apex Tier Reached",
+			message:  "**Master**",
+			color:    rankPromotionColor,
+		},
+		{
+			name: "leave apex",
+			change: rankChange{
+				Before: riot.LeagueEntry{QueueType: flexQueueType, Tier: "MASTER", Rank: "I", LeaguePoints: 0},
+				After:  riot.LeagueEntry{QueueType: flexQueueType, Tier: "DIAMOND", Rank: "I", LeaguePoints: 75},
+			},
+			headline: "Left Apex Tier",
+			message:  "Dropped out of **Master** to **Diamond I**",
+			color:    rankDemotionColor,
+		},
+		{
+			name: "placement",
+			change: rankChange{
+				Before: riot.LeagueEntry{QueueType: soloQueueType},
+				After:  riot.LeagueEntry{QueueType: soloQueueType, Tier: "SILVER", Rank: "II", LeaguePoints: 0},
+			},
+			headline: "Placements Complete",
+			message:  "Placed in **Silver II**",
+			color:    rankPromotionColor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			embed := buildRankAnnouncementEmbed("Bekko#Ekko", tt.change, icons)
+			if embed.Author == nil || embed.Author.Name != tt.headline {
+				t.Fatalf("embed.Author = %#v, want %q", embed.Author, tt.headline)
+			}
+			if !strings.Contains(embed.Description, tt.message) {
+				t.Fatalf("embed.Description = %q, want it to contain %q", embed.Description, tt.message)
+			}
+			if embed.Color != tt.color {
+				t.Fatalf("embed.Color = %#x, want %#x", embed.Color, tt.color)
+			}
+			if embed.Title != "Bekko#Ekko" {
+				t.Fatalf("embed.Title = %q", embed.Title)
+			}
+		})
+	}
+
+	embed := buildRankAnnouncementEmbed("Bekko#Ekko", tests[1].change, icons)
+	if embed.Fields[2].Value != "<:Master:1> Master 0LP" {
+		t.Fatalf("embed.Fields[2].Value = %q", embed.Fields[2].Value)
+	}
+}
+
+func TestCheckRankChangesStoresOnlyChangedStates(t *testing.T) {
+	riotAPI := &fakeRiotAPI{leagueEntries: []riot.LeagueEntry{
+		{QueueType: soloQueueType, Tier: "GOLD", Rank: "I", LeaguePoints: 10, Wins: 21, Losses: 20},
+	}}
+	db := &postPublishTestDB{
+		rankSnapshots: []postgres.TrackRankSnapshot{
+			{PUUID: "p1", QueueType: soloQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 90, Wins: 20, Losses: 20},
+			{PUUID: "p1", QueueType: flexQueueType},
+		},
+	}
+	service := newPostTestService(db, io.Discard)
+	service.riot = riotAPI
+
+	err := service.checkRankChanges(context.Background(), []postgres.TrackNotificationTarget{
+		{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1"},
+	})
+	if err != nil {
+		t.Fatalf("checkRankChanges() error = %v", err)
+	}
+
+	if len(db.rankSnapshots) != 3 {
+		t.Fatalf("rank snapshots = %d, want 3", len(db.rankSnapshots))
+	}
+	stored := db.rankSnapshots[2]
+	if stored.Phase != postgres.TrackRankPhasePoll || stored.QueueType != soloQueueType || stored.Rank != "I" {
+		t.Fatalf("stored snapshot = %+v", stored)
+	}
+}
+
+func TestStartRankCheckMarksOnlyCompletedChecks(t *testing.T) {
+	targets := []postgres.TrackNotificationTarget{{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1"}}
+	db := &postPublishTestDB{rankSnapshotErr: errors.New("database unavailable")}
+	service := newPostTestService(db, io.Discard)
+	service.riot = &fakeRiotAPI{leagueEntries: []riot.LeagueEntry{
+		{QueueType: soloQueueType, Tier: "GOLD", Rank: "I", LeaguePoints: 10, Wins: 21, Losses: 20},
+	}}
+	service.rankCheckInterval = time.Hour
+	now := time.Now().UTC()
+
+	service.startRankCheck(t.Context(), targets, now)
+	service.jobs.Wait()
+	if !service.lastRankCheck.IsZero() || service.rankCheckRunning {
+		t.Fatalf("lastRankCheck = %v, running = %v; want the failed check due again", service.lastRankCheck, service.rankCheckRunning)
+	}
+
+	db.rankSnapshotErr = nil
+	service.startRankCheck(t.Context(), targets, now)
+	service.jobs.Wait()
+	if !service.lastRankCheck.Equal(now) || len(db.rankSnapshots) != 2 {
+		t.Fatalf("lastRankCheck = %v, snapshots = %d; want the check marked done", service.lastRankCheck, len(db.rankSnapshots))
+	}
+}
+
+func TestRankCheckDue(t *testing.T) {
+	now := time.Now().UTC()
+	service := &Service{rankCheckInterval: time.Hour}
+	if !service.rankCheckDue(now) {
+		t.Fatalf("rankCheckDue() = false on first run, want true")
+	}
+	service.lastRankCheck = now.Add(-30 * time.Minute)
+	if service.rankCheckDue(now) {
+		t.Fatalf("rankCheckDue() = true before interval, want false")
+	}
+	if (&Service{}).rankCheckDue(now) {
+		t.Fatalf("rankCheckDue() = true with disabled interval, want false")
+	}
+}
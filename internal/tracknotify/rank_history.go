@@ -0,0 +1,158 @@
+package tracknotify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+const flexQueueType = "RANKED_FLEX_SR"
+
+// rankedQueueTypes maps match queue IDs to the league-v4 queue they award LP in.
+var rankedQueueTypes = map[int]string{
+	420: soloQueueType,
+	440: flexQueueType,
+}
+
+type rankChange struct {
+	Before riot.LeagueEntry
+	After  riot.LeagueEntry
+	// Announce is set when this call observed a tier or division change that no earlier snapshot had recorded.
+	Announce bool
+}
+
+// recordRankSnapshotsAtStart stores the pre-game ranked state of every tracked player in a ranked live game.
+func (s *Service) recordRankSnapshotsAtStart(ctx context.Context, match *liveGuildMatch) {
+	queueType, ok := rankedQueueTypes[match.Game.GameQueueConfigID]
+	if !ok {
+		return
+	}
+	matchID := riot.BuildMatchID(match.PlatformID, match.Game.GameID)
+	for puuid := range match.TrackedByPUUID {
+		_, found, err := s.database.TrackRankSnapshotForMatch(ctx, puuid, queueType, matchID, postgres.TrackRankPhaseStart)
+		if err != nil {
+			s.logger.Warn("Failed to load start rank snapshot", "matchID", matchID, "puuid", puuid, "error", err)
+			continue
+		}
+		if found {
+			continue
+		}
+
+		entry, err := s.fetchQueueEntry(ctx, match.PlatformRegion, puuid, queueType)
+		if err != nil {
+			s.logger.Warn("Failed to fetch rank before game start", "matchID", matchID, "puuid", puuid, "error", err)
+			continue
+		}
+		snapshot := postgres.NewTrackRankSnapshot(match.PlatformRegion, puuid, entry, matchID, postgres.TrackRankPhaseStart, time.Now().UTC())
+		if err := s.database.InsertTrackRankSnapshot(ctx, snapshot); err != nil {
+			s.logger.Warn("Failed to store start rank snapshot", "matchID", matchID, "puuid", puuid, "error", err)
+		}
+	}
+}
+
+// resolveRankChange records the post-game ranked state of a player and compares it with the
+// snapshot taken when the game started, falling back to the latest snapshot from an earlier game.
+// It returns nil when the queue is unranked or there is no baseline, and reports pending when Riot
+// has not applied the LP yet, so the caller can ask again later.
+func (s *Service) resolveRankChange(ctx context.Context, notification postgres.TrackMatchNotification, match riot.MatchDetail) (change *rankChange, pending bool) {
+	queueType, ok := rankedQueueTypes[match.Info.QueueID]
+	if !ok {
+		return nil, false
+	}
+	platformRegion := riot.NormalizePlatformRegion(notification.PlatformID)
+	puuid, matchID := notification.PlayerPUUID, notification.MatchID
+	if platformRegion == "" || puuid == "" || matchID == "" {
+		return nil, false
+	}
+
+	before, hasBefore, err := s.database.TrackRankSnapshotForMatch(ctx, puuid, queueType, matchID, postgres.TrackRankPhaseStart)
+	if err == nil && !hasBefore {
+		before, hasBefore, err = s.database.LatestTrackRankSnapshot(ctx, puuid, queueType, matchID)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to load baseline rank snapshot", "matchID", matchID, "puuid", puuid, "error", err)
+		return nil, false
+	}
+
+	after, hasAfter, err := s.database.TrackRankSnapshotForMatch(ctx, puuid, queueType, matchID, postgres.TrackRankPhaseEnd)
+	if err != nil {
+		s.logger.Warn("Failed to load end rank snapshot", "matchID", matchID, "puuid", puuid, "error", err)
+		return nil, false
+	}
+	announce := false
+	if !hasAfter {
+		entry, err := s.fetchQueueEntry(ctx, platformRegion, puuid, queueType)
+		if err != nil {
+			s.logger.Warn("Failed to fetch rank after game end", "matchID", matchID, "puuid", puuid, "error", err)
+			return nil, true
+		}
+		if hasBefore && entry.Wins+entry.Losses == before.Wins+before.Losses {
+			s.logger.Debug("Ranked entry not updated yet after game end", "matchID", matchID, "puuid", puuid)
+			return nil, true
+		}
+		// A periodic rank check may already have seen (and announced) this change while the post was pending.
+		s.rankSnapshotMu.Lock()
+		previous, hasPrevious, err := s.database.LatestTrackRankSnapshot(ctx, puuid, queueType, "")
+		if err != nil {
+			s.logger.Warn("Failed to load previous rank snapshot", "matchID", matchID, "puuid", puuid, "error", err)
+		}
+		announce = hasPrevious && rankStepChanged(previous.LeagueEntry(), entry)
+
+		after = postgres.NewTrackRankSnapshot(platformRegion, puuid, entry, matchID, postgres.TrackRankPhaseEnd, time.Now().UTC())
+		if err := s.database.InsertTrackRankSnapshot(ctx, after); err != nil {
+			s.logger.Warn("Failed to store end rank snapshot", "matchID", matchID, "puuid", puuid, "error", err)
+		}
+		s.rankSnapshotMu.Unlock()
+	}
+	if !hasBefore {
+		return nil, false
+	}
+	return &rankChange{Before: before.LeagueEntry(), After: after.LeagueEntry(), Announce: announce}, false
+}
+
+// waitForRankUpdate reports whether a post with a pending rank change should wait for Riot to apply the
+// LP: for up to rankUpdateWait after the game ended. Past that, the post goes out without the change.
+func waitForRankUpdate(notification postgres.TrackMatchNotification, match riot.MatchDetail, now time.Time) bool {
+	ended := notification.LastLiveSeenAt
+	if end := match.Info.GameEndTimestamp; end > 0 {
+		ended = time.UnixMilli(end)
+	}
+	return now.Sub(ended) < rankUpdateWait
+}
+
+func (s *Service) fetchQueueEntry(ctx context.Context, platformRegion, puuid, queueType string) (riot.LeagueEntry, error) {
+	entries, err := s.riot.FetchLeagueEntriesByPUUID(ctx, platformRegion, puuid)
+	if err != nil {
+		return riot.LeagueEntry{}, err
+	}
+	if entry := riot.QueueEntry(entries, queueType); entry != nil {
+		return *entry, nil
+	}
+	return riot.LeagueEntry{QueueType: queueType}, nil
+}
+
+// rankStepChanged reports a tier or division change into a ranked state; dropping to unranked is not announced.
+func rankStepChanged(before, after riot.LeagueEntry) bool {
+	afterStep := riot.RankStep(after)
+	return afterStep >= 0 && riot.RankStep(before) != afterStep
+}
+
+func formatRankChange(change rankChange) string {
+	beforeStep, afterStep := riot.RankStep(change.Before), riot.RankStep(change.After)
+	current := fmt.Sprintf("%s %d LP", riot.RankLabel(change.After), change.After.LeaguePoints)
+	switch {
+	case afterStep < 0:
+		return ""
+	case beforeStep < 0:
+		return fmt.Sprintf("🆕 Placed in %s", current)
+	case afterStep > beforeStep:
+		return fmt.Sprintf("⬆️ Promoted to %s", current)
+	case afterStep < beforeStep:
+		return fmt.Sprintf("⬇️ Demoted to %s", current)
+	default:
+		return fmt.Sprintf("%+d LP (%s)", change.After.LeaguePoints-change.Before.LeaguePoints, current)
+	}
+}
@@ -0,0 +1,148 @@
+package tracknotify
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestFormatRankChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change rankChange
+		want   string
+	}{
+		{
+			name: "gain",
+			change: rankChange{
+				Before: riot.LeagueEntry{Tier: "GOLD", Rank: "II", LeaguePoints: 36},
+				After:  riot.LeagueEntry{Tier: "GOLD", Rank: "II", LeaguePoints: 54},
+			},
+			want: "+18 LP (Gold II 54 LP)",
+		},
+		{
+			name: "loss",
+			change: rankChange{
+				Before: riot.LeagueEntry{Tier: "MASTER", Rank: "I", LeaguePoints: 120},
+				After:  riot.LeagueEntry{Tier: "MASTER", Rank: "I", LeaguePoints: 101},
+			},
+			want: "-19 LP (Master 101 LP)",
+		},
+		{
+			name: "promotion",
+			change: rankChange{
+				Before: riot.LeagueEntry{Tier: "GOLD", Rank: "I", LeaguePoints: 90},
+				After:  riot.LeagueEntry{Tier: "PLATINUM", Rank: "IV", LeaguePoints: 10},
+			},
+			want: "⬆️ Promoted to Platinum IV 10 LP",
+		},
+		{
+			name: "demotion",
+			change: rankChange{
+				Before: riot.LeagueEntry{Tier: "SILVER", Rank: "III", LeaguePoints: 0},
+				After:  riot.LeagueEntry{Tier: "SILVER", Rank: "IV", LeaguePoints: 75},
+			},
+			want: "⬇️ Demoted to Silver IV 75 LP",
+		},
+		{
+			name: "placement",
+			change: rankChange{
+				After: riot.LeagueEntry{Tier: "EMERALD", Rank: "IV", LeaguePoints: 0},
+			},
+			want: "🆕 Placed in Emerald IV 0 LP",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRankChange(tt.change); got != tt.want {
+				t.Fatalf("formatRankChange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRankChangeUsesStartSnapshot(t *testing.T) {
+	riotAPI := &fakeRiotAPI{leagueEntries: []riot.LeagueEntry{
+		{QueueType: soloQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 54, Wins: 11, Losses: 9},
+	}}
+	db := &postPublishTestDB{
+		rankSnapshots: []postgres.TrackRankSnapshot{
+			{PUUID: "p1", QueueType: soloQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 80, Wins: 10, Losses: 9, MatchID: "BR1_1", Phase: postgres.TrackRankPhaseEnd},
+			{PUUID: "p1", QueueType: soloQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 36, Wins: 10, Losses: 9, MatchID: "BR1_2", Phase: postgres.TrackRankPhaseStart},
+		},
+	}
+	service := newPostTestService(db, io.Discard)
+	service.riot = riotAPI
+
+	change, pending := service.resolveRankChange(context.Background(), postgres.TrackMatchNotification{
+		PlatformID:  "BR1",
+		MatchID:     "BR1_2",
+		PlayerPUUID: "p1",
+	}, riot.MatchDetail{Info: riot.MatchInfo{QueueID: 420}})
+	if change == nil || pending {
+		t.Fatalf("resolveRankChange() = nil, want change")
+	}
+	if got := formatRankChange(*change); got != "+18 LP (Gold II 54 LP)" {
+		t.Fatalf("formatRankChange() = %q", got)
+	}
+	if len(db.rankSnapshots) != 3 || db.rankSnapshots[2].Phase != postgres.TrackRankPhaseEnd || db.rankSnapshots[2].PlatformRegion != "br1" {
+		t.Fatalf("rank snapshots = %#v, want stored end snapshot", db.rankSnapshots)
+	}
+}
+
+func TestResolveRankChangeSkipsWhenLeagueNotUpdated(t *testing.T) {
+	riotAPI := &fakeRiotAPI{leagueEntries: []riot.LeagueEntry{
+		{QueueType: flexQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 36, Wins: 10, Losses: 9},
+	}}
+	db := &postPublishTestDB{
+		rankSnapshots: []postgres.TrackRankSnapshot{
+			{PUUID: "p1", QueueType: flexQueueType, Tier: "GOLD", Rank: "II", LeaguePoints: 36, Wins: 10, Losses: 9, MatchID: "BR1_2", Phase: postgres.TrackRankPhaseStart},
+		},
+	}
+	service := newPostTestService(db, io.Discard)
+	service.riot = riotAPI
+
+	change, pending := service.resolveRankChange(context.Background(), postgres.TrackMatchNotification{
+		PlatformID:  "BR1",
+		MatchID:     "BR1_2",
+		PlayerPUUID: "p1",
+	}, riot.MatchDetail{Info: riot.MatchInfo{QueueID: 440}})
+	if change != nil || !pending {
+		t.Fatalf("resolveRankChange() = %#v, %v; want nil, pending", change, pending)
+	}
+	if len(db.rankSnapshots) != 1 {
+		t.Fatalf("rank snapshots = %d, want 1", len(db.rankSnapshots))
+	}
+}
+
+func TestResolveRankChangeIgnoresUnrankedQueues(t *testing.T) {
+	service := newPostTestService(&postPublishTestDB{}, io.Discard)
+
+	change, pending := service.resolveRankChange(context.Background(), postgres.TrackMatchNotification{
+		PlatformID:  "BR1",
+		MatchID:     "BR1_2",
+		PlayerPUUID: "p1",
+	}, riot.MatchDetail{Info: riot.MatchInfo{QueueID: 450}})
+	if change != nil || pending {
+		t.Fatalf("resolveRankChange() = %#v, %v; want nil", change, pending)
+	}
+}
+
+func TestWaitForRankUpdate(t *testing.T) {
+	now := time.Now().UTC()
+	ended := riot.MatchDetail{Info: riot.MatchInfo{GameEndTimestamp: now.Add(-time.Minute).UnixMilli()}}
+	if !waitForRankUpdate(postgres.TrackMatchNotification{}, ended, now) {
+		t.Fatalf("waitForRankUpdate() = false, want a wait right after the game")
+	}
+	if waitForRankUpdate(postgres.TrackMatchNotification{}, ended, now.Add(rankUpdateWait)) {
+		t.Fatalf("waitForRankUpdate() = true, want no wait past rankUpdateWait")
+	}
+	seen := postgres.TrackMatchNotification{LastLiveSeenAt: now.Add(-2 * rankUpdateWait)}
+	if waitForRankUpdate(seen, riot.MatchDetail{}, now) {
+		t.Fatalf("waitForRankUpdate() = true, want the last live sighting used without an end time")
+	}
+}
@@ -0,0 +1,156 @@
+package tracknotify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/riot/cdn"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultRenameCheckInterval = 6 * time.Hour
+	renameCheckTimeout         = 10 * time.Minute
+	renameAnnouncementColor    = 0x3498DB
+)
+
+func (s *Service) renameCheckDue(now time.Time) bool {
+	if s.renameCheckInterval <= 0 {
+		return false
+	}
+	return s.lastRenameCheck.IsZero() || now.Sub(s.lastRenameCheck) >= s.renameCheckInterval
+}
+
+// startRenameCheck runs a due rename sweep in the background with its own deadline, since looking up
+// every tracked account can outlast a loop tick. The sweep is only marked done once it succeeds, so a
+// failed one is tried again on a later tick.
+func (s *Service) startRenameCheck(parent context.Context, targets []postgres.TrackNotificationTarget, now time.Time) {
+	s.renameMu.Lock()
+	defer s.renameMu.Unlock()
+	if s.renameRunning || !s.renameCheckDue(now) {
+		return
+	}
+	s.renameRunning = true
+	s.jobs.Go(func() {
+		ctx, cancel := context.WithTimeout(riot.WithPriority(parent, riot.PriorityBackground), renameCheckTimeout)
+		defer cancel()
+		err := s.checkRenames(ctx, targets)
+
+		s.renameMu.Lock()
+		defer s.renameMu.Unlock()
+		s.renameRunning = false
+		if err != nil {
+			s.logger.Warn("Track notify rename check failed", "error", err)
+			return
+		}
+		s.lastRenameCheck = now
+	})
+}
+
+// checkRenames refreshes the stored Riot ID of every tracked account, so renamed players do not keep
+// their old name in the leaderboard and autocomplete. Guilds that opted in get an announcement.
+// It fails when the sweep ran out of time or a rename could not be stored.
+func (s *Service) checkRenames(ctx context.Context, targets []postgres.TrackNotificationTarget) error {
+	accounts := s.fetchTrackedAccounts(ctx, targetProbeKeys(targets))
+	routes := s.loadChannelRoutes(ctx)
+	renamed, failed := 0, 0
+	for key, account := range accounts {
+		current := riot.FormatRiotID(account.GameName, account.TagLine)
+		if strings.TrimSpace(account.GameName) == "" || strings.TrimSpace(account.TagLine) == "" || !targetsNeedRename(targets, key, current) {
+			continue
+		}
+		guildIDs, err := s.database.UpdateTrackedAccountRiotID(ctx, key.PlatformRegion, key.PUUID, account.GameName, account.TagLine)
+		if err != nil {
+			s.logger.Warn("Failed to store renamed tracked account", "platformRegion", key.PlatformRegion, "puuid", key.PUUID, "error", err)
+			failed++
+			continue
+		}
+		renamed++
+		s.announceRename(ctx, key, current, guildIDs, targets, routes)
+	}
+	s.logger.Debug("Track notify rename check summary", "accounts", len(accounts), "renamed", renamed, "failed", failed)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("rename check: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("rename check: %d renamed accounts not stored", failed)
+	}
+	return nil
+}
+
+func (s *Service) fetchTrackedAccounts(ctx context.Context, keys map[targetProbeKey]struct{}) map[targetProbeKey]riot.RiotAccount {
+	results := make(map[targetProbeKey]riot.RiotAccount, len(keys))
+	var mu sync.Mutex
+	var g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(defaultFetchLimit)
+	for key := range keys {
+		g.Go(func() error {
+			account, err := s.riot.FetchAccountByPUUID(gctx, key.PlatformRegion, key.PUUID)
+			if err != nil {
+				s.logger.Warn("Rename check failed", "platformRegion", key.PlatformRegion, "puuid", key.PUUID, "error", err)
+				return nil
+			}
+			mu.Lock()
+			results[key] = account
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+func targetsNeedRename(targets []postgres.TrackNotificationTarget, key targetProbeKey, current string) bool {
+	for _, target := range targets {
+		if target.PUUID == key.PUUID && target.PlatformRegion == key.PlatformRegion && target.RiotID() != current {
+			return true
+		}
+	}
+	return false
+}
+
+// announceRename posts to the renamed guilds that enabled rename announcements, in the channel their
+// rename route points to or else the tracking channel.
+func (s *Service) announceRename(ctx context.Context, key targetProbeKey, current string, guildIDs []string, targets []postgres.TrackNotificationTarget, routes *channelRouteSet) {
+	for _, target := range targets {
+		if target.PUUID != key.PUUID || target.PlatformRegion != key.PlatformRegion || !slices.Contains(guildIDs, target.GuildID) {
+			continue
+		}
+		channelID := routes.channelFor(target.GuildID, strings.TrimSpace(target.ChannelID), postgres.TrackRouteEventRename, 0, "")
+		if !target.AnnounceRenames || channelID == "" {
+			continue
+		}
+		if _, err := s.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{buildRenameAnnouncementEmbed(target.RiotID(), current)},
+		}); err != nil {
+			if s.disableGuildTrackingOnAccessLoss(ctx, target.GuildID, channelID, err) {
+				continue
+			}
+			s.logger.Warn("Failed to send rename announcement", "guildID", target.GuildID, "channelID", channelID, "puuid", key.PUUID, "error", err)
+			continue
+		}
+		s.logger.Info("Rename announcement posted", "guildID", target.GuildID, "channelID", channelID, "puuid", key.PUUID, "riotID", current)
+	}
+}
+
+func buildRenameAnnouncementEmbed(previous, current string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    "Name Change",
+			IconURL: cdn.ProfileIconURL(5704),
+		},
+		Color:       renameAnnouncementColor,
+		Title:       current,
+		Description: fmt.Sprintf("🏷️ **%s** is now known as **%s**.", previous, current),
+	}
+	discord.ApplyDefaultFooter(embed)
+	return embed
+}
@@ -0,0 +1,79 @@
+package tracknotify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+)
+
+func TestCheckRenamesUpdatesOnlyRenamedAccounts(t *testing.T) {
+	riotAPI := &fakeRiotAPI{accounts: map[string]riot.RiotAccount{
+		"p1": {PUUID: "p1", GameName: "Bekko", TagLine: "Ekko"},
+		"p2": {PUUID: "p2", GameName: "Ahri", TagLine: "BR1"},
+	}}
+	db := &postPublishTestDB{renamedGuilds: []string{"g1"}}
+	service := newPostTestService(db, io.Discard)
+	service.riot = riotAPI
+
+	err := service.checkRenames(context.Background(), []postgres.TrackNotificationTarget{
+		{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1", NickName: "Ekko", TagLine: "Ekko"},
+		{GuildID: "g2", PlatformRegion: "br1", PUUID: "p1", NickName: "Bekko", TagLine: "Ekko"},
+		{GuildID: "g1", PlatformRegion: "br1", PUUID: "p2", NickName: "Ahri", TagLine: "BR1"},
+	})
+
+	if err != nil {
+		t.Fatalf("checkRenames() error = %v", err)
+	}
+	if len(db.renames) != 1 || db.renames[0] != "br1/p1=Bekko#Ekko" {
+		t.Fatalf("renames = %v, want [br1/p1=Bekko#Ekko]", db.renames)
+	}
+}
+
+func TestStartRenameCheckMarksOnlySuccessfulSweeps(t *testing.T) {
+	targets := []postgres.TrackNotificationTarget{{GuildID: "g1", PlatformRegion: "br1", PUUID: "p1", NickName: "Ekko", TagLine: "Ekko"}}
+	db := &postPublishTestDB{renameErr: errors.New("database unavailable")}
+	service := newPostTestService(db, io.Discard)
+	service.riot = &fakeRiotAPI{accounts: map[string]riot.RiotAccount{"p1": {PUUID: "p1", GameName: "Bekko", TagLine: "Ekko"}}}
+	service.renameCheckInterval = time.Hour
+	now := time.Now().UTC()
+
+	service.startRenameCheck(t.Context(), targets, now)
+	service.jobs.Wait()
+	if !service.lastRenameCheck.IsZero() || service.renameRunning {
+		t.Fatalf("lastRenameCheck = %v, running = %v; want the failed sweep due again", service.lastRenameCheck, service.renameRunning)
+	}
+
+	db.renameErr = nil
+	service.startRenameCheck(t.Context(), targets, now)
+	service.jobs.Wait()
+	if !service.lastRenameCheck.Equal(now) || len(db.renames) != 1 {
+		t.Fatalf("lastRenameCheck = %v, renames = %v; want the sweep marked done", service.lastRenameCheck, db.renames)
+	}
+}
+
+func TestBuildRenameAnnouncementEmbed(t *testing.T) {
+	embed := buildRenameAnnouncementEmbed("Ekko#Ekko", "Bekko#Ekko")
+	if embed.Title != "Bekko#Ekko" || embed.Description != "🏷️ **Ekko#Ekko** is now known as **Bekko#Ekko**." {
+		t.Fatalf("embed = %+v", embed)
+	}
+}
+
+func TestRenameCheckDue(t *testing.T) {
+	now := time.Now().UTC()
+	service := &Service{renameCheckInterval: time.Hour}
+	if !service.renameCheckDue(now) {
+		t.Fatalf("renameCheckDue() = false on first run, want true")
+	}
+	service.lastRenameCheck = now.Add(-30 * time.Minute)
+	if service.renameCheckDue(now) {
+		t.Fatalf("renameCheckDue() = true before interval, want false")
+	}
+	if (&Service{}).renameCheckDue(now) {
+		t.Fatalf("renameCheckDue() = true with disabled interval, want false")
+	}
+}
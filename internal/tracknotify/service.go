@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bingbr/League-API-bot/internal/riot"
@@ -21,6 +22,8 @@ const (
 	postRetryBaseDelay      = 15 * time.Second
 	postRetryMaxDelay       = 5 * time.Minute
 	postAbandonAfter        = 2 * time.Hour
+	rankUpdateWait          = 3 * time.Minute
+	rankUpdateRetryDelay    = 30 * time.Second
 	soloQueueType           = "RANKED_SOLO_5x5"
 	queueCategoryPvP        = "kpvp"
 )
@@ -34,15 +37,41 @@ type Database interface {
 	storage.TrackNotifyDB
 	storage.SearchDB
 	storage.FreeWeekDB
+	storage.RankHistoryDB
 }
 
 type Service struct {
 	database     Database
 	session      *discordgo.Session
-	riotAPIKey   string
+	riot         riot.API
 	logger       *slog.Logger
 	pollInterval time.Duration
 	loopTimeout  time.Duration
+
+	rankCheckInterval time.Duration
+	// rankCheckMu guards lastRankCheck and rankCheckRunning, which the background rank check updates.
+	rankCheckMu      sync.Mutex
+	lastRankCheck    time.Time
+	rankCheckRunning bool
+	// rankSnapshotMu serializes comparing a rank against the latest snapshot and storing it, which the
+	// background rank check and the post-game rank lookups both do.
+	rankSnapshotMu sync.Mutex
+
+	renameCheckInterval time.Duration
+	// renameMu guards lastRenameCheck and renameRunning, which the background rename sweep updates.
+	renameMu        sync.Mutex
+	lastRenameCheck time.Time
+	renameRunning   bool
+
+	catchUpInterval time.Duration
+	// catchUpMu guards lastCatchUp and catchUpRunning, which the background catch-up sweep updates.
+	catchUpMu      sync.Mutex
+	lastCatchUp    time.Time
+	catchUpRunning bool
+
+	probes *probeScheduler
+	// jobs tracks the background work started by the loop, so Run returns only after it stops.
+	jobs sync.WaitGroup
 }
 
 type guildMatchKey struct {
@@ -59,6 +88,8 @@ type liveGuildMatch struct {
 	PlatformID     string
 	Game           *riot.LiveGame
 	TrackedByPUUID map[string]string
+	// LinkedUsers maps tracked PUUIDs to the Discord users linked to them with /link.
+	LinkedUsers map[string]string
 }
 
 type targetProbeKey struct {
@@ -83,17 +114,24 @@ type guildPlatformKey struct {
 	PlatformID string
 }
 
-func NewService(db Database, session *discordgo.Session, riotAPIKey string, logger *slog.Logger) *Service {
+func NewService(db Database, session *discordgo.Session, riotClient riot.API, logger *slog.Logger) *Service {
 	return &Service{
 		database:     db,
 		session:      session,
-		riotAPIKey:   strings.TrimSpace(riotAPIKey),
+		riot:         riotClient,
 		logger:       logger,
 		pollInterval: defaultPollInterval,
 		loopTimeout:  defaultLoopTimeout,
+
+		rankCheckInterval:   defaultRankCheckInterval,
+		renameCheckInterval: defaultRenameCheckInterval,
+		catchUpInterval:     defaultCatchUpInterval,
+		probes:              newProbeScheduler(defaultPollInterval),
 	}
 }
 
+// disableGuildTrackingOnAccessLoss reacts to a missing-access error. When the channel is a routed one,
+// only its routes are dropped so other notifications keep flowing; otherwise the whole guild is disabled.
 func (s *Service) disableGuildTrackingOnAccessLoss(ctx context.Context, guildID, channelID string, sendErr error) bool {
 	if !isDiscordMissingAccess(sendErr) {
 		return false
@@ -103,6 +141,15 @@ func (s *Service) disableGuildTrackingOnAccessLoss(ctx context.Context, guildID,
 	if guildID == "" {
 		return false
 	}
+	if channelID != "" {
+		removed, err := s.database.RemoveTrackChannelRoutesByChannel(ctx, guildID, channelID)
+		if err != nil {
+			s.logger.Warn("Failed to remove track channel routes after Discord access loss", "guildID", guildID, "channelID", channelID, "error", err, "cause", sendErr)
+		} else if removed > 0 {
+			s.logger.Warn("Removed track channel routes after Discord access loss", "guildID", guildID, "channelID", channelID, "routes", removed, "cause", sendErr)
+			return true
+		}
+	}
 	if err := s.database.DisableTrackGuildConfig(ctx, guildID); err != nil {
 		s.logger.Warn("Failed to disable tracking after Discord access loss", "guildID", guildID, "channelID", channelID, "error", err, "cause", sendErr)
 		return false
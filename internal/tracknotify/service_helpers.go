@@ -76,6 +76,21 @@ func otherTrackedRiotIDs(values map[string]string, subjectRiotID string) []strin
 	return out
 }
 
+func targetProbeKeys(targets []postgres.TrackNotificationTarget) map[targetProbeKey]struct{} {
+	probeKeys := make(map[targetProbeKey]struct{}, len(targets))
+	for _, target := range targets {
+		key := targetProbeKey{
+			PlatformRegion: riot.NormalizePlatformRegion(target.PlatformRegion),
+			PUUID:          strings.TrimSpace(target.PUUID),
+		}
+		if key.PlatformRegion == "" || key.PUUID == "" {
+			continue
+		}
+		probeKeys[key] = struct{}{}
+	}
+	return probeKeys
+}
+
 func trackedTargetsByGuildPlatform(targets []postgres.TrackNotificationTarget) map[guildPlatformKey]map[string]string {
 	out := make(map[guildPlatformKey]map[string]string)
 	for _, target := range targets {
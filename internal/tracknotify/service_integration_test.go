@@ -36,9 +36,9 @@ func TestTrackNotifyIntegration_PublishPostEmbedsSchedulesRetryOnFetchFailure(t
 	fx := newTrackNotifyFixture(t)
 	notification := fx.createPendingNotification(t, "retry", "NA1")
 
-	withFetchMatchStub(t, func(context.Context, string, string, string) (riot.MatchDetail, error) {
+	fx.svc.riot = &fakeRiotAPI{matchByID: func(context.Context, string, string) (riot.MatchDetail, error) {
 		return riot.MatchDetail{}, errors.New("fetch failed")
-	})
+	}}
 
 	before := time.Now().UTC()
 	fx.svc.publishPostEmbeds(fx.ctx, map[guildMatchKey]*liveGuildMatch{}, nil)
@@ -107,10 +107,10 @@ func TestTrackNotifyIntegration_ResolvePostMatchCachesAndReusesSnapshot(t *testi
 	}
 
 	fetchCalls := 0
-	withFetchMatchStub(t, func(context.Context, string, string, string) (riot.MatchDetail, error) {
+	fx.svc.riot = &fakeRiotAPI{matchByID: func(context.Context, string, string) (riot.MatchDetail, error) {
 		fetchCalls++
 		return expected, nil
-	})
+	}}
 
 	got, err := fx.svc.resolvePostMatch(fx.ctx, notification, "americas")
 	if err != nil {
@@ -128,10 +128,10 @@ func TestTrackNotifyIntegration_ResolvePostMatchCachesAndReusesSnapshot(t *testi
 		t.Fatalf("snapshot mismatch: found=%v queueID=%d", found, snapshot.Info.QueueID)
 	}
 
-	withFetchMatchStub(t, func(context.Context, string, string, string) (riot.MatchDetail, error) {
+	fx.svc.riot = &fakeRiotAPI{matchByID: func(context.Context, string, string) (riot.MatchDetail, error) {
 		t.Fatalf("fetchMatchByID should not be called when snapshot exists")
 		return riot.MatchDetail{}, nil
-	})
+	}}
 
 	got, err = fx.svc.resolvePostMatch(fx.ctx, notification, "americas")
 	if err != nil {
@@ -155,9 +155,9 @@ func newTrackNotifyFixture(t *testing.T) trackNotifyFixture {
 		pool:   pool,
 		prefix: prefix,
 		svc: &Service{
-			database:   db,
-			logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
-			riotAPIKey: "test-api-key",
+			database: db,
+			riot:     &fakeRiotAPI{},
+			logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
 		},
 	}
 }
@@ -222,13 +222,6 @@ func (fx trackNotifyFixture) assertPending(t *testing.T, key postgres.TrackMatch
 	}
 }
 
-func withFetchMatchStub(t *testing.T, fn func(context.Context, string, string, string) (riot.MatchDetail, error)) {
-	t.Helper()
-	originalFetch := fetchMatchByID
-	fetchMatchByID = fn
-	t.Cleanup(func() { fetchMatchByID = originalFetch })
-}
-
 func openTrackNotifyIntegrationDB(t *testing.T, ctx context.Context) (*postgres.Database, *pgxpool.Pool, string) {
 	t.Helper()
 
@@ -247,6 +240,9 @@ func openTrackNotifyIntegrationDB(t *testing.T, ctx context.Context) (*postgres.
 	if err := db.CreateTrackTable(ctx); err != nil {
 		t.Fatalf("CreateTrackTable() error = %v", err)
 	}
+	if err := db.CreateAccountLinkTable(ctx); err != nil {
+		t.Fatalf("CreateAccountLinkTable() error = %v", err)
+	}
 
 	prefix := trackNotifyIntegrationPrefix(t.Name())
 	cleanupTrackNotifyIntegrationData(t, pool, prefix)
@@ -2,10 +2,13 @@ package tracknotify
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/bingbr/League-API-bot/internal/discord"
 	"github.com/bingbr/League-API-bot/internal/riot"
 	"github.com/bingbr/League-API-bot/internal/storage/postgres"
 	"github.com/bwmarrin/discordgo"
@@ -18,20 +21,14 @@ func (s *Service) buildActiveMatches(ctx context.Context, targets []postgres.Tra
 		return active
 	}
 
-	probeKeys := make(map[targetProbeKey]struct{}, len(targets))
-	for _, target := range targets {
-		key := targetProbeKey{
-			PlatformRegion: riot.NormalizePlatformRegion(target.PlatformRegion),
-			PUUID:          strings.TrimSpace(target.PUUID),
-		}
-		if key.PlatformRegion == "" || key.PUUID == "" {
-			continue
-		}
-		probeKeys[key] = struct{}{}
-	}
-
-	results, stats := s.fetchLiveGames(ctx, probeKeys)
-	s.logger.Debug("Track notify live probe summary", "targets", len(targets), "uniqueProbes", len(probeKeys), "checked", stats.Checked, "liveGames", stats.LiveGames, "notInGame", stats.NotInGame, "errors", stats.Errors)
+	now := time.Now().UTC()
+	probeKeys := targetProbeKeys(targets)
+	due, refresh := s.probes.plan(probeKeys, now, s.probeBudgets(probeKeys))
+	s.refreshProbeActivity(ctx, refresh, now)
+	results, stats := s.fetchLiveGames(ctx, due)
+	s.probes.record(due, results, now)
+	results = s.probes.liveGames(results)
+	s.logger.Debug("Track notify live probe summary", "targets", len(targets), "uniqueProbes", len(probeKeys), "due", len(due), "activityChecks", len(refresh), "checked", stats.Checked, "liveGames", stats.LiveGames, "notInGame", stats.NotInGame, "errors", stats.Errors)
 	for _, target := range targets {
 		platformRegion := riot.NormalizePlatformRegion(target.PlatformRegion)
 		puuid := strings.TrimSpace(target.PUUID)
@@ -63,6 +60,7 @@ func (s *Service) buildActiveMatches(ctx context.Context, targets []postgres.Tra
 				PlatformID:     platformID,
 				Game:           game,
 				TrackedByPUUID: make(map[string]string),
+				LinkedUsers:    make(map[string]string),
 			}
 			active[key] = entry
 		}
@@ -72,10 +70,15 @@ func (s *Service) buildActiveMatches(ctx context.Context, targets []postgres.Tra
 			riotID = puuid
 		}
 		entry.TrackedByPUUID[puuid] = riotID
+		if userID := strings.TrimSpace(target.DiscordUserID); userID != "" {
+			entry.LinkedUsers[puuid] = userID
+		}
 	}
 	return active
 }
 
+// fetchLiveGames probes the spectator endpoint. Accounts that are not in game map to nil and failed
+// probes are left out of the results.
 func (s *Service) fetchLiveGames(ctx context.Context, keys map[targetProbeKey]struct{}) (map[targetProbeKey]*riot.LiveGame, liveFetchStats) {
 	results := make(map[targetProbeKey]*riot.LiveGame, len(keys))
 	stats := liveFetchStats{}
@@ -88,12 +91,13 @@ func (s *Service) fetchLiveGames(ctx context.Context, keys map[targetProbeKey]st
 	g.SetLimit(defaultFetchLimit)
 	for key := range keys {
 		g.Go(func() error {
-			game, err := riot.FetchActiveGameBySummoner(gctx, key.PlatformRegion, key.PUUID, s.riotAPIKey)
+			game, err := s.riot.FetchActiveGameBySummoner(gctx, key.PlatformRegion, key.PUUID)
 
 			mu.Lock()
 			stats.Checked++
 			if err != nil {
 				if httpStatusIs(err, 404) {
+					results[key] = nil
 					stats.NotInGame++
 				} else {
 					stats.Errors++
@@ -119,12 +123,8 @@ func (s *Service) publishLiveEmbeds(ctx context.Context, active map[guildMatchKe
 		s.logger.Debug("No live matches to publish in this tick")
 		return
 	}
+	filters, routes := s.loadQueueFilters(ctx), s.loadChannelRoutes(ctx)
 	for _, match := range active {
-		playerPUUID, playerRiotID, trackedCount := selectPlayerTracked(match.TrackedByPUUID)
-		if playerPUUID == "" || playerRiotID == "" || trackedCount <= 0 {
-			s.logger.Debug("Skipping live match without valid tracked player", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID)
-			continue
-		}
 		queueDisplay, found, err := s.database.QueueDisplayByID(ctx, match.Game.GameQueueConfigID)
 		if err != nil {
 			s.logger.Warn("Failed to load queue display for live match", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "queueID", match.Game.GameQueueConfigID, "error", err)
@@ -134,7 +134,18 @@ func (s *Service) publishLiveEmbeds(ctx context.Context, active map[guildMatchKe
 			s.logger.Debug("Skipping live match without queue name", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "queueID", match.Game.GameQueueConfigID)
 			continue
 		}
+		if filterTrackedByQueue(filters, match.GuildID, match.TrackedByPUUID, match.Game.GameQueueConfigID, queueDisplay.GameSelectCategory) == 0 {
+			s.logger.Debug("Skipping live match excluded by queue filters", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "queueID", match.Game.GameQueueConfigID)
+			continue
+		}
+		match.ChannelID = routes.channelFor(match.GuildID, match.ChannelID, postgres.TrackRouteEventGame, match.Game.GameQueueConfigID, queueDisplay.GameSelectCategory)
+		playerPUUID, playerRiotID, trackedCount := selectPlayerTracked(match.TrackedByPUUID)
+		if playerPUUID == "" || playerRiotID == "" || trackedCount <= 0 {
+			s.logger.Debug("Skipping live match without valid tracked player", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID)
+			continue
+		}
 
+		seenAt := time.Now().UTC()
 		notification, err := s.database.UpsertTrackMatchNotificationLive(ctx, postgres.UpsertTrackMatchLiveInput{
 			GuildID:        match.GuildID,
 			PlatformID:     match.PlatformID,
@@ -146,17 +157,21 @@ func (s *Service) publishLiveEmbeds(ctx context.Context, active map[guildMatchKe
 			QueueID:        match.Game.GameQueueConfigID,
 			QueueCategory:  strings.TrimSpace(queueDisplay.GameSelectCategory),
 			LiveChannelID:  match.ChannelID,
-			LastLiveSeenAt: time.Now().UTC(),
+			LastLiveSeenAt: seenAt,
 		})
 		if err != nil {
 			s.logger.Error("Failed to upsert live notification", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "error", err)
 			continue
 		}
+		if err := s.database.AdvanceTrackCatchUpCursors(ctx, match.GuildID, slices.Collect(maps.Keys(match.TrackedByPUUID)), seenAt); err != nil {
+			s.logger.Warn("Failed to advance track catch-up cursors", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "error", err)
+		}
 		if strings.TrimSpace(notification.LiveMessageID) != "" {
 			s.logger.Debug("Live notification already posted", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "messageID", notification.LiveMessageID)
 			continue
 		}
 
+		s.recordRankSnapshotsAtStart(ctx, match)
 		queueName := strings.TrimSpace(queueDisplay.Name)
 		embed, err := s.buildLiveEmbed(ctx, match, playerRiotID, queueName)
 		if err != nil {
@@ -164,9 +179,14 @@ func (s *Service) publishLiveEmbeds(ctx context.Context, active map[guildMatchKe
 			continue
 		}
 
-		msg, err := s.session.ChannelMessageSendComplex(match.ChannelID, &discordgo.MessageSend{
-			Embeds: []*discordgo.MessageEmbed{embed},
-		})
+		// A full lobby of long Riot IDs fits one message; anything beyond it follows in more messages.
+		batches := discord.PackEmbeds(embed)
+		send := &discordgo.MessageSend{Embeds: batches[0]}
+		if users := linkedUserMentions(match); len(users) > 0 {
+			send.Content = mentionContent(users)
+			send.AllowedMentions = &discordgo.MessageAllowedMentions{Users: users}
+		}
+		msg, err := s.session.ChannelMessageSendComplex(match.ChannelID, send)
 		if err != nil {
 			if s.disableGuildTrackingOnAccessLoss(ctx, match.GuildID, match.ChannelID, err) {
 				continue
@@ -174,9 +194,38 @@ func (s *Service) publishLiveEmbeds(ctx context.Context, active map[guildMatchKe
 			s.logger.Warn("Failed to send live embed", "guildID", match.GuildID, "channelID", match.ChannelID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "error", err)
 			continue
 		}
+		for _, batch := range batches[1:] {
+			if _, err := s.session.ChannelMessageSendComplex(match.ChannelID, &discordgo.MessageSend{Embeds: batch}); err != nil {
+				s.logger.Warn("Failed to send live embed overflow", "guildID", match.GuildID, "channelID", match.ChannelID, "gameID", match.Game.GameID, "error", err)
+				break
+			}
+		}
 		s.logger.Info("Live notification posted", "guildID", match.GuildID, "channelID", match.ChannelID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "messageID", msg.ID)
 		if err := s.database.MarkTrackMatchLivePosted(ctx, notification.Key(), match.ChannelID, msg.ID, time.Now().UTC()); err != nil {
 			s.logger.Warn("Failed to persist live notification message", "guildID", match.GuildID, "platformID", match.PlatformID, "gameID", match.Game.GameID, "error", err)
 		}
 	}
 }
+
+// linkedUserMentions returns the Discord users linked to the tracked players left after queue filtering.
+func linkedUserMentions(match *liveGuildMatch) []string {
+	if match == nil || len(match.LinkedUsers) == 0 {
+		return nil
+	}
+	users := make([]string, 0, len(match.LinkedUsers))
+	for puuid, userID := range match.LinkedUsers {
+		if _, tracked := match.TrackedByPUUID[puuid]; tracked && !slices.Contains(users, userID) {
+			users = append(users, userID)
+		}
+	}
+	slices.Sort(users)
+	return users
+}
+
+func mentionContent(userIDs []string) string {
+	mentions := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		mentions = append(mentions, "<@"+userID+">")
+	}
+	return strings.Join(mentions, " ")
+}
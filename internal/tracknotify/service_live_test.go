@@ -0,0 +1,23 @@
+package tracknotify
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinkedUserMentions(t *testing.T) {
+	match := &liveGuildMatch{
+		TrackedByPUUID: map[string]string{"p1": "Ahri#BR1", "p2": "Lux#BR1", "p3": "Zed#BR1"},
+		LinkedUsers:    map[string]string{"p1": "200", "p2": "100", "p3": "200", "filtered": "300"},
+	}
+	users := linkedUserMentions(match)
+	if !slices.Equal(users, []string{"100", "200"}) {
+		t.Fatalf("linkedUserMentions() = %v, want [100 200]", users)
+	}
+	if got := mentionContent(users); got != "<@100> <@200>" {
+		t.Fatalf("mentionContent() = %q", got)
+	}
+	if users := linkedUserMentions(&liveGuildMatch{TrackedByPUUID: match.TrackedByPUUID}); users != nil {
+		t.Fatalf("linkedUserMentions(no links) = %v, want nil", users)
+	}
+}
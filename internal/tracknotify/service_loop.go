@@ -3,6 +3,8 @@ package tracknotify
 import (
 	"context"
 	"time"
+
+	"github.com/bingbr/League-API-bot/internal/riot"
 )
 
 func (s *Service) Run(ctx context.Context) {
@@ -12,6 +14,7 @@ func (s *Service) Run(ctx context.Context) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	defer s.jobs.Wait()
 
 	s.runOnce(ctx)
 	ticker := time.NewTicker(s.pollInterval)
@@ -47,9 +50,13 @@ func (s *Service) runOnce(parent context.Context) {
 	}
 	s.logger.Debug("=== Track notify tick ===", "targets", len(targets))
 
-	activeMatches := s.buildActiveMatches(ctx, targets)
+	liveCtx := riot.WithPriority(ctx, riot.PriorityLiveProbe)
+	activeMatches := s.buildActiveMatches(liveCtx, targets)
 	s.logger.Debug("Track notify active matches", "count", len(activeMatches))
-	s.publishLiveEmbeds(ctx, activeMatches)
-	s.publishPostEmbeds(ctx, activeMatches, targets)
+	s.publishLiveEmbeds(liveCtx, activeMatches)
+	s.publishPostEmbeds(riot.WithPriority(ctx, riot.PriorityPostGame), activeMatches, targets)
+	s.startRankCheck(parent, targets, now)
+	s.startCatchUp(parent, targets, now)
+	s.startRenameCheck(parent, targets, now)
 	s.logger.Debug("=== Track notify tick ended ===")
 }
@@ -3,16 +3,16 @@ package tracknotify
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/bingbr/League-API-bot/internal/discord"
 	"github.com/bingbr/League-API-bot/internal/riot"
 	"github.com/bingbr/League-API-bot/internal/storage/postgres"
 	"github.com/bwmarrin/discordgo"
 )
 
-var fetchMatchByID = riot.FetchMatchByID
-
 func (s *Service) publishPostEmbeds(ctx context.Context, active map[guildMatchKey]*liveGuildMatch, targets []postgres.TrackNotificationTarget) {
 	now := time.Now().UTC()
 	pending, err := s.database.ListPendingTrackMatchNotifications(ctx, now, defaultPostPendingLimit)
@@ -26,6 +26,8 @@ func (s *Service) publishPostEmbeds(ctx context.Context, active map[guildMatchKe
 	}
 
 	targetsByGuildPlatform := trackedTargetsByGuildPlatform(targets)
+	postModes := postModesByGuild(targets)
+	filters := s.loadQueueFilters(ctx)
 	for _, notification := range pending {
 		key := guildMatchKey{GuildID: notification.GuildID, PlatformID: notification.PlatformID, GameID: notification.GameID}
 		if _, live := active[key]; live {
@@ -75,19 +77,53 @@ func (s *Service) publishPostEmbeds(ctx context.Context, active map[guildMatchKe
 				RiotID: notification.PlayerRiotID,
 			}}
 		}
+		trackedPairs = slices.DeleteFunc(trackedPairs, func(pair trackedPair) bool {
+			return !filters.allows(notification.GuildID, pair.PUUID, match.Info.QueueID, queueDisplay.GameSelectCategory)
+		})
+		if len(trackedPairs) == 0 {
+			s.abandonPostNotification(ctx, notification, now, "queue excluded by track filters", "Failed to abandon filtered post notification", "queueID", match.Info.QueueID)
+			continue
+		}
 
-		embeds := make([]*discordgo.MessageEmbed, 0, len(trackedPairs))
+		players := make([]postgres.TrackMatchNotification, 0, len(trackedPairs))
+		changes := make([]*rankChange, 0, len(trackedPairs))
+		announcements := make([]rankAnnouncement, 0)
+		rankPending := false
 		for _, pair := range trackedPairs {
 			perPlayer := notification
 			perPlayer.PlayerPUUID = pair.PUUID
 			perPlayer.PlayerRiotID = pair.RiotID
 			perPlayer.TrackedCount = len(trackedPairs)
 
-			embed, err := s.buildPostEmbed(ctx, perPlayer, match, queueName)
+			// A catch-up game may be one of several missed ones, so its LP delta cannot be told apart.
+			var change *rankChange
+			if !notification.CatchUp {
+				var pending bool
+				change, pending = s.resolveRankChange(ctx, perPlayer, match)
+				rankPending = rankPending || pending
+			}
+			if change != nil && change.Announce {
+				announcements = append(announcements, rankAnnouncement{PUUID: pair.PUUID, Change: *change})
+			}
+			players = append(players, perPlayer)
+			changes = append(changes, change)
+		}
+		if rankPending && waitForRankUpdate(notification, match, now) && s.deferPostForRankUpdate(ctx, notification, now) {
+			// End snapshots stored in this pass are not announced again on the retry.
+			s.announceRankChanges(ctx, announcements, targets)
+			continue
+		}
+
+		embeds := make([]*discordgo.MessageEmbed, 0, len(trackedPairs))
+		for idx, perPlayer := range players {
+			embed, err := s.buildPostEmbed(ctx, perPlayer, match, queueName, changes[idx])
 			if err != nil {
-				s.logger.Warn("Failed to build post embed for tracked player", "guildID", notification.GuildID, "platformID", notification.PlatformID, "gameID", notification.GameID, "playerPUUID", pair.PUUID, "error", err)
+				s.logger.Warn("Failed to build post embed for tracked player", "guildID", notification.GuildID, "platformID", notification.PlatformID, "gameID", notification.GameID, "playerPUUID", perPlayer.PlayerPUUID, "error", err)
 				continue
 			}
+			if notification.CatchUp {
+				markCatchUpEmbed(embed, match)
+			}
 			embeds = append(embeds, embed)
 		}
 		if len(embeds) == 0 {
@@ -95,7 +131,8 @@ func (s *Service) publishPostEmbeds(ctx context.Context, active map[guildMatchKe
 			continue
 		}
 
-		lastMessageID, sendErr := s.sendPostEmbedBatches(notification, embeds)
+		lastMessageID, sendErr := s.publishPostMessage(notification, embeds, postModes[notification.GuildID])
+		s.announceRankChanges(ctx, announcements, targets)
 		if sendErr != nil {
 			if s.disableGuildTrackingOnAccessLoss(ctx, notification.GuildID, notification.LiveChannelID, sendErr) {
 				s.abandonPostNotification(ctx, notification, now, "discord access lost to configured channel", "Failed to abandon post notification after disabling tracking")
@@ -126,7 +163,7 @@ func (s *Service) resolvePostMatch(ctx context.Context, notification postgres.Tr
 		return snapshot, nil
 	}
 
-	match, err := fetchMatchByID(ctx, continent, matchID, s.riotAPIKey)
+	match, err := s.riot.FetchMatchByID(ctx, continent, matchID)
 	if err != nil {
 		return riot.MatchDetail{}, err
 	}
@@ -136,6 +173,18 @@ func (s *Service) resolvePostMatch(ctx context.Context, notification postgres.Tr
 	return match, nil
 }
 
+// deferPostForRankUpdate moves the post to the next loop without counting an attempt, so it can show the
+// LP change once Riot applies it. It reports false when the retry could not be stored.
+func (s *Service) deferPostForRankUpdate(ctx context.Context, notification postgres.TrackMatchNotification, now time.Time) bool {
+	err := s.database.MarkTrackMatchPostRetry(ctx, notification.Key(), notification.PostAttempts, now.Add(rankUpdateRetryDelay), "ranked entry not updated yet")
+	if err != nil {
+		s.logger.Warn("Failed to defer post for rank update", "guildID", notification.GuildID, "platformID", notification.PlatformID, "gameID", notification.GameID, "error", err)
+		return false
+	}
+	s.logger.Debug("Deferred post until the ranked entry updates", "guildID", notification.GuildID, "platformID", notification.PlatformID, "gameID", notification.GameID)
+	return true
+}
+
 func (s *Service) schedulePostRetry(ctx context.Context, notification postgres.TrackMatchNotification, now time.Time, reason string) {
 	attempts := notification.PostAttempts + 1
 	nextAttempt := now.Add(postRetryDelay(attempts))
@@ -160,10 +209,9 @@ func (s *Service) abandonPostNotification(ctx context.Context, notification post
 
 func (s *Service) sendPostEmbedBatches(notification postgres.TrackMatchNotification, embeds []*discordgo.MessageEmbed) (string, error) {
 	lastMessageID := ""
-	for idx := 0; idx < len(embeds); idx += 10 {
-		end := min(idx+10, len(embeds))
-		send := &discordgo.MessageSend{Embeds: embeds[idx:end]}
-		if idx == 0 {
+	for idx, batch := range discord.PackEmbeds(embeds...) {
+		send := &discordgo.MessageSend{Embeds: batch}
+		if idx == 0 && notification.LiveMessageID != "" {
 			send.Reference = &discordgo.MessageReference{
 				MessageID:       notification.LiveMessageID,
 				ChannelID:       notification.LiveChannelID,
@@ -0,0 +1,153 @@
+package tracknotify
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	discordErrUnknownMessage = 10008
+	liveLineupFieldName      = "Live Lineup"
+)
+
+// postModesByGuild resolves the configured post mode of every guild that has tracked accounts.
+func postModesByGuild(targets []postgres.TrackNotificationTarget) map[string]string {
+	out := make(map[string]string)
+	for _, target := range targets {
+		guildID := strings.TrimSpace(target.GuildID)
+		if guildID == "" {
+			continue
+		}
+		out[guildID] = postgres.NormalizeTrackPostMode(target.PostMode)
+	}
+	return out
+}
+
+// publishPostMessage sends the post-game embeds according to the guild post mode.
+// In edit mode the live message is replaced in place; a deleted live message falls back to a reply.
+func (s *Service) publishPostMessage(notification postgres.TrackMatchNotification, embeds []*discordgo.MessageEmbed, postMode string) (string, error) {
+	if postMode == postgres.TrackPostModeEdit {
+		messageID, edited, err := s.editLiveMessageWithPost(notification, embeds)
+		if err != nil {
+			return "", err
+		}
+		if edited {
+			return messageID, nil
+		}
+		s.logger.Debug("Live message unavailable for in-place edit, posting a new message", "guildID", notification.GuildID, "platformID", notification.PlatformID, "gameID", notification.GameID)
+	}
+	return s.sendPostEmbedBatches(notification, embeds)
+}
+
+// editLiveMessageWithPost replaces the live embed with the post-game embeds and keeps the lineup as one compact field.
+// It reports edited=false when the live message is gone, so the caller can post a new one.
+func (s *Service) editLiveMessageWithPost(notification postgres.TrackMatchNotification, embeds []*discordgo.MessageEmbed) (string, bool, error) {
+	channelID, messageID := strings.TrimSpace(notification.LiveChannelID), strings.TrimSpace(notification.LiveMessageID)
+	if channelID == "" || messageID == "" || len(embeds) == 0 {
+		return "", false, nil
+	}
+
+	live, err := s.session.ChannelMessage(channelID, messageID)
+	if err != nil {
+		// Reading the message needs Read Message History; without it, post a new message instead of disabling tracking.
+		if isDiscordUnknownMessage(err) || isDiscordMissingAccess(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	batches := discord.PackEmbeds(embedsWithLiveLineup(embeds, live.Embeds)...)
+	if len(batches) == 0 {
+		return "", false, nil
+	}
+	batch := batches[0]
+	if _, err := s.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:      messageID,
+		Channel: channelID,
+		Embeds:  &batch,
+	}); err != nil {
+		if isDiscordUnknownMessage(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	lastMessageID := messageID
+	if len(batches) > 1 {
+		overflowID, err := s.sendPostEmbedBatches(notification, slices.Concat(batches[1:]...))
+		if err != nil {
+			return "", false, err
+		}
+		lastMessageID = overflowID
+	}
+	return lastMessageID, true, nil
+}
+
+// embedsWithLiveLineup returns a copy of embeds whose first entry carries the collapsed live lineup.
+func embedsWithLiveLineup(embeds []*discordgo.MessageEmbed, liveEmbeds []*discordgo.MessageEmbed) []*discordgo.MessageEmbed {
+	out := make([]*discordgo.MessageEmbed, len(embeds))
+	copy(out, embeds)
+	if len(out) == 0 || len(liveEmbeds) == 0 {
+		return out
+	}
+	lineup := collapseLiveLineup(liveEmbeds[0])
+	if lineup == nil || len(out[0].Fields) >= discord.EmbedFieldCountLimit {
+		return out
+	}
+
+	first := *out[0]
+	first.Fields = append(append([]*discordgo.MessageEmbedField{}, out[0].Fields...), lineup)
+	out[0] = &first
+	return out
+}
+
+// collapseLiveLineup folds the team columns of a live embed into a single field, one line per team.
+func collapseLiveLineup(live *discordgo.MessageEmbed) *discordgo.MessageEmbedField {
+	if live == nil {
+		return nil
+	}
+	lines := make([]string, 0, 2)
+	for _, field := range live.Fields {
+		if field == nil {
+			continue
+		}
+		var marker string
+		switch strings.TrimSpace(field.Name) {
+		case "🔵 Team":
+			marker = "🔵"
+		case "🔴 Team":
+			marker = "🔴"
+		default:
+			continue
+		}
+		names := make([]string, 0, 5)
+		for name := range strings.SplitSeq(field.Value, "\n") {
+			if name = strings.TrimSpace(name); name != "" && name != "-" {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			lines = append(lines, marker+" "+strings.Join(names, " · "))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	value := discord.TruncateText(strings.Join(lines, "\n"), discord.EmbedFieldValueLimit)
+	return &discordgo.MessageEmbedField{Name: liveLineupFieldName, Value: value}
+}
+
+func isDiscordUnknownMessage(err error) bool {
+	restErr, ok := errors.AsType[*discordgo.RESTError](err)
+	if !ok || restErr == nil || restErr.Message == nil {
+		return false
+	}
+	return restErr.Message.Code == discordErrUnknownMessage
+}
@@ -0,0 +1,81 @@
+package tracknotify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bingbr/League-API-bot/internal/discord"
+	"github.com/bingbr/League-API-bot/internal/storage/postgres"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCollapseLiveLineup(t *testing.T) {
+	live := &discordgo.MessageEmbed{
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "🔵 Team", Value: "Ahri Bekko#Ekko\nZed Foo#BR1"},
+			{Name: "Rank", Value: "Gold II\nSilver I"},
+			{Name: "🔴 Team", Value: "Lux Bar#NA1"},
+			{Name: "🔴 Bans", Value: "Yasuo"},
+		},
+	}
+
+	field := collapseLiveLineup(live)
+	if field == nil {
+		t.Fatalf("collapseLiveLineup() = nil")
+	}
+	want := "🔵 Ahri Bekko#Ekko · Zed Foo#BR1\n🔴 Lux Bar#NA1"
+	if field.Name != liveLineupFieldName || field.Value != want || field.Inline {
+		t.Fatalf("collapseLiveLineup() = %+v, want value %q", field, want)
+	}
+
+	if got := collapseLiveLineup(&discordgo.MessageEmbed{Fields: []*discordgo.MessageEmbedField{{Name: "🔵 Team", Value: "-"}}}); got != nil {
+		t.Fatalf("collapseLiveLineup(empty team) = %+v, want nil", got)
+	}
+}
+
+func TestCollapseLiveLineupTruncatesLongValues(t *testing.T) {
+	live := &discordgo.MessageEmbed{Fields: []*discordgo.MessageEmbedField{{Name: "🔵 Team", Value: strings.Repeat("x", 2000)}}}
+	field := collapseLiveLineup(live)
+	if field == nil || len([]rune(field.Value)) != discord.EmbedFieldValueLimit || !strings.HasSuffix(field.Value, "…") {
+		t.Fatalf("collapseLiveLineup() value length = %d", len([]rune(field.Value)))
+	}
+}
+
+func TestEmbedsWithLiveLineupDoesNotMutateInput(t *testing.T) {
+	post := &discordgo.MessageEmbed{Title: "Post", Fields: []*discordgo.MessageEmbedField{{Name: "KDA", Value: "1/2/3"}}}
+	live := []*discordgo.MessageEmbed{{Fields: []*discordgo.MessageEmbedField{{Name: "🔵 Team", Value: "Ahri"}}}}
+
+	out := embedsWithLiveLineup([]*discordgo.MessageEmbed{post}, live)
+	if len(out) != 1 || len(out[0].Fields) != 2 || out[0].Fields[1].Name != liveLineupFieldName {
+		t.Fatalf("embedsWithLiveLineup() fields = %+v", out[0].Fields)
+	}
+	if len(post.Fields) != 1 {
+		t.Fatalf("input embed was mutated: %+v", post.Fields)
+	}
+
+	out = embedsWithLiveLineup([]*discordgo.MessageEmbed{post}, nil)
+	if out[0] != post {
+		t.Fatalf("embedsWithLiveLineup() without live embed should keep the original embed")
+	}
+}
+
+func TestPostModesByGuild(t *testing.T) {
+	modes := postModesByGuild([]postgres.TrackNotificationTarget{
+		{GuildID: "g1", PostMode: "edit"},
+		{GuildID: " g2 ", PostMode: ""},
+		{GuildID: "", PostMode: "edit"},
+	})
+	if len(modes) != 2 || modes["g1"] != postgres.TrackPostModeEdit || modes["g2"] != postgres.TrackPostModeReply {
+		t.Fatalf("postModesByGuild() = %v", modes)
+	}
+}
+
+func TestIsDiscordUnknownMessage(t *testing.T) {
+	err := &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: discordErrUnknownMessage}}
+	if !isDiscordUnknownMessage(err) {
+		t.Fatalf("isDiscordUnknownMessage(10008) = false")
+	}
+	if isDiscordUnknownMessage(&discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: 50013}}) {
+		t.Fatalf("isDiscordUnknownMessage(50013) = true")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -63,10 +64,10 @@ func TestPublishPostEmbeds_AbandonOnMissingContinentHandlesDBErrorAndContinues(t
 
 func TestResolvePostMatchUsesSnapshot(t *testing.T) {
 	called := false
-	withFetchMatchByIDStub(t, func(context.Context, string, string, string) (riot.MatchDetail, error) {
+	riotAPI := &fakeRiotAPI{matchByID: func(context.Context, string, string) (riot.MatchDetail, error) {
 		called = true
 		return riot.MatchDetail{}, errors.New("fetch should not be called")
-	})
+	}}
 
 	match := riot.MatchDetail{
 		Metadata: riot.MatchMetadata{MatchID: "NA1_123"},
@@ -78,6 +79,7 @@ func TestResolvePostMatchUsesSnapshot(t *testing.T) {
 		},
 	}
 	service := newPostTestService(db, io.Discard)
+	service.riot = riotAPI
 
 	got, err := service.resolvePostMatch(context.Background(), postgres.TrackMatchNotification{
 		GuildID:    "g1",
@@ -104,7 +106,7 @@ func TestResolvePostMatchFetchesAndCachesOnSnapshotMiss(t *testing.T) {
 		Metadata: riot.MatchMetadata{MatchID: "EUW1_456"},
 		Info:     riot.MatchInfo{QueueID: 440},
 	}
-	withFetchMatchByIDStub(t, func(_ context.Context, continent, matchID, _ string) (riot.MatchDetail, error) {
+	riotAPI := &fakeRiotAPI{matchByID: func(_ context.Context, continent, matchID string) (riot.MatchDetail, error) {
 		if continent != "europe" {
 			t.Fatalf("continent = %q, want %q", continent, "europe")
 		}
@@ -112,10 +114,11 @@ func TestResolvePostMatchFetchesAndCachesOnSnapshotMiss(t *testing.T) {
 			t.Fatalf("matchID = %q, want %q", matchID, "EUW1_456")
 		}
 		return match, nil
-	})
+	}}
 
 	db := &postPublishTestDB{}
 	service := newPostTestService(db, io.Discard)
+	service.riot = riotAPI
 
 	got, err := service.resolvePostMatch(context.Background(), postgres.TrackMatchNotification{
 		GuildID:    "g1",
@@ -151,17 +154,40 @@ type postPublishTestDB struct {
 	snapshotErr       error
 	upsertSnapshotErr error
 	upsertedSnapshots []riot.MatchDetail
+	rankSnapshots     []postgres.TrackRankSnapshot
+	rankSnapshotErr   error
+	queueFilters      []postgres.TrackQueueFilter
+	routesRemoved     int64
+	disabledGuilds    []string
+	renames           []string
+	renamedGuilds     []string
+	renameErr         error
+	queues            map[int]postgres.QueueDisplay
+	catchUpErr        error
+	catchUpCursors    []postgres.TrackCatchUpCursor
+	knownMatches      map[string][]string
+	catchUps          []postgres.UpsertTrackMatchLiveInput
+	catchUpSeen       map[string]time.Time
 }
 
 func (d *postPublishTestDB) ListTrackNotificationTargets(context.Context) ([]postgres.TrackNotificationTarget, error) {
 	return nil, nil
 }
 
+func (d *postPublishTestDB) UpdateTrackedAccountRiotID(_ context.Context, platformRegion, puuid, gameName, tagLine string) ([]string, error) {
+	if d.renameErr != nil {
+		return nil, d.renameErr
+	}
+	d.renames = append(d.renames, platformRegion+"/"+puuid+"="+riot.FormatRiotID(gameName, tagLine))
+	return d.renamedGuilds, nil
+}
+
 func (d *postPublishTestDB) UpsertTrackMatchNotificationLive(context.Context, postgres.UpsertTrackMatchLiveInput) (postgres.TrackMatchNotification, error) {
 	panic("unexpected call to UpsertTrackMatchNotificationLive")
 }
 
-func (d *postPublishTestDB) DisableTrackGuildConfig(context.Context, string) error {
+func (d *postPublishTestDB) DisableTrackGuildConfig(_ context.Context, guildID string) error {
+	d.disabledGuilds = append(d.disabledGuilds, guildID)
 	return nil
 }
 
@@ -186,6 +212,34 @@ func (d *postPublishTestDB) AbandonTrackMatchNotification(_ context.Context, key
 	return d.abandonErr
 }
 
+func (d *postPublishTestDB) ListTrackCatchUpCursors(context.Context) ([]postgres.TrackCatchUpCursor, error) {
+	if d.catchUpErr != nil {
+		return nil, d.catchUpErr
+	}
+	return d.catchUpCursors, nil
+}
+
+func (d *postPublishTestDB) AdvanceTrackCatchUpCursors(_ context.Context, guildID string, puuids []string, seenAt time.Time) error {
+	if d.catchUpSeen == nil {
+		d.catchUpSeen = make(map[string]time.Time)
+	}
+	for _, puuid := range puuids {
+		if key := guildID + "/" + puuid; seenAt.After(d.catchUpSeen[key]) {
+			d.catchUpSeen[key] = seenAt
+		}
+	}
+	return nil
+}
+
+func (d *postPublishTestDB) ListTrackMatchNotificationGuilds(context.Context, []string) (map[string][]string, error) {
+	return d.knownMatches, nil
+}
+
+func (d *postPublishTestDB) InsertTrackMatchCatchUp(_ context.Context, input postgres.UpsertTrackMatchLiveInput) (bool, error) {
+	d.catchUps = append(d.catchUps, input)
+	return true, nil
+}
+
 func (d *postPublishTestDB) GetTrackMatchSnapshot(_ context.Context, matchID string) (riot.MatchDetail, bool, error) {
 	if d.snapshotErr != nil {
 		return riot.MatchDetail{}, false, d.snapshotErr
@@ -203,8 +257,12 @@ func (d *postPublishTestDB) CleanupTrackMatchNotifications(context.Context, time
 	return 0, nil
 }
 
-func (d *postPublishTestDB) QueueDisplayByID(context.Context, int) (postgres.QueueDisplay, bool, error) {
-	panic("unexpected call to QueueDisplayByID")
+func (d *postPublishTestDB) QueueDisplayByID(_ context.Context, queueID int) (postgres.QueueDisplay, bool, error) {
+	if d.queues == nil {
+		panic("unexpected call to QueueDisplayByID")
+	}
+	queue, ok := d.queues[queueID]
+	return queue, ok, nil
 }
 
 func (d *postPublishTestDB) MapDisplayByID(context.Context, int) (postgres.MapDisplay, bool, error) {
@@ -243,16 +301,119 @@ func (d *postPublishTestDB) ChampionDisplayByIDs(context.Context, []int) (map[in
 	return map[int]postgres.ChampionDisplay{}, nil
 }
 
-func withFetchMatchByIDStub(t *testing.T, fn func(context.Context, string, string, string) (riot.MatchDetail, error)) {
-	t.Helper()
-	original := fetchMatchByID
-	fetchMatchByID = fn
-	t.Cleanup(func() { fetchMatchByID = original })
+func (d *postPublishTestDB) ListAllTrackQueueFilters(context.Context) ([]postgres.TrackQueueFilter, error) {
+	return d.queueFilters, nil
+}
+
+func (d *postPublishTestDB) ListAllTrackChannelRoutes(context.Context) ([]postgres.TrackChannelRoute, error) {
+	return nil, nil
+}
+
+func (d *postPublishTestDB) RemoveTrackChannelRoutesByChannel(context.Context, string, string) (int64, error) {
+	return d.routesRemoved, nil
+}
+
+func (d *postPublishTestDB) InsertTrackRankSnapshot(_ context.Context, snapshot postgres.TrackRankSnapshot) error {
+	if d.rankSnapshotErr != nil {
+		return d.rankSnapshotErr
+	}
+	d.rankSnapshots = append(d.rankSnapshots, snapshot)
+	return nil
+}
+
+func (d *postPublishTestDB) TrackRankSnapshotForMatch(_ context.Context, puuid, queueType, matchID, phase string) (postgres.TrackRankSnapshot, bool, error) {
+	for _, snapshot := range d.rankSnapshots {
+		if snapshot.PUUID == puuid && snapshot.QueueType == queueType && snapshot.MatchID == matchID && snapshot.Phase == phase {
+			return snapshot, true, nil
+		}
+	}
+	return postgres.TrackRankSnapshot{}, false, nil
+}
+
+func (d *postPublishTestDB) LatestTrackRankSnapshot(_ context.Context, puuid, queueType, excludeMatchID string) (postgres.TrackRankSnapshot, bool, error) {
+	for idx := len(d.rankSnapshots) - 1; idx >= 0; idx-- {
+		snapshot := d.rankSnapshots[idx]
+		if snapshot.PUUID == puuid && snapshot.QueueType == queueType && (excludeMatchID == "" || snapshot.MatchID != excludeMatchID) {
+			return snapshot, true, nil
+		}
+	}
+	return postgres.TrackRankSnapshot{}, false, nil
 }
 
 func newPostTestService(db *postPublishTestDB, sink io.Writer) *Service {
 	return &Service{
 		database: db,
+		riot:     &fakeRiotAPI{},
 		logger:   slog.New(slog.NewTextHandler(sink, nil)),
 	}
 }
+
+var errRiotNotStubbed = errors.New("riot call not stubbed")
+
+// fakeRiotAPI answers the Riot calls of the service from its fields; calls without a stub fail.
+type fakeRiotAPI struct {
+	matchByID     func(ctx context.Context, continent, matchID string) (riot.MatchDetail, error)
+	matchIDs      []string
+	leagueEntries []riot.LeagueEntry
+	accounts      map[string]riot.RiotAccount
+	summoner      *riot.SummonerProfile
+	requestRate   float64
+	requestRates  map[string]float64
+}
+
+func (f *fakeRiotAPI) FetchAccountByRiotID(context.Context, string, string, string) (riot.RiotAccount, error) {
+	return riot.RiotAccount{}, errRiotNotStubbed
+}
+
+func (f *fakeRiotAPI) FetchAccountByPUUID(_ context.Context, _, puuid string) (riot.RiotAccount, error) {
+	account, ok := f.accounts[puuid]
+	if !ok {
+		return riot.RiotAccount{}, errRiotNotStubbed
+	}
+	return account, nil
+}
+
+func (f *fakeRiotAPI) FetchSummonerByPUUID(context.Context, string, string) (riot.SummonerProfile, error) {
+	if f.summoner == nil {
+		return riot.SummonerProfile{}, errRiotNotStubbed
+	}
+	return *f.summoner, nil
+}
+
+func (f *fakeRiotAPI) FetchLeagueEntriesByPUUID(context.Context, string, string) ([]riot.LeagueEntry, error) {
+	return f.leagueEntries, nil
+}
+
+func (f *fakeRiotAPI) FetchChampionRotation(context.Context, string) (riot.ChampionRotation, error) {
+	return riot.ChampionRotation{}, errRiotNotStubbed
+}
+
+func (f *fakeRiotAPI) FetchActiveGameBySummoner(context.Context, string, string) (riot.LiveGame, error) {
+	return riot.LiveGame{}, errRiotNotStubbed
+}
+
+func (f *fakeRiotAPI) FetchMatchByID(ctx context.Context, continent, matchID string) (riot.MatchDetail, error) {
+	if f.matchByID == nil {
+		return riot.MatchDetail{}, errRiotNotStubbed
+	}
+	return f.matchByID(ctx, continent, matchID)
+}
+
+func (f *fakeRiotAPI) FetchMatchIDsByPUUID(context.Context, string, string, riot.MatchIDsFilter) ([]string, error) {
+	return slices.Clone(f.matchIDs), nil
+}
+
+func (f *fakeRiotAPI) FetchTopChampionMasteries(context.Context, string, string, int) ([]riot.ChampionMastery, error) {
+	return nil, errRiotNotStubbed
+}
+
+func (f *fakeRiotAPI) FetchChampionMasteryScore(context.Context, string, string) (int, error) {
+	return 0, errRiotNotStubbed
+}
+
+func (f *fakeRiotAPI) RequestRate(endpoint string) float64 {
+	if rate, ok := f.requestRates[endpoint]; ok {
+		return rate
+	}
+	return f.requestRate
+}